@@ -0,0 +1,303 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/controller"
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/apis/externaldns"
+	"sigs.k8s.io/external-dns/pkg/migration"
+	"sigs.k8s.io/external-dns/pkg/zonefile"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+	"sigs.k8s.io/external-dns/provider/inmemory"
+	"sigs.k8s.io/external-dns/source"
+)
+
+// runCommand runs the inspection command of cfg against the controllers of the router, writing its output to w.
+func runCommand(ctx context.Context, cfg *externaldns.Config, router *controller.Router, w io.Writer) error {
+	switch cfg.Command {
+	case externaldns.CommandRecordsList:
+		return listRecords(ctx, router, w)
+	case externaldns.CommandPlan:
+		return printPlan(ctx, router, w)
+	case externaldns.CommandValidateConfig:
+		return validateProviders(ctx, router, w)
+	case externaldns.CommandZoneExport:
+		ownerID := ""
+		if cfg.ZoneExportOwnedOnly {
+			ownerID = cfg.TXTOwnerID
+		}
+		return exportZone(ctx, router, ownerID, w)
+	default:
+		return fmt.Errorf("unknown command: %s", cfg.Command)
+	}
+}
+
+// listRecords prints the records of every provider with the labels of their owner.
+func listRecords(ctx context.Context, router *controller.Router, w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROVIDER\tNAME\tTYPE\tTTL\tTARGETS\tOWNER\tRESOURCE")
+	for _, ctrl := range router.Controllers {
+		records, err := ctrl.Records(ctx)
+		if err != nil {
+			return fmt.Errorf("provider %s: %w", ctrl.ProviderName, err)
+		}
+		sortEndpoints(records)
+		for _, ep := range records {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", ctrl.ProviderName, ep.DNSName, ep.RecordType, formatTTL(ep.RecordTTL),
+				ep.Targets, ep.Labels[endpoint.OwnerLabelKey], ep.Labels[endpoint.ResourceLabelKey])
+		}
+	}
+	return tw.Flush()
+}
+
+// printPlan prints the changes the next synchronization of every provider would apply, including the
+// changes of the ownership records the registry applies on its own.
+func printPlan(ctx context.Context, router *controller.Router, w io.Writer) error {
+	for _, ctrl := range router.Controllers {
+		changes, err := ctrl.Plan(ctx)
+		if err != nil {
+			return fmt.Errorf("provider %s: %w", ctrl.ProviderName, err)
+		}
+		printChanges(w, ctrl.ProviderName, changes)
+		printRegistryChanges(w, ctrl.ProviderName, ctrl.PendingRegistryChanges())
+	}
+	return nil
+}
+
+// printRegistryChanges prints the changes of the ownership records of the provider called name, e.g.
+// the removal of orphaned records and the migration of records named after a legacy naming scheme.
+func printRegistryChanges(w io.Writer, name string, changes *plan.Changes) {
+	if !changes.HasChanges() {
+		return
+	}
+	fmt.Fprintf(w, "Registry of provider %s: %d ownership records to create, %d to delete\n",
+		name, len(changes.Create), len(changes.Delete))
+
+	sortEndpoints(changes.Create)
+	for _, ep := range changes.Create {
+		fmt.Fprintf(w, "  + %s\n", formatEndpoint(ep))
+	}
+	sortEndpoints(changes.Delete)
+	for _, ep := range changes.Delete {
+		fmt.Fprintf(w, "  - %s\n", formatEndpoint(ep))
+	}
+}
+
+// printChanges prints the changes of the provider called name.
+func printChanges(w io.Writer, name string, changes *plan.Changes) {
+	fmt.Fprintf(w, "Provider %s: %d to create, %d to update, %d to delete\n",
+		name, len(changes.Create), len(changes.UpdateNew), len(changes.Delete))
+
+	sortEndpoints(changes.Create)
+	for _, ep := range changes.Create {
+		fmt.Fprintf(w, "  + %s\n", formatEndpoint(ep))
+	}
+	for i, ep := range changes.UpdateNew {
+		fmt.Fprintf(w, "  ~ %s (was %s %s)\n", formatEndpoint(ep), formatTTL(changes.UpdateOld[i].RecordTTL), changes.UpdateOld[i].Targets)
+	}
+	sortEndpoints(changes.Delete)
+	for _, ep := range changes.Delete {
+		fmt.Fprintf(w, "  - %s\n", formatEndpoint(ep))
+	}
+}
+
+// simulate prints the changes the endpoints of the sources would make to the zones of the snapshot
+// of cfg, followed by the records of the zones after applying them to the in-memory provider.
+func simulate(ctx context.Context, cfg *externaldns.Config, endpointsSource source.Source, domainFilter endpoint.DomainFilter, w io.Writer) error {
+	policy, exists := plan.Policies[cfg.Policy]
+	if !exists {
+		return fmt.Errorf("unknown policy: %s", cfg.Policy)
+	}
+	if len(cfg.ProviderRoutes) > 0 || cfg.SplitHorizonPrivateProvider != "" {
+		log.Warn("The provider routes and the split-horizon private provider are ignored by the simulation")
+	}
+	snapshot, err := inmemory.ReadSnapshot(cfg.SimulateZoneSnapshot)
+	if err != nil {
+		return err
+	}
+	p := inmemory.NewInMemoryProvider(inmemory.InMemoryWithDomain(domainFilter))
+	if err := p.LoadSnapshot(ctx, snapshot); err != nil {
+		return err
+	}
+	ctrl, err := newController(cfg, "inmemory", p, policy, endpointsSource, domainFilter)
+	if err != nil {
+		return err
+	}
+
+	changes, err := ctrl.Plan(ctx)
+	if err != nil {
+		return err
+	}
+	printChanges(w, ctrl.ProviderName, changes)
+	if err := ctrl.Registry.ApplyChanges(ctx, changes); err != nil {
+		return fmt.Errorf("applying the changes to the zone snapshot: %w", err)
+	}
+
+	fmt.Fprintln(w)
+	return listRecords(ctx, &controller.Router{Controllers: []*controller.Controller{ctrl}}, w)
+}
+
+// exportZone prints the records of every provider as a zone file. When ownerID isn't empty, only the
+// records owned by ownerID according to the registry are printed.
+func exportZone(ctx context.Context, router *controller.Router, ownerID string, w io.Writer) error {
+	for _, ctrl := range router.Controllers {
+		records, err := ctrl.Records(ctx)
+		if err != nil {
+			return fmt.Errorf("provider %s: %w", ctrl.ProviderName, err)
+		}
+		if ownerID != "" {
+			owned := records[:0]
+			for _, ep := range records {
+				if ep.Labels[endpoint.OwnerLabelKey] == ownerID {
+					owned = append(owned, ep)
+				}
+			}
+			records = owned
+		}
+		fmt.Fprintf(w, "; provider %s\n", ctrl.ProviderName)
+		if err := zonefile.Write(w, records); err != nil {
+			return fmt.Errorf("provider %s: %w", ctrl.ProviderName, err)
+		}
+	}
+	return nil
+}
+
+// migrate copies the records owned by the owner ID from the provider of --provider to the provider of
+// --migrate-to-provider, printing the changes and the skipped records, and verifies the destination afterwards.
+func migrate(ctx context.Context, cfg *externaldns.Config, from provider.Provider, endpointsSource source.Source, domainFilter endpoint.DomainFilter, w io.Writer) error {
+	to, err := buildProvider(ctx, cfg, cfg.MigrateToProvider, domainFilter, endpointsSource)
+	if err != nil {
+		return fmt.Errorf("provider %s: %w", cfg.MigrateToProvider, err)
+	}
+	// The controllers only create the registries of both providers, no synchronization runs.
+	fromCtrl, err := newController(cfg, cfg.Provider, from, &plan.UpsertOnlyPolicy{}, endpointsSource, domainFilter)
+	if err != nil {
+		return err
+	}
+	toCtrl, err := newController(cfg, cfg.MigrateToProvider, to, &plan.UpsertOnlyPolicy{}, endpointsSource, domainFilter)
+	if err != nil {
+		return err
+	}
+
+	m := &migration.Migration{
+		From:               fromCtrl.Registry,
+		To:                 toCtrl.Registry,
+		DomainFilter:       domainFilter,
+		ManagedRecordTypes: cfg.ManagedDNSRecordTypes,
+		ExcludeRecordTypes: cfg.ExcludeDNSRecordTypes,
+		Translate:          migration.TranslatorFor(cfg.Provider, cfg.MigrateToProvider),
+		BatchSize:          cfg.MigrateBatchSize,
+		SetIdentifiers:     cfg.Provider == cfg.MigrateToProvider,
+	}
+	result, err := m.Plan(ctx)
+	if err != nil {
+		return err
+	}
+	printChanges(w, cfg.MigrateToProvider, result.Changes)
+	sort.SliceStable(result.Skipped, func(i, j int) bool {
+		return result.Skipped[i].Endpoint.DNSName < result.Skipped[j].Endpoint.DNSName
+	})
+	for _, skipped := range result.Skipped {
+		fmt.Fprintf(w, "  ! %s (skipped, %s)\n", formatEndpoint(skipped.Endpoint), skipped.Reason)
+	}
+	if cfg.MigrateDryRun {
+		return nil
+	}
+
+	if err := m.Apply(ctx, result); err != nil {
+		return fmt.Errorf("provider %s: %w", cfg.MigrateToProvider, err)
+	}
+	differences, err := m.Verify(ctx, result)
+	if err != nil {
+		return err
+	}
+	for _, difference := range differences {
+		fmt.Fprintf(w, "Verification: %s\n", difference)
+	}
+	if len(differences) > 0 {
+		return fmt.Errorf("verification failed, %d records differ between the providers", len(differences))
+	}
+	fmt.Fprintf(w, "Verified %d records in provider %s\n", len(result.Desired), cfg.MigrateToProvider)
+	return nil
+}
+
+// validateProviders reads the records of every provider to check its credentials, and calculates the plan
+// to check the sources and the registry. It reports the changes without applying them. The configuration
+// is already validated when the controllers are created.
+func validateProviders(ctx context.Context, router *controller.Router, w io.Writer) error {
+	var errs []error
+	for _, ctrl := range router.Controllers {
+		summary, err := validateProvider(ctx, ctrl)
+		if err != nil {
+			fmt.Fprintf(w, "Provider %s: %v\n", ctrl.ProviderName, err)
+			errs = append(errs, fmt.Errorf("provider %s: %w", ctrl.ProviderName, err))
+			continue
+		}
+		fmt.Fprintf(w, "Provider %s: OK, %s\n", ctrl.ProviderName, summary)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "Configuration is valid")
+	return nil
+}
+
+// validateProvider returns a summary of the records and of the pending changes of the controller.
+func validateProvider(ctx context.Context, ctrl *controller.Controller) (string, error) {
+	records, err := ctrl.Records(ctx)
+	if err != nil {
+		return "", err
+	}
+	changes, err := ctrl.Plan(ctx)
+	if err != nil {
+		return "", err
+	}
+	pending := ctrl.PendingRegistryChanges()
+	return fmt.Sprintf("%d records, %d changes to apply, %d ownership records to change", len(records),
+		len(changes.Create)+len(changes.UpdateNew)+len(changes.Delete), len(pending.Create)+len(pending.Delete)), nil
+}
+
+func sortEndpoints(endpoints []*endpoint.Endpoint) {
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		if endpoints[i].DNSName != endpoints[j].DNSName {
+			return endpoints[i].DNSName < endpoints[j].DNSName
+		}
+		return endpoints[i].RecordType < endpoints[j].RecordType
+	})
+}
+
+func formatEndpoint(ep *endpoint.Endpoint) string {
+	return fmt.Sprintf("%s %s %s %s", ep.DNSName, ep.RecordType, formatTTL(ep.RecordTTL), ep.Targets)
+}
+
+func formatTTL(ttl endpoint.TTL) string {
+	if !ttl.IsConfigured() {
+		return "-"
+	}
+	return fmt.Sprintf("%d", ttl)
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/controller"
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/apis/externaldns"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+	"sigs.k8s.io/external-dns/provider/inmemory"
+)
+
+// testSource returns copies of its endpoints.
+type testSource []*endpoint.Endpoint
+
+func (s testSource) Endpoints(context.Context) ([]*endpoint.Endpoint, error) {
+	endpoints := make([]*endpoint.Endpoint, 0, len(s))
+	for _, ep := range s {
+		endpoints = append(endpoints, ep.DeepCopy())
+	}
+	return endpoints, nil
+}
+
+func (s testSource) AddEventHandler(context.Context, func()) {}
+
+// failingProvider fails to read its records.
+type failingProvider struct {
+	provider.BaseProvider
+}
+
+func (failingProvider) Records(context.Context) ([]*endpoint.Endpoint, error) {
+	return nil, errors.New("invalid credentials")
+}
+
+func (failingProvider) ApplyChanges(context.Context, *plan.Changes) error {
+	return nil
+}
+
+var commandsTestSource = testSource{
+	endpoint.NewEndpoint("www.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+	endpoint.NewEndpoint("shop.example.com", endpoint.RecordTypeCNAME, "lb.example.net"),
+}
+
+func newCommandsTestConfig(t *testing.T) *externaldns.Config {
+	cfg := externaldns.NewConfig()
+	require.NoError(t, cfg.ParseFlags([]string{
+		"--source=service",
+		"--provider=inmemory",
+		"--inmemory-zone=example.com",
+		"--registry=txt",
+		"--txt-owner-id=owner",
+		"--txt-garbage-collect",
+		"--managed-record-types=A",
+		"--managed-record-types=CNAME",
+	}))
+	return cfg
+}
+
+// newCommandsTestProvider returns an in-memory provider with the record of www.example.com owned by
+// owner, the orphaned ownership record of gone.example.com and the unowned record of mail.example.com.
+func newCommandsTestProvider(t *testing.T) *inmemory.InMemoryProvider {
+	p := inmemory.NewInMemoryProvider(inmemory.InMemoryInitZones([]string{"example.com"}))
+	require.NoError(t, p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("www.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+			endpoint.NewEndpoint("www.example.com", endpoint.RecordTypeTXT, "\"heritage=external-dns,external-dns/owner=owner\""),
+			endpoint.NewEndpoint("a-www.example.com", endpoint.RecordTypeTXT, "\"heritage=external-dns,external-dns/owner=owner\""),
+			endpoint.NewEndpoint("a-gone.example.com", endpoint.RecordTypeTXT, "\"heritage=external-dns,external-dns/owner=owner\""),
+			endpoint.NewEndpoint("mail.example.com", endpoint.RecordTypeA, "5.6.7.8"),
+		},
+	}))
+	return p
+}
+
+func newCommandsTestRouter(t *testing.T, cfg *externaldns.Config, providers map[string]provider.Provider) *controller.Router {
+	router := &controller.Router{}
+	for _, name := range []string{"inmemory", "broken"} {
+		p, ok := providers[name]
+		if !ok {
+			continue
+		}
+		ctrl, err := newController(cfg, name, p, plan.Policies[cfg.Policy], commandsTestSource, endpoint.NewDomainFilter(nil))
+		require.NoError(t, err)
+		router.Controllers = append(router.Controllers, ctrl)
+	}
+	return router
+}
+
+func TestRunCommand(t *testing.T) {
+	for _, tc := range []struct {
+		title          string
+		command        string
+		ownedOnly      bool
+		broken         bool
+		expectedOutput string
+		expectedErr    string
+	}{
+		{
+			title:   "records list",
+			command: externaldns.CommandRecordsList,
+			expectedOutput: `PROVIDER  NAME              TYPE  TTL  TARGETS  OWNER  RESOURCE
+inmemory  mail.example.com  A     -    5.6.7.8         
+inmemory  www.example.com   A     -    1.2.3.4  owner  
+`,
+		},
+		{
+			title:   "plan with the orphaned ownership records",
+			command: externaldns.CommandPlan,
+			expectedOutput: `Provider inmemory: 1 to create, 0 to update, 1 to delete
+  + shop.example.com CNAME - lb.example.net
+  - a-gone.example.com TXT - "heritage=external-dns,external-dns/owner=owner"
+`,
+		},
+		{
+			title:   "validate-config",
+			command: externaldns.CommandValidateConfig,
+			expectedOutput: `Provider inmemory: OK, 2 records, 2 changes to apply, 0 ownership records to change
+Configuration is valid
+`,
+		},
+		{
+			title:       "validate-config with a failing provider",
+			command:     externaldns.CommandValidateConfig,
+			broken:      true,
+			expectedErr: "provider broken: invalid credentials",
+			expectedOutput: `Provider inmemory: OK, 2 records, 2 changes to apply, 0 ownership records to change
+Provider broken: invalid credentials
+`,
+		},
+		{
+			title:     "zone export of the owned records",
+			command:   externaldns.CommandZoneExport,
+			ownedOnly: true,
+			expectedOutput: `; provider inmemory
+$TTL 300
+www.example.com.	300	IN	A	1.2.3.4
+`,
+		},
+		{
+			title:       "unknown command",
+			command:     "records delete",
+			expectedErr: "unknown command: records delete",
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			cfg := newCommandsTestConfig(t)
+			cfg.Command = tc.command
+			cfg.ZoneExportOwnedOnly = tc.ownedOnly
+			providers := map[string]provider.Provider{"inmemory": newCommandsTestProvider(t)}
+			if tc.broken {
+				providers["broken"] = failingProvider{}
+			}
+			router := newCommandsTestRouter(t, cfg, providers)
+
+			var output bytes.Buffer
+			err := runCommand(context.Background(), cfg, router, &output)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedOutput, output.String())
+		})
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zones.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`zones:
+- example.com
+records:
+- dnsName: www.example.com
+  recordType: A
+  targets: [1.2.3.4]
+`), 0o644))
+	cfg := newCommandsTestConfig(t)
+	cfg.SimulateZoneSnapshot = path
+
+	var output bytes.Buffer
+	require.NoError(t, simulate(context.Background(), cfg, commandsTestSource, endpoint.NewDomainFilter(nil), &output))
+	assert.Equal(t, `Provider inmemory: 1 to create, 0 to update, 0 to delete
+  + shop.example.com CNAME - lb.example.net
+
+PROVIDER  NAME              TYPE   TTL  TARGETS         OWNER  RESOURCE
+inmemory  shop.example.com  CNAME  -    lb.example.net  owner  
+inmemory  www.example.com   A      -    1.2.3.4                
+`, output.String())
+}
+
+func TestMigrate(t *testing.T) {
+	for _, tc := range []struct {
+		title          string
+		dryRun         bool
+		expectedOutput string
+	}{
+		{
+			title:  "dry run",
+			dryRun: true,
+			expectedOutput: `Provider inmemory: 1 to create, 0 to update, 0 to delete
+  + www.example.com A - 1.2.3.4
+  ! mail.example.com A - 5.6.7.8 (skipped, not owned by ExternalDNS)
+`,
+		},
+		{
+			title: "migration",
+			expectedOutput: `Provider inmemory: 1 to create, 0 to update, 0 to delete
+  + www.example.com A - 1.2.3.4
+  ! mail.example.com A - 5.6.7.8 (skipped, not owned by ExternalDNS)
+Verified 1 records in provider inmemory
+`,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			cfg := newCommandsTestConfig(t)
+			cfg.MigrateToProvider = "inmemory"
+			cfg.MigrateDryRun = tc.dryRun
+
+			var output bytes.Buffer
+			require.NoError(t, migrate(context.Background(), cfg, newCommandsTestProvider(t), commandsTestSource, endpoint.NewDomainFilter(nil), &output))
+			assert.Equal(t, tc.expectedOutput, output.String())
+		})
+	}
+}
@@ -25,8 +25,11 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/tracing"
 	"sigs.k8s.io/external-dns/plan"
 	"sigs.k8s.io/external-dns/provider"
 	"sigs.k8s.io/external-dns/registry"
@@ -152,6 +155,77 @@ var (
 			Help:      "Number of DNS AAAA-records that exists both in source and registry.",
 		},
 	)
+	providerRegistryEndpoints = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "external_dns",
+			Subsystem: "controller",
+			Name:      "provider_registry_endpoints",
+			Help:      "Number of Endpoints in the registry of each provider.",
+		},
+		[]string{"provider"},
+	)
+	providerErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "external_dns",
+			Subsystem: "controller",
+			Name:      "provider_errors_total",
+			Help:      "Number of Registry errors of each provider.",
+		},
+		[]string{"provider"},
+	)
+	providerChangesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "external_dns",
+			Subsystem: "controller",
+			Name:      "provider_changes_total",
+			Help:      "Number of DNS record changes applied by each provider.",
+		},
+		[]string{"provider", "type"},
+	)
+	providerLastSyncTimestamp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "external_dns",
+			Subsystem: "controller",
+			Name:      "provider_last_sync_timestamp_seconds",
+			Help:      "Timestamp of last successful sync with each DNS provider",
+		},
+		[]string{"provider"},
+	)
+	consecutiveFailures = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "external_dns",
+			Subsystem: "controller",
+			Name:      "consecutive_failures",
+			Help:      "Number of synchronizations with each provider failed in a row, by class of the last error.",
+		},
+		[]string{"provider", "class"},
+	)
+	backoffSeconds = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "external_dns",
+			Subsystem: "controller",
+			Name:      "backoff_seconds",
+			Help:      "Current delay before the next synchronization with each provider after failures, by class of the last error.",
+		},
+		[]string{"provider", "class"},
+	)
+	circuitBreakerOpen = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "external_dns",
+			Subsystem: "controller",
+			Name:      "circuit_breaker_open",
+			Help:      "Whether synchronizations with each provider are paused after too many failures (1) or not (0).",
+		},
+		[]string{"provider"},
+	)
+	controllerLeader = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "external_dns",
+			Subsystem: "controller",
+			Name:      "leader",
+			Help:      "Whether this replica holds the leader election lease (1) or is a standby (0).",
+		},
+	)
 )
 
 func init() {
@@ -170,6 +244,14 @@ func init() {
 	prometheus.MustRegister(sourceAAAARecords)
 	prometheus.MustRegister(verifiedARecords)
 	prometheus.MustRegister(verifiedAAAARecords)
+	prometheus.MustRegister(providerRegistryEndpoints)
+	prometheus.MustRegister(providerErrorsTotal)
+	prometheus.MustRegister(providerChangesTotal)
+	prometheus.MustRegister(providerLastSyncTimestamp)
+	prometheus.MustRegister(consecutiveFailures)
+	prometheus.MustRegister(backoffSeconds)
+	prometheus.MustRegister(circuitBreakerOpen)
+	prometheus.MustRegister(controllerLeader)
 }
 
 // Controller is responsible for orchestrating the different components.
@@ -183,6 +265,8 @@ type Controller struct {
 	Registry registry.Registry
 	// The policy that defines which changes to DNS records are allowed
 	Policy plan.Policy
+	// The ConflictResolver decides which resources acquire the DNS names they claim together, PerResource if nil
+	ConflictResolver plan.ConflictResolver
 	// The interval between individual synchronizations
 	Interval time.Duration
 	// The DomainFilter defines which DNS records to keep or exclude
@@ -197,32 +281,81 @@ type Controller struct {
 	ManagedRecordTypes []string
 	// ExcludeRecordTypes are DNS record types that will be excluded from management.
 	ExcludeRecordTypes []string
+	// PropertyComparisons define how the provider specific properties are compared, by their name
+	PropertyComparisons map[string]plan.PropertyComparison
+	// IgnoredProperties are the names of the provider specific properties never causing an update
+	IgnoredProperties []string
+	// TTLPolicy normalizes the TTLs of the endpoints of the source before planning, if not nil
+	TTLPolicy *plan.TTLPolicy
 	// MinEventSyncInterval is used as window for batching events
 	MinEventSyncInterval time.Duration
+	// ProviderName labels the per provider metrics of this controller
+	ProviderName string
+	// MaxChangesPerApply splits the changes into dependency ordered batches of at most this many changes, 0 for no limit
+	MaxChangesPerApply int
+	// MaxBackoff is the maximum delay between synchronizations failing in a row, 0 disables the backoff
+	MaxBackoff time.Duration
+	// CircuitBreakerThreshold is the number of failures in a row pausing synchronizations, 0 disables the circuit breaker
+	CircuitBreakerThreshold int
+	// CircuitBreakerCooldown is the time synchronizations are paused while the circuit breaker is open
+	CircuitBreakerCooldown time.Duration
+	// The failures counts the synchronizations failed in a row, protected by runAtMutex
+	failures int
+	// The hardFailure opens the circuit breaker until the next successful synchronization, protected by runAtMutex
+	hardFailure bool
+	// The backoffUntil delays all synchronizations after failures, protected by runAtMutex
+	backoffUntil time.Time
+	// The state holds the snapshot of the last synchronization
+	state syncState
+	// FullResyncInterval enables incremental synchronizations of the resources reported by ResourceChanged,
+	// with a full synchronization at least once per interval. 0 disables incremental synchronizations.
+	FullResyncInterval time.Duration
+	// The incremental holds the state of the previous synchronization used by incremental synchronizations
+	incremental incrementalState
+	// The pendingSettings are the settings changed by Reconfigure since the last synchronization, protected by runAtMutex
+	pendingSettings *Settings
 }
 
 // RunOnce runs a single iteration of a reconciliation loop.
 func (c *Controller) RunOnce(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "Controller.RunOnce", attribute.String("external_dns.provider", c.ProviderName))
+	err := c.runOnce(ctx)
+	tracing.End(span, err)
+	return err
+}
+
+func (c *Controller) runOnce(ctx context.Context) error {
 	lastReconcileTimestamp.SetToCurrentTime()
 
 	c.runAtMutex.Lock()
 	c.lastRunAt = time.Now()
 	c.runAtMutex.Unlock()
 
-	records, err := c.Registry.Records(ctx)
+	reconfigured := c.applyPendingSettings()
+	if c.FullResyncInterval > 0 && !reconfigured && !registryHasPendingChanges(c.Registry) {
+		if sync, ok := c.incremental.take(time.Now(), c.FullResyncInterval); ok {
+			return c.runIncremental(ctx, sync)
+		}
+	}
+	c.incremental.invalidate()
+
+	records, err := c.records(ctx)
 	if err != nil {
 		registryErrorsTotal.Inc()
 		deprecatedRegistryErrors.Inc()
+		providerErrorsTotal.WithLabelValues(c.ProviderName).Inc()
 		return err
 	}
 
+	c.state.setRecords(records)
 	registryEndpointsTotal.Set(float64(len(records)))
+	providerRegistryEndpoints.WithLabelValues(c.ProviderName).Set(float64(len(records)))
 	regARecords, regAAAARecords := countAddressRecords(records)
 	registryARecords.Set(float64(regARecords))
 	registryAAAARecords.Set(float64(regAAAARecords))
 	ctx = context.WithValue(ctx, provider.RecordsContextKey, records)
 
-	endpoints, err := c.Source.Endpoints(ctx)
+	endpoints, err := c.endpoints(ctx)
 	if err != nil {
 		sourceErrorsTotal.Inc()
 		deprecatedSourceErrors.Inc()
@@ -239,37 +372,183 @@ func (c *Controller) RunOnce(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("adjusting endpoints: %w", err)
 	}
-	registryFilter := c.Registry.GetDomainFilter()
+	c.state.setDesired(endpoints)
+	var desired map[string][]*endpoint.Endpoint
+	if c.FullResyncInterval > 0 {
+		desired = endpointsByResource(endpoints)
+	}
+
+	plan := c.calculatePlan(ctx, records, endpoints)
+	if err := c.applyChanges(ctx, plan.Changes); err != nil {
+		return err
+	}
+
+	if c.FullResyncInterval > 0 {
+		c.incremental.synced(time.Now(), true, applyChangesToRecords(records, plan.Changes), desired)
+	}
+	c.synced()
+
+	return nil
+}
+
+// Records returns the records of the registry, with the labels of their owner.
+func (c *Controller) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	return c.records(ctx)
+}
+
+// Plan calculates the changes the next synchronization would apply, without applying them.
+func (c *Controller) Plan(ctx context.Context) (*plan.Changes, error) {
+	records, err := c.records(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, provider.RecordsContextKey, records)
+
+	endpoints, err := c.endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	endpoints, err = c.Registry.AdjustEndpoints(endpoints)
+	if err != nil {
+		return nil, fmt.Errorf("adjusting endpoints: %w", err)
+	}
+	return c.calculatePlan(ctx, records, endpoints).Changes, nil
+}
+
+// records reads the records of the registry.
+func (c *Controller) records(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	ctx, span := tracing.Start(ctx, "Registry.Records")
+	records, err := c.Registry.Records(ctx)
+	span.SetAttributes(attribute.Int("external_dns.records", len(records)))
+	tracing.End(span, err)
+	return records, err
+}
+
+// endpoints reads the endpoints of the source, with the TTLs of the TTL policy.
+func (c *Controller) endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	ctx, span := tracing.Start(ctx, "Source.Endpoints")
+	endpoints, err := c.Source.Endpoints(ctx)
+	span.SetAttributes(attribute.Int("external_dns.endpoints", len(endpoints)))
+	tracing.End(span, err)
+	if err != nil {
+		return nil, err
+	}
+	return c.TTLPolicy.Apply(endpoints), nil
+}
+
+// calculatePlan calculates the changes moving the records towards the endpoints.
+func (c *Controller) calculatePlan(ctx context.Context, records, endpoints []*endpoint.Endpoint) *plan.Plan {
+	_, span := tracing.Start(ctx, "Plan.Calculate")
+	defer span.End()
 
 	plan := &plan.Plan{
-		Policies:       []plan.Policy{c.Policy},
-		Current:        records,
-		Desired:        endpoints,
-		DomainFilter:   endpoint.MatchAllDomainFilters{c.DomainFilter, registryFilter},
-		ManagedRecords: c.ManagedRecordTypes,
-		ExcludeRecords: c.ExcludeRecordTypes,
-		OwnerID:        c.Registry.OwnerID(),
+		Policies:            []plan.Policy{c.Policy},
+		Current:             records,
+		Desired:             endpoints,
+		DomainFilter:        endpoint.MatchAllDomainFilters{c.DomainFilter, c.Registry.GetDomainFilter()},
+		ManagedRecords:      c.ManagedRecordTypes,
+		ExcludeRecords:      c.ExcludeRecordTypes,
+		OwnerID:             c.Registry.OwnerID(),
+		Resolver:            c.ConflictResolver,
+		PropertyComparisons: c.PropertyComparisons,
+		IgnoredProperties:   c.IgnoredProperties,
+		OrphanedRecords:     c.orphanedRecords(),
 	}
 
 	plan = plan.Calculate()
+	c.state.setChanges(plan.Changes)
+	span.SetAttributes(changesAttributes(plan.Changes)...)
+	return plan
+}
+
+// applyChanges applies the changes to the registry, if there are any.
+func (c *Controller) applyChanges(ctx context.Context, changes *plan.Changes) error {
+	if !changes.HasChanges() && !registryHasPendingChanges(c.Registry) {
+		controllerNoChangesTotal.Inc()
+		log.Info("All records are already up to date")
+		return nil
+	}
 
-	if plan.Changes.HasChanges() {
-		err = c.Registry.ApplyChanges(ctx, plan.Changes)
+	batches := changes.Batches(c.MaxChangesPerApply)
+	if len(batches) == 0 {
+		// let the registry flush its pending changes
+		batches = []*plan.Changes{changes}
+	}
+	for i, batch := range batches {
+		if len(batches) > 1 {
+			log.Debugf("Applying batch %d of %d with %d changes", i+1, len(batches), len(batch.Create)+len(batch.UpdateNew)+len(batch.Delete))
+		}
+		applyCtx, span := tracing.Start(ctx, "Registry.ApplyChanges", changesAttributes(batch)...)
+		err := c.Registry.ApplyChanges(applyCtx, batch)
+		tracing.End(span, err)
 		if err != nil {
 			registryErrorsTotal.Inc()
 			deprecatedRegistryErrors.Inc()
+			providerErrorsTotal.WithLabelValues(c.ProviderName).Inc()
 			return err
 		}
-	} else {
-		controllerNoChangesTotal.Inc()
-		log.Info("All records are already up to date")
+		providerChangesTotal.WithLabelValues(c.ProviderName, "create").Add(float64(len(batch.Create)))
+		providerChangesTotal.WithLabelValues(c.ProviderName, "update").Add(float64(len(batch.UpdateNew)))
+		providerChangesTotal.WithLabelValues(c.ProviderName, "delete").Add(float64(len(batch.Delete)))
 	}
+	return nil
+}
+
+func changesAttributes(changes *plan.Changes) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.Int("external_dns.changes.create", len(changes.Create)),
+		attribute.Int("external_dns.changes.update", len(changes.UpdateNew)),
+		attribute.Int("external_dns.changes.delete", len(changes.Delete)),
+	}
+}
 
+// synced records a successful synchronization.
+func (c *Controller) synced() {
+	c.state.setSuccess(time.Now())
 	lastSyncTimestamp.SetToCurrentTime()
+	providerLastSyncTimestamp.WithLabelValues(c.ProviderName).SetToCurrentTime()
+}
+
+// pendingChangesRegistry is implemented by registries which may have changes of their own
+// to apply, e.g. the migration of ownership records, even when the plan is empty.
+type pendingChangesRegistry interface {
+	HasPendingChanges() bool
+}
 
+func registryHasPendingChanges(r registry.Registry) bool {
+	if pr, ok := r.(pendingChangesRegistry); ok {
+		return pr.HasPendingChanges()
+	}
+	return false
+}
+
+// orphanedRecordsLister is implemented by registries which find ownership records whose records are gone.
+type orphanedRecordsLister interface {
+	OrphanedRecords() []*endpoint.Endpoint
+}
+
+// orphanedRecords returns the orphaned ownership records of the registry, as found by the last read of the records.
+func (c *Controller) orphanedRecords() []*endpoint.Endpoint {
+	if lister, ok := c.Registry.(orphanedRecordsLister); ok {
+		return lister.OrphanedRecords()
+	}
 	return nil
 }
 
+// pendingChangesLister is implemented by registries which can list their pending changes.
+type pendingChangesLister interface {
+	PendingChanges() *plan.Changes
+}
+
+// PendingRegistryChanges returns the changes the registry adds to the next changes on its own, e.g. the
+// migration of ownership records, as found by the last read of the records.
+func (c *Controller) PendingRegistryChanges() *plan.Changes {
+	if lister, ok := c.Registry.(pendingChangesLister); ok {
+		return lister.PendingChanges()
+	}
+	return &plan.Changes{}
+}
+
 func earliest(r time.Time, times ...time.Time) time.Time {
 	for _, t := range times {
 		if t.Before(r) {
@@ -338,6 +617,7 @@ func (c *Controller) ScheduleRunOnce(now time.Time) {
 			now.Add(5*time.Second),
 			c.nextRunAt,
 		),
+		c.backoffUntil,
 	)
 }
 
@@ -357,13 +637,8 @@ func (c *Controller) Run(ctx context.Context) {
 	defer ticker.Stop()
 	for {
 		if c.ShouldRunOnce(time.Now()) {
-			if err := c.RunOnce(ctx); err != nil {
-				if errors.Is(err, provider.SoftError) {
-					log.Errorf("Failed to do run once: %v", err)
-				} else {
-					log.Fatalf("Failed to do run once: %v", err)
-				}
-			}
+			err := c.RunOnce(ctx)
+			c.handleRunResult(time.Now(), err, errorClass(err))
 		}
 		select {
 		case <-ticker.C:
@@ -373,3 +648,97 @@ func (c *Controller) Run(ctx context.Context) {
 		}
 	}
 }
+
+// The classes of the errors of failed synchronizations.
+const (
+	// softErrorClass is a transient error reported as provider.SoftError, retried after a backoff
+	softErrorClass = "soft"
+	// hardErrorClass is any other error, stopping ExternalDNS unless the circuit breaker is enabled
+	hardErrorClass = "hard"
+)
+
+var errorClasses = []string{softErrorClass, hardErrorClass}
+
+// errorClass returns the class of the error of a failed synchronization.
+func errorClass(err error) string {
+	if errors.Is(err, provider.SoftError) {
+		return softErrorClass
+	}
+	return hardErrorClass
+}
+
+// handleRunResult delays the next synchronization after a failed one. The delay grows exponentially
+// with the failures in a row, and the circuit breaker pauses synchronizations after too many of them.
+// A hard error opens the circuit breaker at once, or stops ExternalDNS when it is disabled.
+func (c *Controller) handleRunResult(now time.Time, err error, class string) {
+	c.runAtMutex.Lock()
+	defer c.runAtMutex.Unlock()
+
+	if err == nil {
+		if c.circuitBreakerOpen() {
+			log.Infof("Circuit breaker closed after %d failures", c.failures)
+		}
+		c.failures = 0
+		c.hardFailure = false
+		c.backoffUntil = time.Time{}
+		for _, errClass := range errorClasses {
+			consecutiveFailures.WithLabelValues(c.ProviderName, errClass).Set(0)
+			backoffSeconds.WithLabelValues(c.ProviderName, errClass).Set(0)
+		}
+		circuitBreakerOpen.WithLabelValues(c.ProviderName).Set(0)
+		return
+	}
+
+	if class == hardErrorClass && c.CircuitBreakerThreshold <= 0 {
+		log.Fatalf("Failed to do run once: %v", err)
+		return
+	}
+	log.Errorf("Failed to do run once: %v", err)
+
+	wasOpen := c.circuitBreakerOpen()
+	c.failures++
+	c.hardFailure = c.hardFailure || class == hardErrorClass
+	delay := c.backoff()
+	if c.circuitBreakerOpen() {
+		if !wasOpen {
+			log.Warnf("Circuit breaker opened after %d failures, pausing synchronizations for %s", c.failures, c.CircuitBreakerCooldown)
+		}
+		if delay < c.CircuitBreakerCooldown {
+			delay = c.CircuitBreakerCooldown
+		}
+		circuitBreakerOpen.WithLabelValues(c.ProviderName).Set(1)
+	}
+	log.Infof("Next synchronization in %s after %d failures", delay.Round(time.Second), c.failures)
+
+	c.backoffUntil = now.Add(delay)
+	c.nextRunAt = c.backoffUntil
+	for _, errClass := range errorClasses {
+		if errClass != class {
+			consecutiveFailures.WithLabelValues(c.ProviderName, errClass).Set(0)
+			backoffSeconds.WithLabelValues(c.ProviderName, errClass).Set(0)
+		}
+	}
+	consecutiveFailures.WithLabelValues(c.ProviderName, class).Set(float64(c.failures))
+	backoffSeconds.WithLabelValues(c.ProviderName, class).Set(delay.Seconds())
+}
+
+// backoff returns the delay after the failures in a row: the interval doubled for every
+// failure after the first one, with up to 10% of jitter, up to MaxBackoff.
+func (c *Controller) backoff() time.Duration {
+	delay := c.Interval
+	if c.MaxBackoff <= 0 {
+		return delay
+	}
+	for i := 1; i < c.failures && delay < c.MaxBackoff; i++ {
+		delay *= 2
+	}
+	delay = wait.Jitter(delay, 0.1)
+	if delay > c.MaxBackoff {
+		delay = c.MaxBackoff
+	}
+	return delay
+}
+
+func (c *Controller) circuitBreakerOpen() bool {
+	return c.CircuitBreakerThreshold > 0 && (c.hardFailure || c.failures >= c.CircuitBreakerThreshold)
+}
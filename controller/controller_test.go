@@ -19,20 +19,30 @@ package controller
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
 	"reflect"
+	"slices"
 	"sort"
 	"testing"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	"sigs.k8s.io/external-dns/endpoint"
 	"sigs.k8s.io/external-dns/internal/testutils"
 	"sigs.k8s.io/external-dns/pkg/apis/externaldns"
 	"sigs.k8s.io/external-dns/plan"
 	"sigs.k8s.io/external-dns/provider"
+	"sigs.k8s.io/external-dns/provider/inmemory"
 	"sigs.k8s.io/external-dns/registry"
+	"sigs.k8s.io/external-dns/source"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -238,6 +248,38 @@ func TestRunOnce(t *testing.T) {
 	assert.Equal(t, math.Float64bits(1), valueFromMetric(verifiedAAAARecords))
 }
 
+// TestPlan tests that Plan calculates the changes without applying them.
+func TestPlan(t *testing.T) {
+	src := &resourceSource{hosts: map[string]map[string]string{
+		"ingress/default/web": {"www.example.com": "1.2.3.4"},
+	}}
+	p := inmemory.NewInMemoryProvider(inmemory.InMemoryInitZones([]string{"example.com"}))
+	require.NoError(t, p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{endpoint.NewEndpoint("old.example.com", endpoint.RecordTypeA, "5.6.7.8")},
+	}))
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:             src,
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+	}
+
+	changes, err := ctrl.Plan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, changes.Create, 1)
+	assert.Equal(t, "www.example.com", changes.Create[0].DNSName)
+	require.Len(t, changes.Delete, 1)
+	assert.Equal(t, "old.example.com", changes.Delete[0].DNSName)
+
+	records, err := ctrl.Records(context.Background())
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "old.example.com", records[0].DNSName, "the changes are not applied")
+}
+
 // TestRun tests that Run correctly starts and stops
 func TestRun(t *testing.T) {
 	source := getTestSource()
@@ -272,6 +314,36 @@ func TestRun(t *testing.T) {
 	assert.Equal(t, math.Float64bits(1), valueFromMetric(verifiedAAAARecords))
 }
 
+// TestRunBacksOffAfterErrors tests that Run keeps running after a soft error and delays the next synchronization.
+func TestRunBacksOffAfterErrors(t *testing.T) {
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return([]*endpoint.Endpoint(nil), provider.NewSoftError(errors.New("listing services")))
+	r, err := registry.NewNoopRegistry(getTestProvider())
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:             source,
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		Interval:           time.Minute,
+		MaxBackoff:         5 * time.Minute,
+		ProviderName:       "run-errors",
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		ctrl.Run(ctx)
+		close(stopped)
+	}()
+	time.Sleep(1500 * time.Millisecond)
+	cancel()
+	<-stopped
+
+	source.AssertNumberOfCalls(t, "Endpoints", 1)
+	assert.Equal(t, 1.0, testutil.ToFloat64(consecutiveFailures.WithLabelValues("run-errors", softErrorClass)))
+}
+
 func valueFromMetric(metric prometheus.Gauge) uint64 {
 	ref := reflect.ValueOf(metric)
 	return reflect.Indirect(ref).FieldByName("valBits").Uint()
@@ -334,6 +406,129 @@ func TestShouldRunOnce(t *testing.T) {
 	assert.True(t, ctrl.ShouldRunOnce(now))
 }
 
+func TestBackoffAfterFailures(t *testing.T) {
+	ctrl := &Controller{Interval: time.Minute, MinEventSyncInterval: 5 * time.Second, MaxBackoff: 5 * time.Minute, ProviderName: "backoff"}
+	softErr := fmt.Errorf("listing records: %w", provider.SoftError)
+
+	now := time.Now()
+	for _, expected := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute} {
+		ctrl.handleRunResult(now, softErr, softErrorClass)
+		delay := ctrl.nextRunAt.Sub(now)
+		assert.GreaterOrEqual(t, delay, expected)
+		assert.LessOrEqual(t, delay, min(expected+expected/10, ctrl.MaxBackoff))
+	}
+	assert.Equal(t, 5.0, testutil.ToFloat64(consecutiveFailures.WithLabelValues("backoff", softErrorClass)))
+	assert.Equal(t, 0.0, testutil.ToFloat64(consecutiveFailures.WithLabelValues("backoff", hardErrorClass)))
+	assert.Equal(t, 0.0, testutil.ToFloat64(circuitBreakerOpen.WithLabelValues("backoff")))
+
+	// Events do not shorten the backoff.
+	ctrl.ScheduleRunOnce(now)
+	assert.False(t, ctrl.ShouldRunOnce(now.Add(time.Minute)))
+
+	// A successful synchronization resets the backoff.
+	ctrl.handleRunResult(now, nil, "")
+	ctrl.ScheduleRunOnce(now)
+	assert.True(t, ctrl.ShouldRunOnce(now.Add(5*time.Second)))
+	assert.Equal(t, 0.0, testutil.ToFloat64(consecutiveFailures.WithLabelValues("backoff", softErrorClass)))
+	assert.Equal(t, 0.0, testutil.ToFloat64(backoffSeconds.WithLabelValues("backoff", softErrorClass)))
+}
+
+func TestBackoffDisabled(t *testing.T) {
+	ctrl := &Controller{Interval: time.Minute}
+
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		ctrl.handleRunResult(now, provider.SoftError, softErrorClass)
+		assert.Equal(t, now.Add(time.Minute), ctrl.nextRunAt)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	ctrl := &Controller{
+		Interval:                time.Minute,
+		MaxBackoff:              2 * time.Minute,
+		CircuitBreakerThreshold: 3,
+		CircuitBreakerCooldown:  15 * time.Minute,
+		ProviderName:            "breaker",
+	}
+
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		ctrl.handleRunResult(now, provider.SoftError, softErrorClass)
+		assert.Less(t, ctrl.nextRunAt.Sub(now), 15*time.Minute)
+	}
+	assert.Equal(t, 0.0, testutil.ToFloat64(circuitBreakerOpen.WithLabelValues("breaker")))
+
+	// The circuit breaker opens and stays open while the half-open synchronizations fail.
+	for i := 0; i < 2; i++ {
+		ctrl.handleRunResult(now, provider.SoftError, softErrorClass)
+		assert.Equal(t, now.Add(15*time.Minute), ctrl.nextRunAt)
+		assert.Equal(t, 1.0, testutil.ToFloat64(circuitBreakerOpen.WithLabelValues("breaker")))
+	}
+	assert.False(t, ctrl.ShouldRunOnce(now.Add(14*time.Minute)))
+	assert.True(t, ctrl.ShouldRunOnce(now.Add(15*time.Minute)))
+
+	ctrl.handleRunResult(now, nil, "")
+	assert.Equal(t, 0.0, testutil.ToFloat64(circuitBreakerOpen.WithLabelValues("breaker")))
+	assert.Equal(t, 0, ctrl.failures)
+}
+
+func TestErrorClass(t *testing.T) {
+	assert.Equal(t, softErrorClass, errorClass(provider.SoftError))
+	assert.Equal(t, softErrorClass, errorClass(provider.NewSoftError(errors.New("throttled"))))
+	assert.Equal(t, softErrorClass, errorClass(fmt.Errorf("listing records: %w", provider.SoftError)))
+	assert.Equal(t, hardErrorClass, errorClass(errors.New("invalid credentials")))
+}
+
+func TestHardErrorOpensCircuitBreaker(t *testing.T) {
+	ctrl := &Controller{
+		Interval:                time.Minute,
+		MaxBackoff:              2 * time.Minute,
+		CircuitBreakerThreshold: 3,
+		CircuitBreakerCooldown:  15 * time.Minute,
+		ProviderName:            "hard-breaker",
+	}
+	hardErr := errors.New("invalid credentials")
+
+	now := time.Now()
+	ctrl.handleRunResult(now, provider.SoftError, softErrorClass)
+	assert.Equal(t, 0.0, testutil.ToFloat64(circuitBreakerOpen.WithLabelValues("hard-breaker")))
+
+	// A hard error opens the circuit breaker below the threshold.
+	ctrl.handleRunResult(now, hardErr, hardErrorClass)
+	assert.Equal(t, now.Add(15*time.Minute), ctrl.nextRunAt)
+	assert.Equal(t, 1.0, testutil.ToFloat64(circuitBreakerOpen.WithLabelValues("hard-breaker")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(consecutiveFailures.WithLabelValues("hard-breaker", hardErrorClass)))
+	assert.Equal(t, 0.0, testutil.ToFloat64(consecutiveFailures.WithLabelValues("hard-breaker", softErrorClass)))
+	assert.Equal(t, (15 * time.Minute).Seconds(), testutil.ToFloat64(backoffSeconds.WithLabelValues("hard-breaker", hardErrorClass)))
+
+	// It stays open while the half-open synchronizations fail with soft errors.
+	ctrl.handleRunResult(now, provider.SoftError, softErrorClass)
+	assert.Equal(t, now.Add(15*time.Minute), ctrl.nextRunAt)
+	assert.Equal(t, 3.0, testutil.ToFloat64(consecutiveFailures.WithLabelValues("hard-breaker", softErrorClass)))
+	assert.Equal(t, 0.0, testutil.ToFloat64(consecutiveFailures.WithLabelValues("hard-breaker", hardErrorClass)))
+
+	ctrl.handleRunResult(now, nil, "")
+	assert.Equal(t, 0.0, testutil.ToFloat64(circuitBreakerOpen.WithLabelValues("hard-breaker")))
+	assert.False(t, ctrl.hardFailure)
+}
+
+func TestHardErrorIsFatalWithoutCircuitBreaker(t *testing.T) {
+	logger := log.StandardLogger()
+	exitFunc := logger.ExitFunc
+	defer func() { logger.ExitFunc = exitFunc }()
+	exitCode := -1
+	logger.ExitFunc = func(code int) { exitCode = code }
+
+	ctrl := &Controller{Interval: time.Minute, MaxBackoff: 5 * time.Minute, ProviderName: "hard-fatal"}
+
+	ctrl.handleRunResult(time.Now(), provider.SoftError, softErrorClass)
+	assert.Equal(t, -1, exitCode)
+
+	ctrl.handleRunResult(time.Now(), errors.New("invalid credentials"), hardErrorClass)
+	assert.Equal(t, 1, exitCode)
+}
+
 func testControllerFiltersDomains(t *testing.T, configuredEndpoints []*endpoint.Endpoint, domainFilter endpoint.DomainFilter, providerEndpoints []*endpoint.Endpoint, expectedChanges []*plan.Changes) {
 	t.Helper()
 	cfg := externaldns.NewConfig()
@@ -393,6 +588,122 @@ func TestControllerSkipsEmptyChanges(t *testing.T) {
 	)
 }
 
+// pendingChangesMockRegistry reports pending changes of its own.
+type pendingChangesMockRegistry struct {
+	registry.Registry
+	pending bool
+}
+
+func (r *pendingChangesMockRegistry) HasPendingChanges() bool {
+	return r.pending
+}
+
+func TestControllerAppliesPendingRegistryChanges(t *testing.T) {
+	for _, tc := range []struct {
+		title                string
+		pending              bool
+		expectedApplyChanges int
+	}{
+		{
+			title:                "pending registry changes are applied without changes of the plan",
+			pending:              true,
+			expectedApplyChanges: 1,
+		},
+		{
+			title:                "nothing is applied without changes",
+			pending:              false,
+			expectedApplyChanges: 0,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			source := new(testutils.MockSource)
+			source.On("Endpoints").Return([]*endpoint.Endpoint{}, nil)
+
+			provider := &filteredMockProvider{}
+			r, err := registry.NewNoopRegistry(provider)
+			require.NoError(t, err)
+
+			ctrl := &Controller{
+				Source:             source,
+				Registry:           &pendingChangesMockRegistry{Registry: r, pending: tc.pending},
+				Policy:             &plan.SyncPolicy{},
+				ManagedRecordTypes: []string{endpoint.RecordTypeA},
+			}
+
+			assert.NoError(t, ctrl.RunOnce(context.Background()))
+			assert.Len(t, provider.ApplyChangesCalls, tc.expectedApplyChanges)
+		})
+	}
+}
+
+func TestControllerAppliesChangesInBatches(t *testing.T) {
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("alias.used.tld", endpoint.RecordTypeCNAME, "target.used.tld"),
+		endpoint.NewEndpoint("target.used.tld", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("other.used.tld", endpoint.RecordTypeA, "1.2.3.5"),
+	}, nil)
+
+	provider := &filteredMockProvider{}
+	r, err := registry.NewNoopRegistry(provider)
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:             source,
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME},
+		MaxChangesPerApply: 2,
+	}
+
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	require.Len(t, provider.ApplyChangesCalls, 2)
+	var created []string
+	for _, changes := range provider.ApplyChangesCalls {
+		assert.LessOrEqual(t, len(changes.Create), 2)
+		for _, ep := range changes.Create {
+			created = append(created, ep.DNSName)
+		}
+	}
+	// the alias is created after its target
+	assert.Less(t, slices.Index(created, "target.used.tld"), slices.Index(created, "alias.used.tld"))
+}
+
+func TestControllerAppliesTTLPolicy(t *testing.T) {
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("default.used.tld", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpointWithTTL("short.used.tld", endpoint.RecordTypeA, 10, "1.2.3.5"),
+	}, nil)
+
+	provider := &filteredMockProvider{
+		RecordsStore: []*endpoint.Endpoint{
+			endpoint.NewEndpointWithTTL("default.used.tld", endpoint.RecordTypeA, 300, "1.2.3.4"),
+			endpoint.NewEndpointWithTTL("short.used.tld", endpoint.RecordTypeA, 10, "1.2.3.5"),
+		},
+	}
+	r, err := registry.NewNoopRegistry(provider)
+	require.NoError(t, err)
+
+	policy, err := plan.NewTTLPolicy(5*time.Minute, time.Minute, 0, nil, nil)
+	require.NoError(t, err)
+	ctrl := &Controller{
+		Source:             source,
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+		TTLPolicy:          policy,
+	}
+
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	require.Len(t, provider.ApplyChangesCalls, 1)
+	// the record without a TTL already has the default TTL, only the short TTL is raised to the minimum
+	changes := provider.ApplyChangesCalls[0]
+	require.Len(t, changes.UpdateNew, 1)
+	assert.Equal(t, "short.used.tld", changes.UpdateNew[0].DNSName)
+	assert.Equal(t, endpoint.TTL(60), changes.UpdateNew[0].RecordTTL)
+}
+
 func TestWhenNoFilterControllerConsidersAllComain(t *testing.T) {
 	testControllerFiltersDomains(
 		t,
@@ -739,3 +1050,51 @@ func TestAAAARecords(t *testing.T) {
 	assert.Equal(t, math.Float64bits(2), valueFromMetric(sourceAAAARecords))
 	assert.Equal(t, math.Float64bits(1), valueFromMetric(registryAAAARecords))
 }
+
+func TestRunOnceTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	defer otel.SetTracerProvider(previous)
+
+	mockSource := new(testutils.MockSource)
+	mockSource.On("Endpoints").Return([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("www.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+	}, nil)
+	p := inmemory.NewInMemoryProvider(inmemory.InMemoryInitZones([]string{"example.com"}))
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:             source.NewMultiSource([]source.Source{mockSource}, nil),
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+		ProviderName:       "inmemory",
+	}
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+
+	spans := map[string]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+	root, ok := spans["Controller.RunOnce"]
+	require.True(t, ok)
+	assert.Contains(t, root.Attributes, attribute.String("external_dns.provider", "inmemory"))
+	for _, name := range []string{"Registry.Records", "Source.Endpoints", "Plan.Calculate", "Registry.ApplyChanges"} {
+		span, ok := spans[name]
+		require.True(t, ok, name)
+		assert.Equal(t, root.SpanContext.SpanID(), span.Parent.SpanID(), name)
+	}
+	assert.Contains(t, spans["Registry.ApplyChanges"].Attributes, attribute.Int("external_dns.changes.create", 1))
+
+	// The multi source creates a span for every nested source.
+	var sourceSpans int
+	for _, span := range exporter.GetSpans() {
+		if span.Name == "Source.Endpoints" && span.Parent.SpanID() == spans["Source.Endpoints"].SpanContext.SpanID() {
+			sourceSpans++
+			assert.Contains(t, span.Attributes, attribute.String("external_dns.source", "*testutils.MockSource"))
+		}
+	}
+	assert.Equal(t, 1, sourceSpans)
+}
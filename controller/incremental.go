@@ -0,0 +1,238 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/tracing"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+	"sigs.k8s.io/external-dns/source"
+)
+
+// incrementalState holds the state of the previous synchronization used by incremental synchronizations.
+type incrementalState struct {
+	mutex sync.Mutex
+	// changed are the resources changed since the last synchronization
+	changed map[string]struct{}
+	// unknown is set by changes of unknown resources, which require a full synchronization
+	unknown bool
+	// records are the registry records after the last synchronization, nil requires a full synchronization
+	records []*endpoint.Endpoint
+	// desired are the desired endpoints by resource, evaluated by the last synchronization of the resource
+	desired map[string][]*endpoint.Endpoint
+	// lastFullSync is the time of the last successful full synchronization
+	lastFullSync time.Time
+}
+
+// incrementalSync is the input of an incremental synchronization.
+type incrementalSync struct {
+	changed map[string]struct{}
+	records []*endpoint.Endpoint
+	desired map[string][]*endpoint.Endpoint
+}
+
+// take returns the resources changed since the last synchronization, and whether they can be
+// synchronized incrementally. The changed resources are reset either way.
+func (s *incrementalState) take(now time.Time, fullResyncInterval time.Duration) (incrementalSync, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sync := incrementalSync{changed: s.changed, records: s.records, desired: s.desired}
+	ok := !s.unknown && s.records != nil && now.Sub(s.lastFullSync) < fullResyncInterval
+	s.changed = nil
+	s.unknown = false
+	return sync, ok
+}
+
+// invalidate requires the next synchronization to be a full synchronization.
+func (s *incrementalState) invalidate() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.changed = nil
+	s.unknown = false
+	s.records = nil
+	s.desired = nil
+}
+
+// synced records the state after a successful synchronization.
+func (s *incrementalState) synced(now time.Time, full bool, records []*endpoint.Endpoint, desired map[string][]*endpoint.Endpoint) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.records = records
+	s.desired = desired
+	if full {
+		s.lastFullSync = now
+	}
+}
+
+// ResourceChanged records a changed resource, formatted like the endpoint.ResourceLabelKey label, to
+// be synchronized by the next incremental synchronization. An empty resource requires a full synchronization.
+func (c *Controller) ResourceChanged(resource string) {
+	c.incremental.mutex.Lock()
+	defer c.incremental.mutex.Unlock()
+	if resource == "" {
+		c.incremental.unknown = true
+		return
+	}
+	if c.incremental.changed == nil {
+		c.incremental.changed = map[string]struct{}{}
+	}
+	c.incremental.changed[resource] = struct{}{}
+}
+
+// runIncremental synchronizes the DNS names of the changed resources, the ones of their previous
+// endpoints and the ones of their current endpoints, against the registry records of the previous
+// synchronization, without listing the registry records again. Only the changed resources are
+// evaluated, the endpoints of the other resources are the ones of their last evaluation.
+func (c *Controller) runIncremental(ctx context.Context, sync incrementalSync) error {
+	if len(sync.changed) == 0 {
+		log.Debug("No resources changed since the last synchronization")
+		c.synced()
+		return nil
+	}
+	log.Infof("Synchronizing %d changed resources", len(sync.changed))
+
+	c.state.setRecords(sync.records)
+	ctx = context.WithValue(ctx, provider.RecordsContextKey, sync.records)
+
+	endpoints, err := c.resourceEndpoints(ctx, sync.changed)
+	if err != nil {
+		sourceErrorsTotal.Inc()
+		deprecatedSourceErrors.Inc()
+		c.incremental.invalidate()
+		return err
+	}
+	endpoints, err = c.Registry.AdjustEndpoints(endpoints)
+	if err != nil {
+		c.incremental.invalidate()
+		return fmt.Errorf("adjusting endpoints: %w", err)
+	}
+
+	// The sources may return the endpoints of other resources merged with the changed ones.
+	evaluated := endpointsByResource(endpoints)
+	for resource := range sync.changed {
+		if _, ok := evaluated[resource]; !ok {
+			evaluated[resource] = nil
+		}
+	}
+
+	affected := map[string]struct{}{}
+	desired := make(map[string][]*endpoint.Endpoint, len(sync.desired)+len(evaluated))
+	for resource, resourceEndpoints := range sync.desired {
+		desired[resource] = resourceEndpoints
+	}
+	for resource, resourceEndpoints := range evaluated {
+		for _, ep := range desired[resource] {
+			affected[ep.DNSName] = struct{}{}
+		}
+		for _, ep := range resourceEndpoints {
+			affected[ep.DNSName] = struct{}{}
+		}
+		if len(resourceEndpoints) == 0 {
+			delete(desired, resource)
+		} else {
+			desired[resource] = resourceEndpoints
+		}
+	}
+
+	var all []*endpoint.Endpoint
+	for _, resourceEndpoints := range desired {
+		all = append(all, resourceEndpoints...)
+	}
+	c.state.setDesired(all)
+
+	// The plan may modify the desired endpoints, which are kept for the next synchronizations.
+	plan := c.calculatePlan(ctx, filterByNames(sync.records, affected), copyEndpoints(filterByNames(all, affected)))
+	if err := c.applyChanges(ctx, plan.Changes); err != nil {
+		c.incremental.invalidate()
+		return err
+	}
+
+	c.incremental.synced(time.Now(), false, applyChangesToRecords(sync.records, plan.Changes), desired)
+	c.synced()
+
+	return nil
+}
+
+// resourceEndpoints returns the endpoints of the resources.
+func (c *Controller) resourceEndpoints(ctx context.Context, resources map[string]struct{}) ([]*endpoint.Endpoint, error) {
+	ctx, span := tracing.Start(ctx, "Source.ResourceEndpoints")
+	endpoints, err := source.ResourceEndpoints(ctx, c.Source, resources)
+	span.SetAttributes(attribute.Int("external_dns.endpoints", len(endpoints)))
+	tracing.End(span, err)
+	if err != nil {
+		return nil, err
+	}
+	return c.TTLPolicy.Apply(endpoints), nil
+}
+
+// ResourceChanged records a changed resource for every controller.
+func (r *Router) ResourceChanged(resource string) {
+	for _, c := range r.Controllers {
+		c.ResourceChanged(resource)
+	}
+}
+
+// endpointsByResource returns copies of the endpoints by their resource, kept by incremental
+// synchronizations while the plan modifies the endpoints.
+func endpointsByResource(endpoints []*endpoint.Endpoint) map[string][]*endpoint.Endpoint {
+	result := map[string][]*endpoint.Endpoint{}
+	for _, ep := range endpoints {
+		resource := ep.Labels[endpoint.ResourceLabelKey]
+		result[resource] = append(result[resource], ep.DeepCopy())
+	}
+	return result
+}
+
+func filterByNames(endpoints []*endpoint.Endpoint, names map[string]struct{}) []*endpoint.Endpoint {
+	var result []*endpoint.Endpoint
+	for _, ep := range endpoints {
+		if _, ok := names[ep.DNSName]; ok {
+			result = append(result, ep)
+		}
+	}
+	return result
+}
+
+// applyChangesToRecords returns the records after applying the changes.
+func applyChangesToRecords(records []*endpoint.Endpoint, changes *plan.Changes) []*endpoint.Endpoint {
+	removed := map[endpoint.EndpointKey]struct{}{}
+	for _, ep := range changes.UpdateOld {
+		removed[ep.Key()] = struct{}{}
+	}
+	for _, ep := range changes.Delete {
+		removed[ep.Key()] = struct{}{}
+	}
+
+	result := make([]*endpoint.Endpoint, 0, len(records)+len(changes.Create))
+	for _, ep := range records {
+		if _, ok := removed[ep.Key()]; !ok {
+			result = append(result, ep)
+		}
+	}
+	result = append(result, changes.Create...)
+	return append(result, changes.UpdateNew...)
+}
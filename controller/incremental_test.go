@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider/inmemory"
+	"sigs.k8s.io/external-dns/registry"
+)
+
+// resourceSource returns endpoints by resource, which tests change between synchronizations.
+type resourceSource struct {
+	hosts map[string]map[string]string
+	// evaluated are the resources evaluated by the calls to Endpoints and ResourceEndpoints
+	evaluated []string
+}
+
+func (s *resourceSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	var endpoints []*endpoint.Endpoint
+	for resource := range s.hosts {
+		endpoints = append(endpoints, s.endpoints(resource)...)
+	}
+	return endpoints, nil
+}
+
+func (s *resourceSource) ResourceEndpoints(ctx context.Context, resources map[string]struct{}) ([]*endpoint.Endpoint, error) {
+	var endpoints []*endpoint.Endpoint
+	for resource := range resources {
+		endpoints = append(endpoints, s.endpoints(resource)...)
+	}
+	return endpoints, nil
+}
+
+func (s *resourceSource) endpoints(resource string) []*endpoint.Endpoint {
+	s.evaluated = append(s.evaluated, resource)
+	var endpoints []*endpoint.Endpoint
+	for host, target := range s.hosts[resource] {
+		ep := endpoint.NewEndpoint(host, endpoint.RecordTypeA, target)
+		ep.Labels[endpoint.ResourceLabelKey] = resource
+		endpoints = append(endpoints, ep)
+	}
+	return endpoints
+}
+
+func (s *resourceSource) AddEventHandler(ctx context.Context, handler func()) {}
+
+func (s *resourceSource) AddResourceEventHandler(ctx context.Context, handler func(resource string)) {
+}
+
+func providerTargets(t *testing.T, p *inmemory.InMemoryProvider) map[string]string {
+	t.Helper()
+	records, err := p.Records(context.Background())
+	require.NoError(t, err)
+	targets := map[string]string{}
+	for _, ep := range records {
+		targets[ep.DNSName] = ep.Targets.String()
+	}
+	return targets
+}
+
+func TestIncrementalSynchronization(t *testing.T) {
+	src := &resourceSource{hosts: map[string]map[string]string{
+		"ingress/default/web": {"www.example.com": "1.2.3.4"},
+		"ingress/default/api": {"api.example.com": "5.6.7.8"},
+	}}
+	p := inmemory.NewInMemoryProvider(inmemory.InMemoryInitZones([]string{"example.com"}))
+	var listed int
+	p.OnRecords = func() { listed++ }
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:             src,
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+		FullResyncInterval: time.Hour,
+	}
+	ctx := context.Background()
+
+	// The first synchronization is a full one.
+	require.NoError(t, ctrl.RunOnce(ctx))
+	assert.Equal(t, 1, listed)
+	assert.Equal(t, map[string]string{"www.example.com": "1.2.3.4", "api.example.com": "5.6.7.8"}, providerTargets(t, p))
+	listed = 0
+
+	// Only the changed resources are evaluated, and only their DNS names are synchronized, without
+	// listing the records.
+	src.hosts["ingress/default/web"] = map[string]string{"www.example.com": "9.9.9.9"}
+	src.hosts["ingress/default/api"] = map[string]string{"api.example.com": "7.7.7.7"}
+	src.evaluated = nil
+	ctrl.ResourceChanged("ingress/default/web")
+	require.NoError(t, ctrl.RunOnce(ctx))
+	assert.Equal(t, []string{"ingress/default/web"}, src.evaluated)
+	assert.Equal(t, map[string]string{"www.example.com": "9.9.9.9", "api.example.com": "5.6.7.8"}, providerTargets(t, p))
+
+	// Nothing is synchronized without changes.
+	require.NoError(t, ctrl.RunOnce(ctx))
+	assert.False(t, ctrl.Snapshot().LastSuccess.IsZero())
+
+	// The DNS names previously owned by a changed resource are synchronized as well.
+	src.hosts["ingress/default/web"] = map[string]string{"www2.example.com": "9.9.9.9"}
+	ctrl.ResourceChanged("ingress/default/web")
+	require.NoError(t, ctrl.RunOnce(ctx))
+	assert.Equal(t, map[string]string{"www2.example.com": "9.9.9.9", "api.example.com": "5.6.7.8"}, providerTargets(t, p))
+
+	// A DNS name shared with an unchanged resource keeps the endpoints of its last evaluation.
+	src.hosts["ingress/default/web"] = map[string]string{"www2.example.com": "9.9.9.9", "api.example.com": "5.6.7.8"}
+	ctrl.ResourceChanged("ingress/default/web")
+	require.NoError(t, ctrl.RunOnce(ctx))
+	assert.Equal(t, map[string]string{"www2.example.com": "9.9.9.9", "api.example.com": "5.6.7.8"}, providerTargets(t, p))
+
+	// Deleting a resource deletes its DNS names.
+	delete(src.hosts, "ingress/default/web")
+	ctrl.ResourceChanged("ingress/default/web")
+	require.NoError(t, ctrl.RunOnce(ctx))
+	assert.Equal(t, map[string]string{"api.example.com": "5.6.7.8"}, providerTargets(t, p))
+	assert.Equal(t, 4, listed, "only the test itself listed the records")
+
+	// A change of unknown resources requires a full synchronization.
+	listed = 0
+	ctrl.ResourceChanged("")
+	require.NoError(t, ctrl.RunOnce(ctx))
+	assert.Equal(t, 1, listed)
+	assert.Equal(t, map[string]string{"api.example.com": "7.7.7.7"}, providerTargets(t, p))
+
+	// A full synchronization runs once per full resync interval.
+	listed = 0
+	ctrl.incremental.lastFullSync = time.Now().Add(-2 * time.Hour)
+	require.NoError(t, ctrl.RunOnce(ctx))
+	assert.Equal(t, 1, listed)
+}
+
+func TestIncrementalSynchronizationDisabled(t *testing.T) {
+	src := &resourceSource{hosts: map[string]map[string]string{
+		"ingress/default/web": {"www.example.com": "1.2.3.4"},
+	}}
+	p := inmemory.NewInMemoryProvider(inmemory.InMemoryInitZones([]string{"example.com"}))
+	var listed int
+	p.OnRecords = func() { listed++ }
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:             src,
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+	}
+
+	ctrl.ResourceChanged("ingress/default/web")
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	ctrl.ResourceChanged("ingress/default/web")
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	assert.Equal(t, 2, listed)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const (
+	// inClusterNamespaceFile holds the namespace of the pod when running inside a cluster.
+	inClusterNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+	// leaderElectionHealthzTimeout is the time allowed beyond the lease expiry before /healthz fails.
+	leaderElectionHealthzTimeout = 20 * time.Second
+)
+
+// LeaderElectionConfig holds the settings of the Lease based leader election.
+type LeaderElectionConfig struct {
+	// Client is used to read and update the Lease.
+	Client kubernetes.Interface
+	// Namespace of the Lease, defaults to the namespace of the pod or "default".
+	Namespace string
+	// Name of the Lease shared by all replicas.
+	Name string
+	// Identity of this replica, defaults to the hostname and a random suffix.
+	Identity string
+	// LeaseDuration is the time standby replicas wait before taking over a lease which was not renewed.
+	LeaseDuration time.Duration
+	// RenewDeadline is the time the leader retries renewing the lease before giving up leadership.
+	RenewDeadline time.Duration
+	// RetryPeriod is the time between attempts to acquire or renew the lease.
+	RetryPeriod time.Duration
+}
+
+// LeaderElection runs a function only while this replica holds the Lease, so that
+// only one of several replicas applies changes to the DNS provider.
+type LeaderElection struct {
+	config   leaderelection.LeaderElectionConfig
+	watchdog *leaderelection.HealthzAdaptor
+	leader   atomic.Bool
+}
+
+// NewLeaderElection returns a LeaderElection competing for the Lease described by cfg.
+func NewLeaderElection(cfg LeaderElectionConfig) (*LeaderElection, error) {
+	if cfg.Client == nil {
+		return nil, fmt.Errorf("leader election requires a kubernetes client")
+	}
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("leader election requires a lease name")
+	}
+	if cfg.Namespace == "" {
+		cfg.Namespace = inClusterNamespace()
+	}
+	if cfg.Identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine leader election identity: %w", err)
+		}
+		cfg.Identity = hostname + "_" + string(uuid.NewUUID())
+	}
+
+	watchdog := leaderelection.NewLeaderHealthzAdaptor(leaderElectionHealthzTimeout)
+	le := &LeaderElection{
+		config: leaderelection.LeaderElectionConfig{
+			Lock: &resourcelock.LeaseLock{
+				LeaseMeta: metav1.ObjectMeta{
+					Namespace: cfg.Namespace,
+					Name:      cfg.Name,
+				},
+				Client: cfg.Client.CoordinationV1(),
+				LockConfig: resourcelock.ResourceLockConfig{
+					Identity: cfg.Identity,
+				},
+			},
+			LeaseDuration:   cfg.LeaseDuration,
+			RenewDeadline:   cfg.RenewDeadline,
+			RetryPeriod:     cfg.RetryPeriod,
+			ReleaseOnCancel: true,
+			WatchDog:        watchdog,
+			Name:            cfg.Name,
+		},
+		watchdog: watchdog,
+	}
+	return le, nil
+}
+
+// Run blocks until ctx is cancelled or the leadership is lost. While this replica is
+// the leader, run is called with a context that is cancelled when the leadership ends.
+// onStoppedLeading is called once the leadership is lost while ctx is still active.
+func (le *LeaderElection) Run(ctx context.Context, run func(ctx context.Context), onStoppedLeading func()) error {
+	identity := le.config.Lock.Identity()
+
+	config := le.config
+	config.Callbacks = leaderelection.LeaderCallbacks{
+		OnStartedLeading: func(ctx context.Context) {
+			log.Infof("Acquired leader lease %s as %s", config.Name, identity)
+			le.leader.Store(true)
+			controllerLeader.Set(1)
+			run(ctx)
+		},
+		OnStoppedLeading: func() {
+			wasLeader := le.leader.Swap(false)
+			controllerLeader.Set(0)
+			if ctx.Err() != nil {
+				log.Infof("Released leader lease %s", config.Name)
+				return
+			}
+			if wasLeader {
+				log.Errorf("Lost leader lease %s", config.Name)
+			}
+			if onStoppedLeading != nil {
+				onStoppedLeading()
+			}
+		},
+		OnNewLeader: func(current string) {
+			if current != identity {
+				log.Infof("Waiting as standby, current leader is %s", current)
+			}
+		},
+	}
+
+	elector, err := leaderelection.NewLeaderElector(config)
+	if err != nil {
+		return err
+	}
+	le.watchdog.SetLeaderElection(elector)
+
+	log.Infof("Attempting to acquire leader lease %s as %s", config.Name, identity)
+	elector.Run(ctx)
+	return nil
+}
+
+// IsLeader returns whether this replica currently holds the lease.
+func (le *LeaderElection) IsLeader() bool {
+	return le.leader.Load()
+}
+
+// Check reports an error when this replica holds the lease but failed to renew it in time.
+func (le *LeaderElection) Check(req *http.Request) error {
+	return le.watchdog.Check(req)
+}
+
+// inClusterNamespace returns the namespace of the pod, or "default" outside of a cluster.
+func inClusterNamespace() string {
+	if data, err := os.ReadFile(inClusterNamespaceFile); err == nil {
+		if ns := strings.TrimSpace(string(data)); ns != "" {
+			return ns
+		}
+	}
+	return "default"
+}
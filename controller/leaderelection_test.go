@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestLeaderElection(t *testing.T, client kubernetes.Interface, identity string) *LeaderElection {
+	t.Helper()
+	le, err := NewLeaderElection(LeaderElectionConfig{
+		Client:        client,
+		Namespace:     "external-dns",
+		Name:          "external-dns-leader",
+		Identity:      identity,
+		LeaseDuration: time.Second,
+		RenewDeadline: 500 * time.Millisecond,
+		RetryPeriod:   100 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	return le
+}
+
+func TestNewLeaderElection(t *testing.T) {
+	_, err := NewLeaderElection(LeaderElectionConfig{Name: "external-dns-leader"})
+	assert.Error(t, err)
+
+	_, err = NewLeaderElection(LeaderElectionConfig{Client: fake.NewSimpleClientset()})
+	assert.Error(t, err)
+
+	le, err := NewLeaderElection(LeaderElectionConfig{Client: fake.NewSimpleClientset(), Name: "external-dns-leader"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, le.config.Lock.Identity())
+	assert.False(t, le.IsLeader())
+	assert.NoError(t, le.Check(nil))
+}
+
+func TestLeaderElectionInvalidDurations(t *testing.T) {
+	le, err := NewLeaderElection(LeaderElectionConfig{
+		Client:        fake.NewSimpleClientset(),
+		Name:          "external-dns-leader",
+		LeaseDuration: time.Second,
+		RenewDeadline: 2 * time.Second,
+		RetryPeriod:   100 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	err = le.Run(context.Background(), func(context.Context) {
+		t.Error("run must not be called")
+	}, nil)
+	assert.Error(t, err)
+}
+
+func TestLeaderElectionFailover(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	first := newTestLeaderElection(t, client, "first")
+	second := newTestLeaderElection(t, client, "second")
+
+	var firstRuns, secondRuns atomic.Int32
+	var firstStopped atomic.Bool
+
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstDone := make(chan struct{})
+	go func() {
+		defer close(firstDone)
+		assert.NoError(t, first.Run(firstCtx, func(ctx context.Context) {
+			firstRuns.Add(1)
+			<-ctx.Done()
+		}, func() { firstStopped.Store(true) }))
+	}()
+
+	require.Eventually(t, first.IsLeader, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1.0, testutil.ToFloat64(controllerLeader))
+
+	lease, err := client.CoordinationV1().Leases("external-dns").Get(context.Background(), "external-dns-leader", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "first", *lease.Spec.HolderIdentity)
+
+	secondCtx, cancelSecond := context.WithCancel(context.Background())
+	secondDone := make(chan struct{})
+	go func() {
+		defer close(secondDone)
+		assert.NoError(t, second.Run(secondCtx, func(ctx context.Context) {
+			secondRuns.Add(1)
+			<-ctx.Done()
+		}, nil))
+	}()
+
+	// The standby keeps waiting while the leader renews its lease.
+	time.Sleep(300 * time.Millisecond)
+	assert.False(t, second.IsLeader())
+	assert.Equal(t, int32(0), secondRuns.Load())
+
+	// Cancelling the leader releases the lease and the standby takes over.
+	cancelFirst()
+	<-firstDone
+	assert.False(t, first.IsLeader())
+	assert.False(t, firstStopped.Load(), "a released lease is not a lost leadership")
+
+	require.Eventually(t, second.IsLeader, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(1), firstRuns.Load())
+	assert.Equal(t, int32(1), secondRuns.Load())
+	assert.NoError(t, second.Check(nil))
+
+	cancelSecond()
+	<-secondDone
+	assert.Equal(t, 0.0, testutil.ToFloat64(controllerLeader))
+}
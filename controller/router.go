@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// ProviderRoute routes the DNS names below Domains to the provider instance named Name, either a
+// provider configured by the global flags or a named instance with its own configuration.
+type ProviderRoute struct {
+	Name    string
+	Domains []string
+}
+
+// NewProviderRoutes parses routes given as a map of provider instance names to comma separated
+// lists of domains. The routes are ordered by name.
+func NewProviderRoutes(routes map[string]string) ([]ProviderRoute, error) {
+	result := make([]ProviderRoute, 0, len(routes))
+	routedTo := map[string]string{}
+	for name, domains := range routes {
+		route := ProviderRoute{Name: name}
+		for _, domain := range strings.Split(domains, ",") {
+			domain = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), "."))
+			if domain == "" {
+				continue
+			}
+			if other, ok := routedTo[domain]; ok {
+				return nil, fmt.Errorf("domain %q is routed to both %s and %s", domain, other, name)
+			}
+			routedTo[domain] = name
+			route.Domains = append(route.Domains, domain)
+		}
+		if len(route.Domains) == 0 {
+			return nil, fmt.Errorf("no domains routed to provider %s", name)
+		}
+		result = append(result, route)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// DomainFilter returns the filter matching the DNS names owned by the route: the names below
+// its domains, except the names below a more specific domain routed to another provider.
+func (r ProviderRoute) DomainFilter(routes []ProviderRoute, excludeDomains []string) endpoint.DomainFilter {
+	exclusions := append([]string{}, excludeDomains...)
+	for _, other := range routes {
+		if other.Name == r.Name {
+			continue
+		}
+		for _, domain := range other.Domains {
+			if endpoint.NewDomainFilter(r.Domains).Match(domain) {
+				exclusions = append(exclusions, domain)
+			}
+		}
+	}
+	return endpoint.NewDomainFilterWithExclusions(r.Domains, exclusions)
+}
+
+// UnroutedDomainFilter returns the filter matching the DNS names which are not routed to any provider.
+func UnroutedDomainFilter(routes []ProviderRoute) endpoint.DomainFilter {
+	var routed []string
+	for _, route := range routes {
+		routed = append(routed, route.Domains...)
+	}
+	return endpoint.NewDomainFilterWithExclusions(nil, routed)
+}
+
+// Router runs one Controller per provider. The controllers share the same source,
+// each of them calculating and applying the plan of the domains routed to its provider.
+type Router struct {
+	Controllers []*Controller
+}
+
+// RunOnce runs a single reconciliation of every controller.
+func (r *Router) RunOnce(ctx context.Context) error {
+	var errs []error
+	for _, c := range r.Controllers {
+		if err := c.RunOnce(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("provider %s: %w", c.ProviderName, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ScheduleRunOnce schedules a reconciliation of every controller.
+func (r *Router) ScheduleRunOnce(now time.Time) {
+	for _, c := range r.Controllers {
+		c.ScheduleRunOnce(now)
+	}
+}
+
+// Run runs the reconciliation loops of all controllers until context is canceled.
+func (r *Router) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, c := range r.Controllers {
+		wg.Add(1)
+		go func(c *Controller) {
+			defer wg.Done()
+			c.Run(ctx)
+		}(c)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/internal/testutils"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider/inmemory"
+	"sigs.k8s.io/external-dns/registry"
+)
+
+func TestNewProviderRoutes(t *testing.T) {
+	routes, err := NewProviderRoutes(map[string]string{
+		"rfc2136":    "internal.example.com, corp.example.com.",
+		"cloudflare": "Example.com",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []ProviderRoute{
+		{Name: "cloudflare", Domains: []string{"example.com"}},
+		{Name: "rfc2136", Domains: []string{"internal.example.com", "corp.example.com"}},
+	}, routes)
+
+	_, err = NewProviderRoutes(map[string]string{"rfc2136": " , "})
+	assert.Error(t, err)
+
+	_, err = NewProviderRoutes(map[string]string{
+		"rfc2136":    "example.com",
+		"cloudflare": "example.com",
+	})
+	assert.Error(t, err)
+}
+
+func TestProviderRouteDomainFilter(t *testing.T) {
+	routes, err := NewProviderRoutes(map[string]string{
+		"cloudflare": "example.com",
+		"rfc2136":    "internal.example.com",
+		"aws":        "partner.example.org",
+	})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		domain   string
+		provider string
+		unrouted bool
+	}{
+		{"example.com", "cloudflare", false},
+		{"www.example.com", "cloudflare", false},
+		{"internal.example.com", "rfc2136", false},
+		{"db.internal.example.com", "rfc2136", false},
+		{"www.partner.example.org", "aws", false},
+		{"www.excluded.example.com", "", false},
+		{"example.net", "", true},
+	} {
+		var matched []string
+		for _, route := range routes {
+			if route.DomainFilter(routes, []string{"excluded.example.com"}).Match(tc.domain) {
+				matched = append(matched, route.Name)
+			}
+		}
+		if tc.provider == "" {
+			assert.Empty(t, matched, tc.domain)
+		} else {
+			assert.Equal(t, []string{tc.provider}, matched, tc.domain)
+		}
+		assert.Equal(t, tc.unrouted, UnroutedDomainFilter(routes).Match(tc.domain), tc.domain)
+	}
+}
+
+func TestRouterRunOnce(t *testing.T) {
+	routes, err := NewProviderRoutes(map[string]string{
+		"public":   "example.com",
+		"internal": "internal.example.com",
+	})
+	require.NoError(t, err)
+
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("www.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("db.internal.example.com", endpoint.RecordTypeA, "10.0.0.1"),
+		endpoint.NewEndpoint("www.example.org", endpoint.RecordTypeA, "5.6.7.8"),
+	}, nil)
+
+	router := &Router{}
+	providers := map[string]*inmemory.InMemoryProvider{}
+	for _, route := range routes {
+		domainFilter := route.DomainFilter(routes, nil)
+		p := inmemory.NewInMemoryProvider(inmemory.InMemoryInitZones(route.Domains), inmemory.InMemoryWithDomain(domainFilter))
+		r, err := registry.NewNoopRegistry(p)
+		require.NoError(t, err)
+		providers[route.Name] = p
+		router.Controllers = append(router.Controllers, &Controller{
+			Source:             source,
+			Registry:           r,
+			Policy:             &plan.SyncPolicy{},
+			DomainFilter:       domainFilter,
+			ManagedRecordTypes: []string{endpoint.RecordTypeA},
+			ProviderName:       route.Name,
+		})
+	}
+
+	require.NoError(t, router.RunOnce(context.Background()))
+
+	public, err := providers["public"].Records(context.Background())
+	require.NoError(t, err)
+	require.Len(t, public, 1)
+	assert.Equal(t, "www.example.com", public[0].DNSName)
+
+	internal, err := providers["internal"].Records(context.Background())
+	require.NoError(t, err)
+	require.Len(t, internal, 1)
+	assert.Equal(t, "db.internal.example.com", internal[0].DNSName)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(providerChangesTotal.WithLabelValues("public", "create")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(providerChangesTotal.WithLabelValues("internal", "create")))
+
+	// A scheduled run applies to every controller.
+	now := time.Now()
+	router.ScheduleRunOnce(now)
+	for _, c := range router.Controllers {
+		assert.True(t, c.ShouldRunOnce(now.Add(5*time.Second)))
+	}
+}
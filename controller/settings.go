@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// Settings are the settings of a controller which can change while it runs.
+type Settings struct {
+	Policy             plan.Policy
+	Interval           time.Duration
+	DomainFilter       endpoint.DomainFilterInterface
+	ManagedRecordTypes []string
+	ExcludeRecordTypes []string
+}
+
+// RecordTypesUpdater is implemented by registries which filter their records by type.
+type RecordTypesUpdater interface {
+	UpdateRecordTypes(managed, exclude []string)
+}
+
+// Reconfigure changes the settings of the controller. The interval applies to the next scheduled
+// synchronization, the other settings apply from the next synchronization on, which is a full one.
+func (c *Controller) Reconfigure(settings Settings) {
+	c.runAtMutex.Lock()
+	defer c.runAtMutex.Unlock()
+
+	if settings.Interval != c.Interval && !c.lastRunAt.IsZero() {
+		c.nextRunAt = latest(earliest(c.nextRunAt, c.lastRunAt.Add(settings.Interval)), c.backoffUntil)
+	}
+	c.Interval = settings.Interval
+	c.pendingSettings = &settings
+}
+
+// applyPendingSettings applies the settings changed by Reconfigure since the last synchronization,
+// and returns whether there were any.
+func (c *Controller) applyPendingSettings() bool {
+	c.runAtMutex.Lock()
+	settings := c.pendingSettings
+	c.pendingSettings = nil
+	c.runAtMutex.Unlock()
+
+	if settings == nil {
+		return false
+	}
+	log.Infof("Applying the changed settings of the controller for %q", c.ProviderName)
+	c.Policy = settings.Policy
+	c.DomainFilter = settings.DomainFilter
+	c.ManagedRecordTypes = settings.ManagedRecordTypes
+	c.ExcludeRecordTypes = settings.ExcludeRecordTypes
+	if updater, ok := c.Registry.(RecordTypesUpdater); ok {
+		updater.UpdateRecordTypes(settings.ManagedRecordTypes, settings.ExcludeRecordTypes)
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider/inmemory"
+	"sigs.k8s.io/external-dns/registry"
+)
+
+// recordTypesRegistry records the record types set by UpdateRecordTypes.
+type recordTypesRegistry struct {
+	registry.Registry
+	managed, exclude []string
+}
+
+func (r *recordTypesRegistry) UpdateRecordTypes(managed, exclude []string) {
+	r.managed = managed
+	r.exclude = exclude
+}
+
+func TestReconfigure(t *testing.T) {
+	src := &resourceSource{hosts: map[string]map[string]string{
+		"ingress/default/a": {"a.example.com": "1.2.3.4"},
+		"ingress/default/b": {"b.example.org": "5.6.7.8"},
+	}}
+	p := inmemory.NewInMemoryProvider(inmemory.InMemoryInitZones([]string{"example.com", "example.org"}))
+	noop, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+	r := &recordTypesRegistry{Registry: noop}
+
+	ctrl := &Controller{
+		Source:             src,
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		Interval:           time.Hour,
+		DomainFilter:       endpoint.NewDomainFilter([]string{"example.com"}),
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+	}
+	ctx := context.Background()
+
+	require.True(t, ctrl.ShouldRunOnce(time.Now()))
+	require.NoError(t, ctrl.RunOnce(ctx))
+	assert.Equal(t, map[string]string{"a.example.com": "1.2.3.4"}, providerTargets(t, p))
+	assert.False(t, ctrl.ShouldRunOnce(time.Now().Add(time.Minute)))
+
+	ctrl.Reconfigure(Settings{
+		Policy:             &plan.UpsertOnlyPolicy{},
+		Interval:           time.Minute,
+		DomainFilter:       endpoint.NewDomainFilter([]string{"example.org"}),
+		ManagedRecordTypes: []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME},
+		ExcludeRecordTypes: []string{endpoint.RecordTypeAAAA},
+	})
+	// The shorter interval applies to the next scheduled synchronization.
+	assert.True(t, ctrl.ShouldRunOnce(time.Now().Add(2*time.Minute)))
+
+	// The other settings apply from the next synchronization on.
+	assert.Nil(t, r.managed)
+	require.NoError(t, ctrl.RunOnce(ctx))
+	assert.Equal(t, map[string]string{"a.example.com": "1.2.3.4", "b.example.org": "5.6.7.8"}, providerTargets(t, p))
+	assert.Equal(t, &plan.UpsertOnlyPolicy{}, ctrl.Policy)
+	assert.Equal(t, []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME}, r.managed)
+	assert.Equal(t, []string{endpoint.RecordTypeAAAA}, r.exclude)
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// Snapshot is the state of the last synchronization of a controller.
+type Snapshot struct {
+	// LastSuccess is the time of the last successful synchronization, zero until the first one
+	LastSuccess time.Time `json:"lastSuccess"`
+	// Records are the records read from the registry
+	Records []*endpoint.Endpoint `json:"records"`
+	// Desired are the endpoints read from the source
+	Desired []*endpoint.Endpoint `json:"desired"`
+	// Changes are the changes calculated by the plan
+	Changes *plan.Changes `json:"changes"`
+	// UnicodeNames maps the internationalized domain names of the records and of the desired endpoints,
+	// in the ASCII form they are stored in, to their Unicode form
+	UnicodeNames map[string]string `json:"unicodeNames,omitempty"`
+}
+
+// syncState records the snapshot of the last synchronization of a controller.
+type syncState struct {
+	mutex    sync.RWMutex
+	snapshot Snapshot
+}
+
+func (s *syncState) setRecords(records []*endpoint.Endpoint) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.snapshot.Records = copyEndpoints(records)
+}
+
+func (s *syncState) setDesired(desired []*endpoint.Endpoint) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.snapshot.Desired = copyEndpoints(desired)
+}
+
+func (s *syncState) setChanges(changes *plan.Changes) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.snapshot.Changes = &plan.Changes{
+		Create:    copyEndpoints(changes.Create),
+		UpdateOld: copyEndpoints(changes.UpdateOld),
+		UpdateNew: copyEndpoints(changes.UpdateNew),
+		Delete:    copyEndpoints(changes.Delete),
+	}
+}
+
+func (s *syncState) setSuccess(now time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.snapshot.LastSuccess = now
+}
+
+func (s *syncState) get() Snapshot {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	snapshot := s.snapshot
+	snapshot.UnicodeNames = unicodeNames(snapshot.Records, snapshot.Desired)
+	return snapshot
+}
+
+// Snapshot returns the state of the last synchronization.
+func (c *Controller) Snapshot() Snapshot {
+	return c.state.get()
+}
+
+// Ready returns an error until every controller synchronized successfully, or when the last successful
+// synchronization of a controller is older than maxAge. A zero maxAge disables the age check.
+func (r *Router) Ready(now time.Time, maxAge time.Duration) error {
+	for _, c := range r.Controllers {
+		lastSuccess := c.Snapshot().LastSuccess
+		if lastSuccess.IsZero() {
+			return fmt.Errorf("provider %s did not synchronize successfully yet", c.ProviderName)
+		}
+		if maxAge > 0 && now.Sub(lastSuccess) > maxAge {
+			return fmt.Errorf("provider %s did not synchronize successfully since %s", c.ProviderName, lastSuccess.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// Snapshots returns the state of the last synchronization of every controller by provider.
+func (r *Router) Snapshots() map[string]Snapshot {
+	snapshots := make(map[string]Snapshot, len(r.Controllers))
+	for _, c := range r.Controllers {
+		snapshots[c.ProviderName] = c.Snapshot()
+	}
+	return snapshots
+}
+
+func copyEndpoints(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	result := make([]*endpoint.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		result = append(result, ep.DeepCopy())
+	}
+	return result
+}
+
+// unicodeNames returns the Unicode form of the internationalized domain names of the endpoints, nil if
+// there are none.
+func unicodeNames(endpoints ...[]*endpoint.Endpoint) map[string]string {
+	var names map[string]string
+	for _, eps := range endpoints {
+		for _, ep := range eps {
+			if unicode := endpoint.ToUnicode(ep.DNSName); unicode != ep.DNSName {
+				if names == nil {
+					names = map[string]string{}
+				}
+				names[ep.DNSName] = unicode
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/internal/testutils"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider/inmemory"
+	"sigs.k8s.io/external-dns/registry"
+)
+
+func TestControllerSnapshot(t *testing.T) {
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("www.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+	}, nil)
+
+	p := inmemory.NewInMemoryProvider(inmemory.InMemoryInitZones([]string{"example.com"}))
+	require.NoError(t, p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{endpoint.NewEndpoint("old.example.com", endpoint.RecordTypeA, "5.6.7.8")},
+	}))
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:             source,
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+		ProviderName:       "inmemory",
+	}
+	router := &Router{Controllers: []*Controller{ctrl}}
+
+	now := time.Now()
+	assert.Error(t, router.Ready(now, 0), "not ready before the first synchronization")
+	assert.True(t, ctrl.Snapshot().LastSuccess.IsZero())
+
+	require.NoError(t, router.RunOnce(context.Background()))
+
+	snapshot := router.Snapshots()["inmemory"]
+	assert.False(t, snapshot.LastSuccess.IsZero())
+	require.Len(t, snapshot.Records, 1)
+	assert.Equal(t, "old.example.com", snapshot.Records[0].DNSName)
+	require.Len(t, snapshot.Desired, 1)
+	assert.Equal(t, "www.example.com", snapshot.Desired[0].DNSName)
+	require.Len(t, snapshot.Changes.Create, 1)
+	assert.Equal(t, "www.example.com", snapshot.Changes.Create[0].DNSName)
+	require.Len(t, snapshot.Changes.Delete, 1)
+	assert.Equal(t, "old.example.com", snapshot.Changes.Delete[0].DNSName)
+
+	assert.NoError(t, router.Ready(snapshot.LastSuccess, time.Minute))
+	assert.NoError(t, router.Ready(snapshot.LastSuccess.Add(time.Hour), 0))
+	assert.Error(t, router.Ready(snapshot.LastSuccess.Add(2*time.Minute), time.Minute), "not ready when the last success is too old")
+}
+
+func TestControllerSnapshotKeepsLastSuccess(t *testing.T) {
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return(nil, errors.New("source failure"))
+
+	p := inmemory.NewInMemoryProvider(inmemory.InMemoryInitZones([]string{"example.com"}))
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+
+	lastSuccess := time.Now().Add(-time.Minute)
+	ctrl := &Controller{Source: source, Registry: r, Policy: &plan.SyncPolicy{}}
+	ctrl.state.setSuccess(lastSuccess)
+
+	assert.Error(t, ctrl.RunOnce(context.Background()))
+	assert.Equal(t, lastSuccess, ctrl.Snapshot().LastSuccess)
+}
+
+func TestControllerSnapshotUnicodeNames(t *testing.T) {
+	var state syncState
+	state.setDesired([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("www.bücher.example", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("www.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+	})
+	assert.Equal(t, map[string]string{"www.xn--bcher-kva.example": "www.bücher.example"}, state.get().UnicodeNames)
+
+	state.setDesired(nil)
+	assert.Nil(t, state.get().UnicodeNames)
+}
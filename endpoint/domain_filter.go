@@ -70,6 +70,10 @@ func prepareFilters(filters []string) []string {
 	var fs []string
 	for _, filter := range filters {
 		if domain := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(filter), ".")); domain != "" {
+			// internationalized domains are matched in their ASCII form, the one of the endpoints
+			if ascii, err := ToASCII(domain); err == nil {
+				domain = ascii
+			}
 			fs = append(fs, domain)
 		}
 	}
@@ -109,7 +113,7 @@ func matchFilter(filters []string, domain string, emptyval bool) bool {
 		return emptyval
 	}
 
-	strippedDomain := strings.ToLower(strings.TrimSuffix(domain, "."))
+	strippedDomain := asciiDomain(domain)
 	for _, filter := range filters {
 		if filter == "" {
 			continue
@@ -132,13 +136,37 @@ func matchFilter(filters []string, domain string, emptyval bool) bool {
 // negativeRegex, if set, takes precedence over regex.  Therefore, matchRegex returns true when
 // only regex regular expression matches the domain
 // Otherwise, if either negativeRegex matches or regex does not match the domain, it returns false
+// Internationalized domains match if either their ASCII or their Unicode form matches.
 func matchRegex(regex *regexp.Regexp, negativeRegex *regexp.Regexp, domain string) bool {
-	strippedDomain := strings.ToLower(strings.TrimSuffix(domain, "."))
+	strippedDomain := asciiDomain(domain)
+	forms := []string{strippedDomain}
+	if unicode := ToUnicode(strippedDomain); unicode != strippedDomain {
+		forms = append(forms, unicode)
+	}
 
 	if negativeRegex != nil && negativeRegex.String() != "" {
-		return !negativeRegex.MatchString(strippedDomain)
+		for _, form := range forms {
+			if negativeRegex.MatchString(form) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, form := range forms {
+		if regex.MatchString(form) {
+			return true
+		}
+	}
+	return false
+}
+
+// asciiDomain returns the lower case ASCII form of domain without its trailing dot.
+func asciiDomain(domain string) string {
+	stripped := strings.ToLower(strings.TrimSuffix(domain, "."))
+	if ascii, err := ToASCII(stripped); err == nil {
+		return ascii
 	}
-	return regex.MatchString(strippedDomain)
+	return stripped
 }
 
 // IsConfigured returns true if any inclusion or exclusion rules have been specified.
@@ -151,6 +179,54 @@ func (df DomainFilter) IsConfigured() bool {
 	return len(df.Filters) > 0 || len(df.exclude) > 0
 }
 
+// Within returns whether every domain matched by df is matched by other as well. Regular
+// expressions can't be compared, so a filter with regular expressions is only within a filter
+// with the same ones.
+func (df DomainFilter) Within(other DomainFilter) bool {
+	if !other.IsConfigured() {
+		return true
+	}
+	if df.usesRegex() || other.usesRegex() {
+		return regexString(df.regex) == regexString(other.regex) &&
+			regexString(df.regexExclusion) == regexString(other.regexExclusion)
+	}
+	if len(df.Filters) == 0 {
+		return false
+	}
+	for _, filter := range df.Filters {
+		if !other.Match(filterDomain(filter)) {
+			return false
+		}
+	}
+	for _, exclusion := range other.exclude {
+		if df.Match(filterDomain(exclusion)) {
+			return false
+		}
+	}
+	return true
+}
+
+// usesRegex returns whether the filter matches with regular expressions.
+func (df DomainFilter) usesRegex() bool {
+	return regexString(df.regex) != "" || regexString(df.regexExclusion) != ""
+}
+
+func regexString(regex *regexp.Regexp) string {
+	if regex == nil {
+		return ""
+	}
+	return regex.String()
+}
+
+// filterDomain returns a domain standing for the domains matched by filter: the filter itself,
+// or any of its subdomains if the filter only matches subdomains.
+func filterDomain(filter string) string {
+	if strings.HasPrefix(filter, ".") {
+		return "*" + filter
+	}
+	return filter
+}
+
 func (df DomainFilter) MarshalJSON() ([]byte, error) {
 	if df.regex != nil || df.regexExclusion != nil {
 		var include, exclude string
@@ -214,7 +290,7 @@ func (df DomainFilter) MatchParent(domain string) bool {
 		return true
 	}
 
-	strippedDomain := strings.ToLower(strings.TrimSuffix(domain, "."))
+	strippedDomain := asciiDomain(domain)
 	for _, filter := range df.Filters {
 		if filter == "" || strings.HasPrefix(filter, ".") {
 			// We don't check parents if the filter is prefixed with "."
@@ -317,6 +317,26 @@ var domainFilterTests = []domainFilterTest{
 			"exclude": {"api.example.org"},
 		},
 	},
+	{
+		[]string{"bücher.example"},
+		[]string{"shop.bücher.example"},
+		[]string{"xn--bcher-kva.example", "www.bücher.example", "WWW.Bücher.example"},
+		true,
+		map[string][]string{
+			"include": {"xn--bcher-kva.example"},
+			"exclude": {"shop.xn--bcher-kva.example"},
+		},
+	},
+	{
+		[]string{"xn--bcher-kva.example"},
+		[]string{"shop.bücher.example"},
+		[]string{"shop.xn--bcher-kva.example", "shop.bücher.example"},
+		false,
+		map[string][]string{
+			"include": {"xn--bcher-kva.example"},
+			"exclude": {"shop.xn--bcher-kva.example"},
+		},
+	},
 }
 
 var regexDomainFilterTests = []regexDomainFilterTest{
@@ -377,6 +397,25 @@ var regexDomainFilterTests = []regexDomainFilterTest{
 			"regexExclude": "^example\\.(?:foo|bar)\\.org$",
 		},
 	},
+	{
+		regexp.MustCompile("bücher\\.example$"),
+		regexp.MustCompile(""),
+		[]string{"www.bücher.example", "www.xn--bcher-kva.example"},
+		true,
+		map[string]string{
+			"regexInclude": "bücher\\.example$",
+		},
+	},
+	{
+		regexp.MustCompile("\\.example$"),
+		regexp.MustCompile("^shop\\.bücher"),
+		[]string{"shop.bücher.example", "shop.xn--bcher-kva.example"},
+		false,
+		map[string]string{
+			"regexInclude": "\\.example$",
+			"regexExclude": "^shop\\.bücher",
+		},
+	},
 }
 
 func TestDomainFilterMatch(t *testing.T) {
@@ -769,3 +808,77 @@ func TestDomainFilterMatchParent(t *testing.T) {
 		})
 	}
 }
+
+func TestDomainFilterWithin(t *testing.T) {
+	for _, tt := range []struct {
+		title    string
+		filter   DomainFilter
+		other    DomainFilter
+		expected bool
+	}{
+		{
+			title:    "any filter is within an empty filter",
+			filter:   NewDomainFilter([]string{"example.org"}),
+			other:    NewDomainFilter(nil),
+			expected: true,
+		},
+		{
+			title:    "an empty filter is not within a configured filter",
+			filter:   NewDomainFilter(nil),
+			other:    NewDomainFilter([]string{"example.com"}),
+			expected: false,
+		},
+		{
+			title:    "subdomains are within their parent domain",
+			filter:   NewDomainFilter([]string{"a.example.com", ".b.example.com", "example.com"}),
+			other:    NewDomainFilter([]string{"example.com"}),
+			expected: true,
+		},
+		{
+			title:    "a parent domain is not within its subdomain",
+			filter:   NewDomainFilter([]string{"example.com"}),
+			other:    NewDomainFilter([]string{"a.example.com"}),
+			expected: false,
+		},
+		{
+			title:    "a domain is not within the filter of its subdomains only",
+			filter:   NewDomainFilter([]string{"example.com"}),
+			other:    NewDomainFilter([]string{".example.com"}),
+			expected: false,
+		},
+		{
+			title:    "another domain is not within the filter",
+			filter:   NewDomainFilter([]string{"a.example.com", "example.org"}),
+			other:    NewDomainFilter([]string{"example.com"}),
+			expected: false,
+		},
+		{
+			title:    "the domains excluded by the other filter must be excluded",
+			filter:   NewDomainFilter([]string{"example.com"}),
+			other:    NewDomainFilterWithExclusions([]string{"example.com"}, []string{"a.example.com"}),
+			expected: false,
+		},
+		{
+			title:    "the domains excluded by the other filter are outside of the filter",
+			filter:   NewDomainFilterWithExclusions([]string{"b.example.com"}, []string{"x.b.example.com"}),
+			other:    NewDomainFilterWithExclusions([]string{"example.com"}, []string{"a.example.com"}),
+			expected: true,
+		},
+		{
+			title:    "the same regular expressions",
+			filter:   NewRegexDomainFilter(regexp.MustCompile(`example\.com$`), nil),
+			other:    NewRegexDomainFilter(regexp.MustCompile(`example\.com$`), nil),
+			expected: true,
+		},
+		{
+			title:    "other regular expressions",
+			filter:   NewRegexDomainFilter(regexp.MustCompile(`a\.example\.com$`), nil),
+			other:    NewRegexDomainFilter(regexp.MustCompile(`example\.com$`), nil),
+			expected: false,
+		},
+	} {
+		t.Run(tt.title, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.filter.Within(tt.other))
+		})
+	}
+}
@@ -46,6 +46,16 @@ const (
 	RecordTypeMX = "MX"
 	// RecordTypeNAPTR is a RecordType enum value
 	RecordTypeNAPTR = "NAPTR"
+	// RecordTypeCAA is a RecordType enum value
+	RecordTypeCAA = "CAA"
+	// RecordTypeTLSA is a RecordType enum value
+	RecordTypeTLSA = "TLSA"
+	// RecordTypeSSHFP is a RecordType enum value
+	RecordTypeSSHFP = "SSHFP"
+	// RecordTypeHTTPS is a RecordType enum value
+	RecordTypeHTTPS = "HTTPS"
+	// RecordTypeSVCB is a RecordType enum value
+	RecordTypeSVCB = "SVCB"
 )
 
 // TTL is a structure defining the TTL of a DNS record
@@ -92,8 +102,15 @@ func (t Targets) Swap(i, j int) {
 	t[i], t[j] = t[j], t[i]
 }
 
-// Same compares to Targets and returns true if they are identical (case-insensitive)
+// Same compares to Targets and returns true if they are identical (case-insensitive). IP addresses
+// are compared in their canonical form.
 func (t Targets) Same(o Targets) bool {
+	return t.SameAs("", o)
+}
+
+// SameAs compares the targets of records of recordType like Same, and compares the data of SRV, MX and
+// NAPTR records in their canonical form.
+func (t Targets) SameAs(recordType string, o Targets) bool {
 	if len(t) != len(o) {
 		return false
 	}
@@ -101,34 +118,30 @@ func (t Targets) Same(o Targets) bool {
 	sort.Stable(o)
 
 	for i, e := range t {
-		if !strings.EqualFold(e, o[i]) {
-			// IPv6 can be shortened, so it should be parsed for equality checking
-			ipA, err := netip.ParseAddr(e)
-			if err != nil {
-				log.WithFields(log.Fields{
-					"targets":           t,
-					"comparisonTargets": o,
-				}).Debugf("Couldn't parse %s as an IP address: %v", e, err)
-			}
-
-			ipB, err := netip.ParseAddr(o[i])
-			if err != nil {
-				log.WithFields(log.Fields{
-					"targets":           t,
-					"comparisonTargets": o,
-				}).Debugf("Couldn't parse %s as an IP address: %v", e, err)
-			}
-
-			// IPv6 Address Shortener == IPv6 Address Expander
-			if ipA.IsValid() && ipB.IsValid() {
-				return ipA.String() == ipB.String()
-			}
+		if !sameTarget(recordType, e, o[i]) {
+			log.WithFields(log.Fields{
+				"targets":           t,
+				"comparisonTargets": o,
+			}).Debugf("Target %s differs from %s", e, o[i])
 			return false
 		}
 	}
 	return true
 }
 
+func sameTarget(recordType, a, b string) bool {
+	if strings.EqualFold(a, b) {
+		return true
+	}
+	// IPv6 can be shortened, so it should be parsed for equality checking
+	ipA, errA := netip.ParseAddr(a)
+	ipB, errB := netip.ParseAddr(b)
+	if errA == nil && errB == nil {
+		return ipA == ipB
+	}
+	return strings.EqualFold(canonicalTarget(recordType, a), canonicalTarget(recordType, b))
+}
+
 // IsLess should fulfill the requirement to compare two targets and choose the 'lesser' one.
 // In the past target was a simple string so simple string comparison could be used. Now we define 'less'
 // as either being the shorter list of targets or where the first entry is less.
@@ -231,18 +244,37 @@ func NewEndpoint(dnsName, recordType string, targets ...string) *Endpoint {
 func NewEndpointWithTTL(dnsName, recordType string, ttl TTL, targets ...string) *Endpoint {
 	cleanTargets := make([]string, len(targets))
 	for idx, target := range targets {
+		// the names in the data of validated record types are absolute, "." is not a trailing dot
+		if IsValidatedRecordType(recordType) {
+			cleanTargets[idx] = target
+			continue
+		}
 		cleanTargets[idx] = strings.TrimSuffix(target, ".")
+		// the targets of these types are host names, which providers store in ASCII
+		if recordType == RecordTypeCNAME || recordType == RecordTypeNS || recordType == RecordTypePTR {
+			ascii, err := ToASCII(cleanTargets[idx])
+			if err != nil {
+				log.Errorf("%v. Cannot create endpoint %s", err, dnsName)
+				return nil
+			}
+			cleanTargets[idx] = ascii
+		}
 	}
 
-	for _, label := range strings.Split(dnsName, ".") {
+	asciiName, err := ToASCII(dnsName)
+	if err != nil {
+		log.Errorf("%v. Cannot create endpoint", err)
+		return nil
+	}
+	for _, label := range strings.Split(asciiName, ".") {
 		if len(label) > 63 {
-			log.Errorf("label %s in %s is longer than 63 characters. Cannot create endpoint", label, dnsName)
+			log.Errorf("label %s in %s is longer than 63 characters. Cannot create endpoint", label, DisplayName(asciiName))
 			return nil
 		}
 	}
 
 	return &Endpoint{
-		DNSName:    strings.TrimSuffix(dnsName, "."),
+		DNSName:    strings.TrimSuffix(asciiName, "."),
 		Targets:    cleanTargets,
 		RecordType: recordType,
 		Labels:     NewLabels(),
@@ -317,7 +349,7 @@ func (e *Endpoint) IsOwnedBy(ownerID string) bool {
 }
 
 func (e *Endpoint) String() string {
-	return fmt.Sprintf("%s %d IN %s %s %s %s", e.DNSName, e.RecordTTL, e.RecordType, e.SetIdentifier, e.Targets, e.ProviderSpecific)
+	return fmt.Sprintf("%s %d IN %s %s %s %s", DisplayName(e.DNSName), e.RecordTTL, e.RecordType, e.SetIdentifier, e.Targets, e.ProviderSpecific)
 }
 
 // Apply filter to slice of endpoints and return new filtered slice that includes
@@ -114,6 +114,75 @@ func TestSameFailures(t *testing.T) {
 	}
 }
 
+func TestSameAs(t *testing.T) {
+	for _, tc := range []struct {
+		title      string
+		recordType string
+		a          Targets
+		b          Targets
+		expected   bool
+	}{
+		{
+			title:      "MX targets in canonical form",
+			recordType: RecordTypeMX,
+			a:          Targets{"10 mail.example.org"},
+			b:          Targets{"10  MAIL.example.org."},
+			expected:   true,
+		},
+		{
+			title:      "MX targets with different preferences",
+			recordType: RecordTypeMX,
+			a:          Targets{"10 mail.example.org"},
+			b:          Targets{"20 mail.example.org"},
+		},
+		{
+			title:      "SRV targets in canonical form",
+			recordType: RecordTypeSRV,
+			a:          Targets{"0 50 30080 node.example.org"},
+			b:          Targets{"0 50 30080 node.example.org."},
+			expected:   true,
+		},
+		{
+			title:      "SRV targets with different ports",
+			recordType: RecordTypeSRV,
+			a:          Targets{"0 50 30080 node.example.org"},
+			b:          Targets{"0 50 30081 node.example.org."},
+		},
+		{
+			title:      "NAPTR targets in canonical form",
+			recordType: RecordTypeNAPTR,
+			a:          Targets{`100 10 "S" "SIP+D2U" "" _sip._udp.example.org`},
+			b:          Targets{`100 10 "s" "SIP+D2U" "" _SIP._udp.example.org.`},
+			expected:   true,
+		},
+		{
+			title:      "TXT targets looking like MX targets",
+			recordType: RecordTypeTXT,
+			a:          Targets{"10 mail.example.org"},
+			b:          Targets{"10 mail.example.org."},
+		},
+		{
+			title:      "CNAME targets looking like SRV targets",
+			recordType: RecordTypeCNAME,
+			a:          Targets{"0 50 30080 node.example.org"},
+			b:          Targets{"0  50 30080 node.example.org"},
+		},
+		{
+			title:      "AAAA targets in canonical form",
+			recordType: RecordTypeAAAA,
+			a:          Targets{"::1"},
+			b:          Targets{"::0001"},
+			expected:   true,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			if tc.a.SameAs(tc.recordType, tc.b) != tc.expected {
+				t.Errorf("%#v should equal %#v as %s targets: %t", tc.a, tc.b, tc.recordType, tc.expected)
+			}
+		})
+	}
+}
+
 func TestIsLess(t *testing.T) {
 	testsA := []Targets{
 		{""},
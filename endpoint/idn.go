@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// idnaProfile maps and validates internationalized domain names following IDNA2008 and UTS #46, the way
+// browsers look them up. Unlike the lookup profile of the idna package, it accepts the underscores and
+// wildcards of DNS records, e.g. "_sip._tcp" or "*".
+var idnaProfile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.Transitional(false),
+	idna.StrictDomainName(false),
+)
+
+// ToASCII returns the domain name with its Unicode labels converted to A-labels, the punycode form
+// DNS providers store, e.g. "xn--bcher-kva.example" for "bücher.example". Names which are already
+// ASCII are returned unchanged.
+func ToASCII(name string) (string, error) {
+	if isASCII(name) {
+		return name, nil
+	}
+	ascii, err := idnaProfile.ToASCII(name)
+	if err != nil {
+		return "", fmt.Errorf("invalid internationalized domain name %q: %w", name, err)
+	}
+	return ascii, nil
+}
+
+// ToUnicode returns the domain name with its A-labels converted to Unicode labels, for display. Names
+// without A-labels, or with invalid ones, are returned unchanged.
+func ToUnicode(name string) string {
+	if !strings.Contains(strings.ToLower(name), "xn--") {
+		return name
+	}
+	unicode, err := idnaProfile.ToUnicode(name)
+	if err != nil {
+		return name
+	}
+	return unicode
+}
+
+// DisplayName returns the domain name followed by its Unicode form in parentheses if it is an
+// internationalized domain name, e.g. "xn--bcher-kva.example (bücher.example)", for logs.
+func DisplayName(name string) string {
+	if unicode := ToUnicode(name); unicode != name {
+		return fmt.Sprintf("%s (%s)", name, unicode)
+	}
+	return name
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
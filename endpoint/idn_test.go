@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToASCII(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		expected string
+	}{
+		{"example.org", "example.org"},
+		{"Example.ORG", "Example.ORG"},
+		{"bücher.example", "xn--bcher-kva.example"},
+		{"Bücher.example.", "xn--bcher-kva.example."},
+		{"*.bücher.example", "*.xn--bcher-kva.example"},
+		{"_sip._tcp.bücher.example", "_sip._tcp.xn--bcher-kva.example"},
+		{"例え.テスト", "xn--r8jz45g.xn--zckzah"},
+		// IDNA2008 keeps the sharp s instead of mapping it to "ss"
+		{"faß.example", "xn--fa-hia.example"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ascii, err := ToASCII(tc.name)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, ascii)
+		})
+	}
+
+	_, err := ToASCII("bad\u0080name.example")
+	assert.Error(t, err)
+}
+
+func TestToUnicode(t *testing.T) {
+	assert.Equal(t, "bücher.example", ToUnicode("xn--bcher-kva.example"))
+	assert.Equal(t, "*.bücher.example", ToUnicode("*.xn--bcher-kva.example"))
+	assert.Equal(t, "example.org", ToUnicode("example.org"))
+	assert.Equal(t, "xn--zz.example", ToUnicode("xn--zz.example"))
+}
+
+func TestDisplayName(t *testing.T) {
+	assert.Equal(t, "xn--bcher-kva.example (bücher.example)", DisplayName("xn--bcher-kva.example"))
+	assert.Equal(t, "example.org", DisplayName("example.org"))
+}
+
+func TestNewEndpointIDN(t *testing.T) {
+	ep := NewEndpoint("www.bücher.example.", RecordTypeCNAME, "lb.bücher.example.")
+	require.NotNil(t, ep)
+	assert.Equal(t, "www.xn--bcher-kva.example", ep.DNSName)
+	assert.Equal(t, Targets{"lb.xn--bcher-kva.example"}, ep.Targets)
+	assert.Contains(t, ep.String(), "www.xn--bcher-kva.example (www.bücher.example)")
+
+	// other targets are kept as they are
+	ep = NewEndpoint("bücher.example", RecordTypeTXT, "bücher")
+	require.NotNil(t, ep)
+	assert.Equal(t, Targets{"bücher"}, ep.Targets)
+
+	assert.Nil(t, NewEndpoint("bad\u0080name.example", RecordTypeA, "192.0.2.1"))
+}
@@ -44,6 +44,25 @@ const (
 	// DualstackLabelKey is the name of the label that identifies dualstack endpoints
 	DualstackLabelKey = "dualstack"
 
+	// AccessLabelKey is the name of the label that identifies whether an endpoint is published publicly or privately
+	AccessLabelKey = "access"
+	// AccessPublic and AccessPrivate are the values of the AccessLabelKey label
+	AccessPublic  = "public"
+	AccessPrivate = "private"
+
+	// PolicyLabelKey is the name of the label that holds the policy of the k8s resource, applied to its records on top
+	// of the policy of the controller
+	PolicyLabelKey = "policy"
+
+	// PriorityLabelKey is the name of the label that holds the priority of the k8s resource, used by the priority conflict resolver
+	PriorityLabelKey = "priority"
+	// CreatedLabelKey is the name of the label that holds the RFC 3339 creation timestamp of the k8s resource, used by the
+	// oldest-resource conflict resolver
+	CreatedLabelKey = "resource-created"
+	// FlattenLabelKey is the name of the label set to "true" on the CNAME records of k8s resources requesting them to be
+	// flattened to A and AAAA records
+	FlattenLabelKey = "flatten"
+
 	// txtEncryptionNonce label for keep same nonce for same txt records, for prevent different result of encryption for same txt record, it can cause issues for some providers
 	txtEncryptionNonce = "txt-encryption-nonce"
 )
@@ -119,7 +138,8 @@ func (l Labels) SerializePlain(withQuotes bool) string {
 	sort.Strings(keys) // sort for consistency
 
 	for _, key := range keys {
-		if key == txtEncryptionNonce {
+		// the labels of the conflict resolvers, of the flattening and of the access are only used before applying the changes
+		if key == txtEncryptionNonce || key == PriorityLabelKey || key == CreatedLabelKey || key == FlattenLabelKey || key == AccessLabelKey {
 			continue
 		}
 		tokens = append(tokens, fmt.Sprintf("%s/%s=%s", heritage, key, l[key]))
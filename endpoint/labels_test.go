@@ -72,6 +72,14 @@ func (suite *LabelsSuite) TestSerialize() {
 	suite.NotEqual(suite.fooAsTextWithQuotes, suite.foo.Serialize(true, true, suite.aesKey), "should serializeLabel and encrypt")
 }
 
+func (suite *LabelsSuite) TestSerializeSkipsConflictLabels() {
+	foo := Labels{PriorityLabelKey: "10", CreatedLabelKey: "2024-01-01T00:00:00Z", FlattenLabelKey: "true", AccessLabelKey: AccessPrivate}
+	for key, value := range suite.foo {
+		foo[key] = value
+	}
+	suite.Equal(suite.fooAsText, foo.SerializePlain(false), "should not serialize the labels of the conflict resolvers")
+}
+
 func (suite *LabelsSuite) TestEncryptionNonceReUsage() {
 	foo, err := NewLabelsFromString(suite.fooAsTextEncrypted, suite.aesKey)
 	suite.NoError(err, "should succeed for valid label text")
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// validatedRecordTypes are the record types whose targets are structured data validated by ValidateTarget.
+var validatedRecordTypes = map[string]bool{
+	RecordTypeCAA:   true,
+	RecordTypeTLSA:  true,
+	RecordTypeSSHFP: true,
+	RecordTypeHTTPS: true,
+	RecordTypeSVCB:  true,
+}
+
+// IsValidatedRecordType returns whether the targets of recordType are validated by ValidateTarget.
+func IsValidatedRecordType(recordType string) bool {
+	return validatedRecordTypes[recordType]
+}
+
+// ValidateTarget parses target as the data of a record of recordType in the presentation format of
+// zone files, e.g. `0 issue "letsencrypt.org"` for a CAA record, and returns it in its canonical form,
+// the way DNS servers present it, so that the targets of the sources compare equal to the ones of the
+// providers. SRV, MX and NAPTR targets are returned in the form of their String method, TXT targets are
+// only validated, and the targets of the other record types are returned unchanged.
+func ValidateTarget(recordType, target string) (string, error) {
+	if !IsValidatedRecordType(recordType) {
+		return validateStructuredTarget(recordType, target)
+	}
+	rr, err := parseRR(recordType, target)
+	if err != nil {
+		return "", err
+	}
+
+	// the wire format drops the differences of presentation, like the case of hexadecimal data
+	buf := make([]byte, dns.Len(rr))
+	off, err := dns.PackRR(rr, buf, 0, nil, false)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s target %q: %w", recordType, target, err)
+	}
+	if rr, _, err = dns.UnpackRR(buf[:off], 0); err != nil {
+		return "", fmt.Errorf("invalid %s target %q: %w", recordType, target, err)
+	}
+	return RData(rr), nil
+}
+
+// RData returns the data of rr in presentation format, the target of its endpoint.
+func RData(rr dns.RR) string {
+	return strings.TrimPrefix(rr.String(), rr.Header().String())
+}
+
+// ValidateTargets validates the targets of the endpoint with ValidateTarget and replaces them by
+// their canonical form.
+func (e *Endpoint) ValidateTargets() error {
+	for i, target := range e.Targets {
+		canonical, err := ValidateTarget(e.RecordType, target)
+		if err != nil {
+			return err
+		}
+		e.Targets[i] = canonical
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTarget(t *testing.T) {
+	for _, tc := range []struct {
+		recordType string
+		target     string
+		expected   string
+		valid      bool
+	}{
+		{RecordTypeCAA, `0 issue "letsencrypt.org"`, `0 issue "letsencrypt.org"`, true},
+		{RecordTypeCAA, `128 iodef mailto:security@example.org`, `128 iodef "mailto:security@example.org"`, true},
+		{RecordTypeCAA, `issue letsencrypt.org`, "", false},
+		{RecordTypeTLSA, `3 1 1 0C72AC70B745AC19998811B131D662C9AC69DBDBE7CB23E5B514B56664C5D3D6`, `3 1 1 0c72ac70b745ac19998811b131d662c9ac69dbdbe7cb23e5b514b56664c5d3d6`, true},
+		{RecordTypeTLSA, `3 1 1 not-hex`, "", false},
+		{RecordTypeSSHFP, `4 2 123456789abcdef67890123456789abcdef67890123456789abcdef123456789`, `4 2 123456789ABCDEF67890123456789ABCDEF67890123456789ABCDEF123456789`, true},
+		{RecordTypeSSHFP, `4`, "", false},
+		{RecordTypeHTTPS, `1 . alpn=h2,h3 ipv4hint=192.0.2.1`, `1 . alpn="h2,h3" ipv4hint="192.0.2.1"`, true},
+		{RecordTypeHTTPS, `0 svc.example.org`, `0 svc.example.org.`, true},
+		{RecordTypeSVCB, `1 svc.example.org. port=8443`, `1 svc.example.org. port="8443"`, true},
+		{RecordTypeSVCB, `1 . port=https`, "", false},
+		{RecordTypeSRV, `0 50 30080 node.example.org.`, `0 50 30080 node.example.org`, true},
+		{RecordTypeSRV, `0 50 node.example.org`, "", false},
+		{RecordTypeMX, `10 mail.example.org`, `10 mail.example.org`, true},
+		{RecordTypeMX, `mail.example.org`, "", false},
+		{RecordTypeNAPTR, `100  10 "S" "SIP+D2U" "" _sip._udp.example.org.`, `100 10 "S" "SIP+D2U" "" _sip._udp.example.org.`, true},
+		{RecordTypeTXT, `anything goes`, `anything goes`, true},
+		{RecordTypeTXT, `"quoted" "strings"`, `"quoted" "strings"`, true},
+		{RecordTypeA, `192.0.2.1`, `192.0.2.1`, true},
+	} {
+		t.Run(tc.recordType+" "+tc.target, func(t *testing.T) {
+			canonical, err := ValidateTarget(tc.recordType, tc.target)
+			if !tc.valid {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, canonical)
+		})
+	}
+}
+
+func TestValidateTargets(t *testing.T) {
+	ep := NewEndpoint("example.org", RecordTypeCAA, `0 issue letsencrypt.org`, `0 issuewild ";"`)
+	require.NoError(t, ep.ValidateTargets())
+	assert.Equal(t, Targets{`0 issue "letsencrypt.org"`, `0 issuewild ";"`}, ep.Targets)
+
+	assert.Error(t, NewEndpoint("example.org", RecordTypeTLSA, "3 1").ValidateTargets())
+}
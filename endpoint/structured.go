@@ -0,0 +1,213 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// maxTXTStringLength is the maximum length of a character string of a TXT record.
+const maxTXTStringLength = 255
+
+// SRVTarget is the data of an SRV record, e.g. "0 50 30080 node.example.org".
+type SRVTarget struct {
+	Priority uint16
+	Weight   uint16
+	Port     uint16
+	// Target is the host name of the service, without a trailing dot, or "." if the service isn't available
+	Target string
+}
+
+// ParseSRVTarget parses the target of an SRV endpoint.
+func ParseSRVTarget(target string) (SRVTarget, error) {
+	rr, err := parseRR(RecordTypeSRV, target)
+	if err != nil {
+		return SRVTarget{}, err
+	}
+	srv := rr.(*dns.SRV)
+	return SRVTarget{Priority: srv.Priority, Weight: srv.Weight, Port: srv.Port, Target: hostTarget(srv.Target)}, nil
+}
+
+// String returns the target of the SRV endpoint.
+func (t SRVTarget) String() string {
+	return fmt.Sprintf("%d %d %d %s", t.Priority, t.Weight, t.Port, hostTarget(t.Target))
+}
+
+// MXTarget is the data of an MX record, e.g. "10 mail.example.org".
+type MXTarget struct {
+	Preference uint16
+	// Exchange is the host name of the mail server, without a trailing dot, or "." if the domain accepts no mail
+	Exchange string
+}
+
+// ParseMXTarget parses the target of an MX endpoint.
+func ParseMXTarget(target string) (MXTarget, error) {
+	rr, err := parseRR(RecordTypeMX, target)
+	if err != nil {
+		return MXTarget{}, err
+	}
+	mx := rr.(*dns.MX)
+	return MXTarget{Preference: mx.Preference, Exchange: hostTarget(mx.Mx)}, nil
+}
+
+// String returns the target of the MX endpoint.
+func (t MXTarget) String() string {
+	return fmt.Sprintf("%d %s", t.Preference, hostTarget(t.Exchange))
+}
+
+// NAPTRTarget is the data of a NAPTR record, e.g. `100 10 "S" "SIP+D2U" "" _sip._udp.example.org.`.
+type NAPTRTarget struct {
+	Order      uint16
+	Preference uint16
+	Flags      string
+	Service    string
+	Regexp     string
+	// Replacement is the fully qualified name of the next lookup, with a trailing dot like the targets
+	// of NAPTR endpoints
+	Replacement string
+}
+
+// ParseNAPTRTarget parses the target of a NAPTR endpoint.
+func ParseNAPTRTarget(target string) (NAPTRTarget, error) {
+	rr, err := parseRR(RecordTypeNAPTR, target)
+	if err != nil {
+		return NAPTRTarget{}, err
+	}
+	naptr := rr.(*dns.NAPTR)
+	return NAPTRTarget{
+		Order:       naptr.Order,
+		Preference:  naptr.Preference,
+		Flags:       naptr.Flags,
+		Service:     naptr.Service,
+		Regexp:      naptr.Regexp,
+		Replacement: naptr.Replacement,
+	}, nil
+}
+
+// String returns the target of the NAPTR endpoint.
+func (t NAPTRTarget) String() string {
+	return RData(&dns.NAPTR{
+		Hdr:         dns.RR_Header{Rrtype: dns.TypeNAPTR},
+		Order:       t.Order,
+		Preference:  t.Preference,
+		Flags:       t.Flags,
+		Service:     t.Service,
+		Regexp:      t.Regexp,
+		Replacement: dns.Fqdn(t.Replacement),
+	})
+}
+
+// TXTTarget is the data of a TXT record, the character strings of at most 255 bytes its text is split into.
+type TXTTarget []string
+
+// ParseTXTTarget parses the target of a TXT endpoint. A target made of quoted character strings,
+// e.g. `"v=spf1" " -all"`, is read in the presentation format of zone files, any other target is the
+// text of the record.
+func ParseTXTTarget(target string) (TXTTarget, error) {
+	if len(target) >= 2 && strings.HasPrefix(target, `"`) && strings.HasSuffix(target, `"`) {
+		rr, err := parseRR(RecordTypeTXT, target)
+		if err != nil {
+			return nil, err
+		}
+		return TXTTarget(rr.(*dns.TXT).Txt), nil
+	}
+
+	var strs TXTTarget
+	for len(target) > maxTXTStringLength {
+		strs = append(strs, target[:maxTXTStringLength])
+		target = target[maxTXTStringLength:]
+	}
+	return append(strs, target), nil
+}
+
+// Text returns the text of the TXT record, its character strings joined.
+func (t TXTTarget) Text() string {
+	return strings.Join(t, "")
+}
+
+// String returns the character strings of the TXT record in presentation format, quoted and escaped.
+func (t TXTTarget) String() string {
+	return RData(&dns.TXT{Hdr: dns.RR_Header{Rrtype: dns.TypeTXT}, Txt: t})
+}
+
+// parseRR parses target as the data of a record of recordType in the presentation format of zone files.
+func parseRR(recordType, target string) (dns.RR, error) {
+	rr, err := dns.NewRR(fmt.Sprintf(". 0 IN %s %s", recordType, target))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s target %q: %w", recordType, target, err)
+	}
+	if rr == nil {
+		return nil, fmt.Errorf("empty %s target", recordType)
+	}
+	return rr, nil
+}
+
+// hostTarget returns the host name of a target the way endpoints hold it, without a trailing dot.
+func hostTarget(name string) string {
+	if name == "" || name == "." {
+		return "."
+	}
+	return strings.TrimSuffix(name, ".")
+}
+
+// validateStructuredTarget returns the canonical form of an SRV, MX or NAPTR target, or validates the
+// character strings of a TXT target.
+func validateStructuredTarget(recordType, target string) (string, error) {
+	switch recordType {
+	case RecordTypeSRV:
+		srv, err := ParseSRVTarget(target)
+		if err != nil {
+			return "", err
+		}
+		return srv.String(), nil
+	case RecordTypeMX:
+		mx, err := ParseMXTarget(target)
+		if err != nil {
+			return "", err
+		}
+		return mx.String(), nil
+	case RecordTypeNAPTR:
+		naptr, err := ParseNAPTRTarget(target)
+		if err != nil {
+			return "", err
+		}
+		return naptr.String(), nil
+	case RecordTypeTXT:
+		// the quoting of TXT targets is up to their source, e.g. the registry quotes its records
+		if _, err := ParseTXTTarget(target); err != nil {
+			return "", err
+		}
+		return target, nil
+	}
+	return target, nil
+}
+
+// canonicalTarget returns the canonical form of target if it is the valid data of an SRV, MX or NAPTR
+// record of recordType, or target otherwise.
+func canonicalTarget(recordType, target string) string {
+	if recordType != RecordTypeSRV && recordType != RecordTypeMX && recordType != RecordTypeNAPTR {
+		return target
+	}
+	canonical, err := validateStructuredTarget(recordType, target)
+	if err != nil {
+		return target
+	}
+	return canonical
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSRVTarget(t *testing.T) {
+	srv, err := ParseSRVTarget("0 50 30080 node.example.org.")
+	require.NoError(t, err)
+	assert.Equal(t, SRVTarget{Priority: 0, Weight: 50, Port: 30080, Target: "node.example.org"}, srv)
+	assert.Equal(t, "0 50 30080 node.example.org", srv.String())
+
+	srv, err = ParseSRVTarget("0 0 0 .")
+	require.NoError(t, err)
+	assert.Equal(t, "0 0 0 .", srv.String())
+
+	for _, target := range []string{"0 50 node.example.org", "0 50 70000 node.example.org", "a b c d"} {
+		_, err = ParseSRVTarget(target)
+		assert.Error(t, err, target)
+	}
+}
+
+func TestParseMXTarget(t *testing.T) {
+	mx, err := ParseMXTarget("10   mail.example.org")
+	require.NoError(t, err)
+	assert.Equal(t, MXTarget{Preference: 10, Exchange: "mail.example.org"}, mx)
+	assert.Equal(t, "10 mail.example.org", mx.String())
+
+	mx, err = ParseMXTarget("0 .")
+	require.NoError(t, err)
+	assert.Equal(t, "0 .", mx.String())
+
+	for _, target := range []string{"mail.example.org", "mail.example.org 10", "-1 mail.example.org"} {
+		_, err = ParseMXTarget(target)
+		assert.Error(t, err, target)
+	}
+}
+
+func TestParseNAPTRTarget(t *testing.T) {
+	naptr, err := ParseNAPTRTarget(`100 10 "S" "SIP+D2U" "" _sip._udp.example.org.`)
+	require.NoError(t, err)
+	assert.Equal(t, NAPTRTarget{Order: 100, Preference: 10, Flags: "S", Service: "SIP+D2U", Replacement: "_sip._udp.example.org."}, naptr)
+	assert.Equal(t, `100 10 "S" "SIP+D2U" "" _sip._udp.example.org.`, naptr.String())
+
+	naptr, err = ParseNAPTRTarget(`100 10 "U" "E2U+sip" "!^.*$!sip:info@example.org!" .`)
+	require.NoError(t, err)
+	assert.Equal(t, `100 10 "U" "E2U+sip" "!^.*$!sip:info@example.org!" .`, naptr.String())
+
+	_, err = ParseNAPTRTarget(`100 "S" "SIP+D2U" "" _sip._udp.example.org.`)
+	assert.Error(t, err)
+}
+
+func TestParseTXTTarget(t *testing.T) {
+	txt, err := ParseTXTTarget("heritage=external-dns,external-dns/owner=default")
+	require.NoError(t, err)
+	assert.Equal(t, TXTTarget{"heritage=external-dns,external-dns/owner=default"}, txt)
+	assert.Equal(t, `"heritage=external-dns,external-dns/owner=default"`, txt.String())
+
+	txt, err = ParseTXTTarget(`"v=spf1 include:example.org" " -all"`)
+	require.NoError(t, err)
+	assert.Equal(t, TXTTarget{"v=spf1 include:example.org", " -all"}, txt)
+	assert.Equal(t, "v=spf1 include:example.org -all", txt.Text())
+
+	long := strings.Repeat("a", 300)
+	txt, err = ParseTXTTarget(long)
+	require.NoError(t, err)
+	assert.Equal(t, TXTTarget{strings.Repeat("a", 255), strings.Repeat("a", 45)}, txt)
+	assert.Equal(t, long, txt.Text())
+
+	_, err = ParseTXTTarget(`"unterminated" "`)
+	assert.Error(t, err)
+}
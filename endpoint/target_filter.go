@@ -18,6 +18,7 @@ package endpoint
 
 import (
 	"net"
+	"regexp"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
@@ -83,3 +84,95 @@ func matchTargetNetFilter(filters []*net.IPNet, target string, emptyval bool) bo
 
 	return false
 }
+
+// TargetHostnameFilter holds the patterns of valid hostname targets, e.g. the CNAME targets of load balancers
+type TargetHostnameFilter struct {
+	// filters define what hostname targets to match
+	filters []*regexp.Regexp
+	// exclude define what hostname targets not to match
+	exclude []*regexp.Regexp
+}
+
+// NewTargetHostnameFilterWithExclusions returns a new TargetHostnameFilter, given a list of globs of matches and
+// exclusions, e.g. "*.elb.amazonaws.com", and regular expressions of matches and exclusions, which may be nil or empty
+func NewTargetHostnameFilterWithExclusions(filters []string, exclusions []string, regexFilter *regexp.Regexp, regexExclusion *regexp.Regexp) TargetHostnameFilter {
+	tf := TargetHostnameFilter{filters: prepareTargetHostnameFilters(filters), exclude: prepareTargetHostnameFilters(exclusions)}
+	if regexFilter != nil && regexFilter.String() != "" {
+		tf.filters = append(tf.filters, regexFilter)
+	}
+	if regexExclusion != nil && regexExclusion.String() != "" {
+		tf.exclude = append(tf.exclude, regexExclusion)
+	}
+	return tf
+}
+
+// prepareTargetHostnameFilters compiles the globs of hostnames
+func prepareTargetHostnameFilters(globs []string) []*regexp.Regexp {
+	fs := make([]*regexp.Regexp, 0)
+	for _, glob := range globs {
+		if strings.TrimSpace(glob) == "" {
+			continue
+		}
+		fs = append(fs, globRegexp(glob))
+	}
+	return fs
+}
+
+// globRegexp returns the regular expression matching the hostnames of glob, whose "*" match any sequence
+// of characters, including dots. Each "*" is a group of the regular expression.
+func globRegexp(glob string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(normalizeTargetHostname(glob))
+	return regexp.MustCompile("^" + strings.ReplaceAll(quoted, `\*`, "(.*)") + "$")
+}
+
+// normalizeTargetHostname returns the hostname in lower case, without a trailing dot.
+func normalizeTargetHostname(hostname string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(hostname), "."))
+}
+
+// Match checks whether a target can be found in the TargetHostnameFilter. IP targets only match an empty filter.
+func (tf TargetHostnameFilter) Match(target string) bool {
+	return matchTargetHostnameFilter(tf.filters, target, true) && !matchTargetHostnameFilter(tf.exclude, target, false)
+}
+
+// matchTargetHostnameFilter determines if any `filters` match the hostname `target`, IP targets never match.
+// If no `filters` are provided, behavior depends on `emptyval`.
+func matchTargetHostnameFilter(filters []*regexp.Regexp, target string, emptyval bool) bool {
+	if len(filters) == 0 {
+		return emptyval
+	}
+	if net.ParseIP(target) != nil {
+		return false
+	}
+
+	hostname := normalizeTargetHostname(target)
+	for _, filter := range filters {
+		if filter.MatchString(hostname) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TargetFilter matches IP targets against nets and hostname targets against hostname patterns.
+// A target matches if no filter is given or if any net or hostname filter matches it, unless an exclusion matches it.
+type TargetFilter struct {
+	Nets      TargetNetFilter
+	Hostnames TargetHostnameFilter
+}
+
+// NewTargetFilter returns a new TargetFilter combining the net and hostname filters
+func NewTargetFilter(nets TargetNetFilter, hostnames TargetHostnameFilter) TargetFilter {
+	return TargetFilter{Nets: nets, Hostnames: hostnames}
+}
+
+// Match checks whether a target can be found in the TargetFilter.
+func (tf TargetFilter) Match(target string) bool {
+	included := len(tf.Nets.FilterNets) == 0 && len(tf.Hostnames.filters) == 0 ||
+		matchTargetNetFilter(tf.Nets.FilterNets, target, false) ||
+		matchTargetHostnameFilter(tf.Hostnames.filters, target, false)
+	return included &&
+		!matchTargetNetFilter(tf.Nets.excludeNets, target, false) &&
+		!matchTargetHostnameFilter(tf.Hostnames.exclude, target, false)
+}
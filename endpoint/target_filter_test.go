@@ -17,6 +17,7 @@ limitations under the License.
 package endpoint
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -94,3 +95,48 @@ func TestMatchTargetFilterReturnsProperEmptyVal(t *testing.T) {
 	assert.Equal(t, true, matchFilter(emptyFilters, "sometarget.com", true))
 	assert.Equal(t, false, matchFilter(emptyFilters, "sometarget.com", false))
 }
+
+func TestTargetHostnameFilter(t *testing.T) {
+	for _, tc := range []struct {
+		title          string
+		filters        []string
+		exclusions     []string
+		regexFilter    *regexp.Regexp
+		regexExclusion *regexp.Regexp
+		target         string
+		expected       bool
+	}{
+		{title: "empty filter", target: "lb.example.com", expected: true},
+		{title: "glob", filters: []string{"*.elb.amazonaws.com"}, target: "internal-lb.eu-west-1.ELB.amazonaws.com.", expected: true},
+		{title: "glob not matching", filters: []string{"*.elb.amazonaws.com"}, target: "lb.example.com", expected: false},
+		{title: "glob matches hostnames only", filters: []string{"*"}, target: "10.1.2.3", expected: false},
+		{title: "excluded glob", exclusions: []string{"internal-*"}, target: "internal-lb.example.com", expected: false},
+		{title: "regex", regexFilter: regexp.MustCompile(`^lb-\d+\.example\.com$`), target: "lb-1.example.com", expected: true},
+		{title: "regex not matching", regexFilter: regexp.MustCompile(`^lb-\d+\.example\.com$`), target: "lb-a.example.com", expected: false},
+		{title: "excluded regex", regexExclusion: regexp.MustCompile(`\.internal$`), target: "lb.internal", expected: false},
+		{title: "empty regex", regexFilter: regexp.MustCompile(""), target: "lb.example.com", expected: true},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			filter := NewTargetHostnameFilterWithExclusions(tc.filters, tc.exclusions, tc.regexFilter, tc.regexExclusion)
+			assert.Equal(t, tc.expected, filter.Match(tc.target))
+		})
+	}
+}
+
+func TestTargetFilter(t *testing.T) {
+	nets := NewTargetNetFilterWithExclusions([]string{"10.0.0.0/8"}, []string{"10.1.0.0/16"})
+	hostnames := NewTargetHostnameFilterWithExclusions([]string{"*.elb.amazonaws.com"}, []string{"internal-*"}, nil, nil)
+	filter := NewTargetFilter(nets, hostnames)
+
+	assert.True(t, filter.Match("10.2.3.4"))
+	assert.False(t, filter.Match("10.1.2.3"))
+	assert.False(t, filter.Match("1.2.3.4"))
+	assert.True(t, filter.Match("lb.eu-west-1.elb.amazonaws.com"))
+	assert.False(t, filter.Match("internal-lb.eu-west-1.elb.amazonaws.com"))
+	assert.False(t, filter.Match("lb.example.com"))
+
+	// without hostname filters, hostname targets don't match the net filters, as before
+	netsOnly := NewTargetFilter(nets, TargetHostnameFilter{})
+	assert.Equal(t, nets.Match("lb.example.com"), netsOnly.Match("lb.example.com"))
+	assert.True(t, NewTargetFilter(TargetNetFilter{}, TargetHostnameFilter{}).Match("lb.example.com"))
+}
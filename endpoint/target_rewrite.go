@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strings"
+)
+
+// TargetRewriteRule rewrites targets, e.g. the hostname of an internal load balancer to the hostname of
+// a CDN, or the addresses of a network to the addresses they are translated to by 1:1 NAT.
+type TargetRewriteRule struct {
+	// rule is the rule as configured
+	rule string
+	// from and to rewrite the targets of CNAME endpoints, the match of the "*" of from replaces the one of to
+	from *regexp.Regexp
+	to   string
+	// fromNet and toNet rewrite the targets of A and AAAA endpoints, keeping their host bits
+	fromNet netip.Prefix
+	toNet   netip.Prefix
+}
+
+// ParseTargetRewriteRules parses rules of the form "from=to". from and to are either hostnames, from
+// possibly with a "*" whose match replaces the "*" of to, e.g. "*.lb.internal=*.cdn.example.com", or
+// networks of the same family and size, e.g. "10.0.0.0/24=203.0.113.0/24".
+func ParseTargetRewriteRules(rules []string) ([]TargetRewriteRule, error) {
+	result := make([]TargetRewriteRule, 0, len(rules))
+	for _, rule := range rules {
+		parsed, err := ParseTargetRewriteRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, parsed)
+	}
+	return result, nil
+}
+
+// ParseTargetRewriteRule parses a rule of the form "from=to", see ParseTargetRewriteRules.
+func ParseTargetRewriteRule(rule string) (TargetRewriteRule, error) {
+	from, to, found := strings.Cut(rule, "=")
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if !found || from == "" || to == "" {
+		return TargetRewriteRule{}, fmt.Errorf("invalid target rewrite %q, expected from=to", rule)
+	}
+
+	if fromNet, err := netip.ParsePrefix(from); err == nil {
+		toNet, err := netip.ParsePrefix(to)
+		if err != nil {
+			return TargetRewriteRule{}, fmt.Errorf("invalid target rewrite %q: %s is not a network like %s", rule, to, from)
+		}
+		if fromNet.Addr().Is4() != toNet.Addr().Is4() || fromNet.Bits() != toNet.Bits() {
+			return TargetRewriteRule{}, fmt.Errorf("invalid target rewrite %q: the networks must be of the same family and size", rule)
+		}
+		return TargetRewriteRule{rule: rule, fromNet: fromNet.Masked(), toNet: toNet.Masked()}, nil
+	}
+
+	wildcards := strings.Count(from, "*")
+	if wildcards > 1 {
+		return TargetRewriteRule{}, fmt.Errorf("invalid target rewrite %q: only one * is supported", rule)
+	}
+	if strings.Count(to, "*") > wildcards {
+		return TargetRewriteRule{}, fmt.Errorf("invalid target rewrite %q: %s has no * to replace the one of %s", rule, from, to)
+	}
+	return TargetRewriteRule{rule: rule, from: globRegexp(from), to: normalizeTargetHostname(to)}, nil
+}
+
+// Rewrite returns the target of an endpoint of recordType rewritten by the rule, and whether the rule applies to it.
+func (r TargetRewriteRule) Rewrite(recordType, target string) (string, bool) {
+	switch {
+	case r.from != nil && recordType == RecordTypeCNAME:
+		match := r.from.FindStringSubmatch(normalizeTargetHostname(target))
+		if match == nil {
+			return target, false
+		}
+		if len(match) > 1 {
+			return strings.Replace(r.to, "*", match[1], 1), true
+		}
+		return r.to, true
+	case r.fromNet.IsValid() && (recordType == RecordTypeA || recordType == RecordTypeAAAA):
+		addr, err := netip.ParseAddr(target)
+		if err != nil || !r.fromNet.Contains(addr) {
+			return target, false
+		}
+		return translateAddr(addr, r.toNet).String(), true
+	}
+	return target, false
+}
+
+// String returns the rule in the form it is parsed from.
+func (r TargetRewriteRule) String() string {
+	return r.rule
+}
+
+// translateAddr returns the address of network with the host bits of addr.
+func translateAddr(addr netip.Addr, network netip.Prefix) netip.Addr {
+	bytes := addr.AsSlice()
+	networkBytes := network.Addr().AsSlice()
+	bits := network.Bits()
+	for i := range bytes {
+		var mask byte
+		switch {
+		case bits >= 8:
+			mask = 0xff
+		case bits > 0:
+			mask = ^byte(0xff >> bits)
+		}
+		bytes[i] = networkBytes[i]&mask | bytes[i]&^mask
+		bits -= 8
+	}
+	translated, _ := netip.AddrFromSlice(bytes)
+	return translated
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetRewriteRule(t *testing.T) {
+	for _, tc := range []struct {
+		title      string
+		rule       string
+		recordType string
+		target     string
+		expected   string
+		rewritten  bool
+	}{
+		{
+			title:      "hostname",
+			rule:       "lb.internal=cdn.example.com",
+			recordType: RecordTypeCNAME,
+			target:     "LB.internal.",
+			expected:   "cdn.example.com",
+			rewritten:  true,
+		},
+		{
+			title:      "hostname wildcard",
+			rule:       "*.lb.internal=*.cdn.example.com",
+			recordType: RecordTypeCNAME,
+			target:     "shop.lb.internal",
+			expected:   "shop.cdn.example.com",
+			rewritten:  true,
+		},
+		{
+			title:      "hostname wildcard to a single hostname",
+			rule:       "*.lb.internal=cdn.example.com",
+			recordType: RecordTypeCNAME,
+			target:     "shop.lb.internal",
+			expected:   "cdn.example.com",
+			rewritten:  true,
+		},
+		{
+			title:      "hostname not matching",
+			rule:       "*.lb.internal=*.cdn.example.com",
+			recordType: RecordTypeCNAME,
+			target:     "lb.example.com",
+			expected:   "lb.example.com",
+		},
+		{
+			title:      "hostname rules only apply to CNAME",
+			rule:       "*=cdn.example.com",
+			recordType: RecordTypeA,
+			target:     "10.0.0.1",
+			expected:   "10.0.0.1",
+		},
+		{
+			title:      "IPv4 network",
+			rule:       "10.0.0.0/24=203.0.113.0/24",
+			recordType: RecordTypeA,
+			target:     "10.0.0.42",
+			expected:   "203.0.113.42",
+			rewritten:  true,
+		},
+		{
+			title:      "IPv4 network not byte aligned",
+			rule:       "10.0.0.0/20=198.51.96.0/20",
+			recordType: RecordTypeA,
+			target:     "10.0.9.1",
+			expected:   "198.51.105.1",
+			rewritten:  true,
+		},
+		{
+			title:      "address outside of the network",
+			rule:       "10.0.0.0/24=203.0.113.0/24",
+			recordType: RecordTypeA,
+			target:     "10.0.1.42",
+			expected:   "10.0.1.42",
+		},
+		{
+			title:      "IPv6 network",
+			rule:       "fd00::/64=2001:db8:1:2::/64",
+			recordType: RecordTypeAAAA,
+			target:     "fd00::abcd",
+			expected:   "2001:db8:1:2::abcd",
+			rewritten:  true,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			rule, err := ParseTargetRewriteRule(tc.rule)
+			require.NoError(t, err)
+			assert.Equal(t, tc.rule, rule.String())
+			target, rewritten := rule.Rewrite(tc.recordType, tc.target)
+			assert.Equal(t, tc.expected, target)
+			assert.Equal(t, tc.rewritten, rewritten)
+		})
+	}
+}
+
+func TestParseTargetRewriteRulesErrors(t *testing.T) {
+	for _, rule := range []string{
+		"lb.internal",
+		"=cdn.example.com",
+		"10.0.0.0/24=cdn.example.com",
+		"10.0.0.0/24=203.0.113.0/25",
+		"10.0.0.0/24=2001:db8::/24",
+		"*.*.internal=*.example.com",
+		"lb.internal=*.example.com",
+	} {
+		_, err := ParseTargetRewriteRules([]string{"a.internal=b.example.com", rule})
+		assert.Error(t, err, rule)
+	}
+}
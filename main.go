@@ -18,10 +18,13 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -39,6 +42,7 @@ import (
 	"sigs.k8s.io/external-dns/endpoint"
 	"sigs.k8s.io/external-dns/pkg/apis/externaldns"
 	"sigs.k8s.io/external-dns/pkg/apis/externaldns/validation"
+	"sigs.k8s.io/external-dns/pkg/tracing"
 	"sigs.k8s.io/external-dns/plan"
 	"sigs.k8s.io/external-dns/provider"
 	"sigs.k8s.io/external-dns/provider/akamai"
@@ -77,6 +81,9 @@ import (
 	"sigs.k8s.io/external-dns/source"
 )
 
+// configReloadInterval is the interval between two checks of the configuration file for changes.
+const configReloadInterval = 10 * time.Second
+
 func main() {
 	cfg := externaldns.NewConfig()
 	if err := cfg.ParseFlags(os.Args[1:]); err != nil {
@@ -108,9 +115,27 @@ func main() {
 
 	ctx, cancel := context.WithCancel(context.Background())
 
-	go serveMetrics(cfg.MetricsAddress)
 	go handleSigterm(cancel)
 
+	if cfg.Tracing {
+		shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+			Endpoint:    cfg.TracingEndpoint,
+			Insecure:    cfg.TracingInsecure,
+			SampleRatio: cfg.TracingSampleRatio,
+			Version:     externaldns.Version,
+		})
+		if err != nil {
+			log.Fatalf("failed to set up tracing: %v", err)
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdownTracing(ctx); err != nil {
+				log.Errorf("Failed to flush traces: %v", err)
+			}
+		}()
+	}
+
 	// error is explicitly ignored because the filter is already validated in validation.ValidateConfig
 	labelSelector, _ := labels.Parse(cfg.LabelFilter)
 
@@ -149,10 +174,11 @@ func main() {
 		ResolveLoadBalancerHostname:    cfg.ResolveServiceLoadBalancerHostname,
 		TraefikDisableLegacy:           cfg.TraefikDisableLegacy,
 		TraefikDisableNew:              cfg.TraefikDisableNew,
+		ZoneFile:                       cfg.ZoneFile,
+		ZoneFileOrigin:                 cfg.ZoneFileOrigin,
 	}
 
-	// Lookup all the selected sources by names and pass them the desired configuration.
-	sources, err := source.ByNames(ctx, &source.SingletonClientGenerator{
+	var clientGenerator source.ClientGenerator = &source.SingletonClientGenerator{
 		KubeConfig:   cfg.KubeConfig,
 		APIServerURL: cfg.APIServerURL,
 		// If update events are enabled, disable timeout.
@@ -162,33 +188,351 @@ func main() {
 			}
 			return cfg.RequestTimeout
 		}(),
-	}, cfg.Sources, sourceCfg)
+	}
+	if cfg.Command == externaldns.CommandSimulate {
+		// The sources read the manifests instead of a cluster.
+		clientGenerator, err = source.NewManifestClientGenerator(cfg.SimulateManifests)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var leaderElection *controller.LeaderElection
+	if cfg.LeaderElect && !cfg.Once && cfg.Command == externaldns.CommandRun {
+		kubeClient, err := clientGenerator.KubeClient()
+		if err != nil {
+			log.Fatal(err)
+		}
+		leaderElection, err = controller.NewLeaderElection(controller.LeaderElectionConfig{
+			Client:        kubeClient,
+			Namespace:     cfg.LeaderElectionNamespace,
+			Name:          cfg.LeaderElectionLeaseName,
+			LeaseDuration: cfg.LeaderElectionLeaseDuration,
+			RenewDeadline: cfg.LeaderElectionRenewDeadline,
+			RetryPeriod:   cfg.LeaderElectionRetryPeriod,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	state := &syncState{maxSyncAge: cfg.ReadinessMaxSyncAge}
+	if cfg.Command == externaldns.CommandRun {
+		go serveMetrics(cfg.MetricsAddress, leaderElection, state, cfg.DebugEndpoints)
+	}
+
+	// Lookup all the selected sources by names and pass them the desired configuration.
+	// Standby replicas create the sources as well, so their informers are in sync when taking over.
+	sources, err := source.ByNames(ctx, clientGenerator, cfg.Sources, sourceCfg)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	// Filter targets
-	targetFilter := endpoint.NewTargetNetFilterWithExclusions(cfg.TargetNetFilter, cfg.ExcludeTargetNets)
+	// Filter and rewrite targets
+	targetFilter := endpoint.NewTargetFilter(
+		endpoint.NewTargetNetFilterWithExclusions(cfg.TargetNetFilter, cfg.ExcludeTargetNets),
+		endpoint.NewTargetHostnameFilterWithExclusions(cfg.TargetHostFilter, cfg.ExcludeTargetHosts, cfg.RegexTargetHostFilter, cfg.RegexTargetHostExclusion),
+	)
+	targetRewrites, err := endpoint.ParseTargetRewriteRules(cfg.TargetRewrites)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// Combine multiple sources into a single, deduplicated source.
 	endpointsSource := source.NewDedupSource(source.NewMultiSource(sources, sourceCfg.DefaultTargets))
+	if cfg.FlattenCNAMEs {
+		endpointsSource = source.NewFlattenSource(endpointsSource, cfg.FlattenCNAMEZones, source.NewResolver(cfg.FlattenCNAMEResolver))
+	}
 	endpointsSource = source.NewNAT64Source(endpointsSource, cfg.NAT64Networks)
 	endpointsSource = source.NewTargetFilterSource(endpointsSource, targetFilter)
+	endpointsSource = source.NewTargetRewriteSource(endpointsSource, targetRewrites)
+	// The controllers of the providers share one evaluation of the sources per synchronization.
+	if len(cfg.ProviderRoutes) > 0 || cfg.SplitHorizonPrivateProvider != "" {
+		endpointsSource = source.NewSharedSource(endpointsSource, cfg.Interval/2)
+	}
+
+	domainFilter := newDomainFilter(cfg)
+
+	if cfg.Command == externaldns.CommandSimulate {
+		if err := simulate(ctx, cfg, endpointsSource, domainFilter, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	routes, err := controller.NewProviderRoutes(cfg.ProviderRoutes)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	p, err := buildProvider(ctx, cfg, cfg.Provider, domainFilter, endpointsSource)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if cfg.WebhookServer {
+		webhookapi.StartHTTPApi(p, nil, cfg.WebhookProviderReadTimeout, cfg.WebhookProviderWriteTimeout, "127.0.0.1:8888")
+		os.Exit(0)
+	}
+
+	policy, exists := plan.Policies[cfg.Policy]
+	if !exists {
+		log.Fatalf("unknown policy: %s", cfg.Policy)
+	}
+
+	if cfg.Command == externaldns.CommandMigrate {
+		if err := migrate(ctx, cfg, p, endpointsSource, domainFilter, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// In split-horizon mode, the private endpoints are published by the private provider only.
+	publicSource := endpointsSource
+	if cfg.SplitHorizonPrivateProvider != "" {
+		publicSource = source.NewAccessFilterSource(endpointsSource, endpoint.AccessPublic)
+	}
+
+	providerFilters := providerDomainFilters(cfg, routes)
+	ctrlDomainFilters := controllerDomainFilters(cfg, routes)
+	ctrlConfigs, err := controllerConfigs(cfg, routes)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctrl, err := newController(cfg, cfg.Provider, p, policy, publicSource, ctrlDomainFilters[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	router := &controller.Router{Controllers: []*controller.Controller{ctrl}}
+
+	for i, route := range routes {
+		instanceCfg := ctrlConfigs[i+1]
+		p, err := buildProvider(ctx, instanceCfg, instanceCfg.Provider, providerFilters[i+1], endpointsSource)
+		if err != nil {
+			log.Fatal(err)
+		}
+		ctrl, err := newController(instanceCfg, route.Name, p, plan.Policies[instanceCfg.Policy], publicSource, ctrlDomainFilters[i+1])
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Infof("Routing %v to provider instance %s (%s)", route.Domains, route.Name, instanceCfg.Provider)
+		router.Controllers = append(router.Controllers, ctrl)
+	}
+
+	if cfg.SplitHorizonPrivateProvider != "" {
+		instanceCfg := ctrlConfigs[len(routes)+1]
+		p, err := buildProvider(ctx, instanceCfg, instanceCfg.Provider, providerFilters[len(routes)+1], endpointsSource)
+		if err != nil {
+			log.Fatal(err)
+		}
+		privateSource := source.NewAccessFilterSource(endpointsSource, endpoint.AccessPrivate)
+		ctrl, err := newController(instanceCfg, cfg.SplitHorizonPrivateProvider, p, plan.Policies[instanceCfg.Policy], privateSource, ctrlDomainFilters[len(routes)+1])
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Infof("Publishing private endpoints to provider instance %s (%s)", cfg.SplitHorizonPrivateProvider, instanceCfg.Provider)
+		router.Controllers = append(router.Controllers, ctrl)
+	}
+
+	if cfg.Command != externaldns.CommandRun {
+		if err := runCommand(ctx, cfg, router, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	state.router.Store(router)
+
+	if cfg.Once {
+		err := router.RunOnce(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		os.Exit(0)
+	}
+
+	if cfg.ConfigFile != "" {
+		running := cfg
+		go externaldns.WatchConfigFile(ctx, cfg.ConfigFile, configReloadInterval, func() {
+			running = reloadConfig(cfg, running, router, endpointsSource, routes)
+		})
+	}
+
+	if cfg.UpdateEvents {
+		// Add RunOnce as the handler function that will be called when ingress/service sources have changed.
+		// Note that k8s Informers will perform an initial list operation, which results in the handler
+		// function initially being called for every Service/Ingress that exists
+		source.AddResourceEventHandler(ctx, endpointsSource, func(resource string) {
+			router.ResourceChanged(resource)
+			router.ScheduleRunOnce(time.Now())
+		})
+	}
+
+	router.ScheduleRunOnce(time.Now())
+	if leaderElection == nil {
+		router.Run(ctx)
+		return
+	}
+
+	err = leaderElection.Run(ctx, router.Run, func() {
+		// Another replica may already apply changes, so stop here and let the pod be restarted as standby.
+		log.Fatal("leader election lost")
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
 
-	// RegexDomainFilter overrides DomainFilter
-	var domainFilter endpoint.DomainFilter
+// newDomainFilter returns the domain filter of cfg, RegexDomainFilter overrides DomainFilter.
+func newDomainFilter(cfg *externaldns.Config) endpoint.DomainFilter {
 	if cfg.RegexDomainFilter.String() != "" {
-		domainFilter = endpoint.NewRegexDomainFilter(cfg.RegexDomainFilter, cfg.RegexDomainExclusion)
-	} else {
-		domainFilter = endpoint.NewDomainFilterWithExclusions(cfg.DomainFilter, cfg.ExcludeDomains)
+		return endpoint.NewRegexDomainFilter(cfg.RegexDomainFilter, cfg.RegexDomainExclusion)
+	}
+	return endpoint.NewDomainFilterWithExclusions(cfg.DomainFilter, cfg.ExcludeDomains)
+}
+
+// providerDomainFilters returns the domain filters the providers select their zones with, in the order
+// of the router: the default provider, the provider routes and the split-horizon private provider.
+func providerDomainFilters(cfg *externaldns.Config, routes []controller.ProviderRoute) []endpoint.DomainFilter {
+	domainFilter := newDomainFilter(cfg)
+	filters := []endpoint.DomainFilter{domainFilter}
+	for _, route := range routes {
+		filters = append(filters, route.DomainFilter(routes, cfg.ExcludeDomains))
 	}
+	if cfg.SplitHorizonPrivateProvider != "" {
+		filters = append(filters, domainFilter)
+	}
+	return filters
+}
+
+// controllerDomainFilters returns the domain filters of the controllers in the order of the router,
+// the ones of their providers.
+func controllerDomainFilters(cfg *externaldns.Config, routes []controller.ProviderRoute) []endpoint.DomainFilterInterface {
+	var filters []endpoint.DomainFilterInterface
+	for _, filter := range providerDomainFilters(cfg, routes) {
+		filters = append(filters, filter)
+	}
+	// The default provider manages the DNS names which are not routed to another provider.
+	if len(routes) > 0 {
+		filters[0] = endpoint.MatchAllDomainFilters{filters[0], controller.UnroutedDomainFilter(routes)}
+	}
+	return filters
+}
+
+// controllerConfigs returns the configurations of the controllers in the order of the router. The
+// provider instances configured by --provider-instance have the configuration of their file, the
+// others the configuration of the default provider.
+func controllerConfigs(cfg *externaldns.Config, routes []controller.ProviderRoute) ([]*externaldns.Config, error) {
+	names := make([]string, 0, len(routes)+1)
+	for _, route := range routes {
+		names = append(names, route.Name)
+	}
+	if cfg.SplitHorizonPrivateProvider != "" {
+		names = append(names, cfg.SplitHorizonPrivateProvider)
+	}
+
+	configs := []*externaldns.Config{cfg}
+	for _, name := range names {
+		path, ok := cfg.ProviderInstances[name]
+		if !ok {
+			instanceCfg := *cfg
+			instanceCfg.Provider = name
+			configs = append(configs, &instanceCfg)
+			continue
+		}
+		instanceCfg, err := externaldns.NewInstanceConfig(os.Args[1:], path)
+		if err != nil {
+			return nil, err
+		}
+		if err := validation.ValidateConfig(instanceCfg); err != nil {
+			return nil, fmt.Errorf("invalid provider instance %s: %w", name, err)
+		}
+		if _, exists := plan.Policies[instanceCfg.Policy]; !exists {
+			return nil, fmt.Errorf("invalid provider instance %s: unknown policy: %s", name, instanceCfg.Policy)
+		}
+		configs = append(configs, instanceCfg)
+	}
+	return configs, nil
+}
+
+// reloadConfig parses the flags again with the changed configuration file, and applies the reloadable
+// settings which differ from the running ones. It returns the configuration now running.
+func reloadConfig(initial, running *externaldns.Config, router *controller.Router, endpointsSource source.Source, routes []controller.ProviderRoute) *externaldns.Config {
+	cfg := externaldns.NewConfig()
+	if err := cfg.ParseFlags(os.Args[1:]); err != nil {
+		log.Errorf("Ignoring the changed config file: %v", err)
+		return running
+	}
+	if err := validation.ValidateConfig(cfg); err != nil {
+		log.Errorf("Ignoring the changed config file: %v", err)
+		return running
+	}
+	policy, exists := plan.Policies[cfg.Policy]
+	if !exists {
+		log.Errorf("Ignoring the changed config file: unknown policy: %s", cfg.Policy)
+		return running
+	}
+	if fields := initial.RestartRequired(cfg); len(fields) > 0 {
+		log.Warnf("Ignoring the changes of %s, which require a restart", strings.Join(fields, ", "))
+	}
+
+	// The controllers of the provider instances are created at start.
+	instances := *cfg
+	instances.SplitHorizonPrivateProvider = initial.SplitHorizonPrivateProvider
+	instances.ProviderInstances = initial.ProviderInstances
+	ctrlConfigs, err := controllerConfigs(&instances, routes)
+	if err != nil {
+		log.Errorf("Ignoring the changed config file: %v", err)
+		return running
+	}
+
+	if cfg.AnnotationFilter != running.AnnotationFilter || cfg.LabelFilter != running.LabelFilter {
+		// error is explicitly ignored because the filter is already validated in validation.ValidateConfig
+		labelSelector, _ := labels.Parse(cfg.LabelFilter)
+		if err := source.UpdateFilters(endpointsSource, cfg.AnnotationFilter, labelSelector); err != nil {
+			log.Errorf("Ignoring the changed config file, the annotation and label filters require a restart: %v", err)
+			return running
+		}
+	}
+
+	// The providers keep managing the zones they selected at start, the DNS names matched by the
+	// changed domain filters outside of these zones are not reconciled until restarted.
+	initialFilters := providerDomainFilters(initial, routes)
+	for i, filter := range providerDomainFilters(&instances, routes) {
+		if !filter.Within(initialFilters[i]) {
+			log.Warnf("The changed domain filters of the provider for %q match DNS names outside of the zones it selected at start, which are ignored until ExternalDNS is restarted", router.Controllers[i].ProviderName)
+		}
+	}
+	ctrlDomainFilters := controllerDomainFilters(&instances, routes)
+
+	for i, ctrl := range router.Controllers {
+		ctrlCfg := ctrlConfigs[i]
+		if i > 0 {
+			policy = plan.Policies[ctrlCfg.Policy]
+		}
+		ctrl.Reconfigure(controller.Settings{
+			Policy:             policy,
+			Interval:           ctrlCfg.Interval,
+			DomainFilter:       ctrlDomainFilters[i],
+			ManagedRecordTypes: ctrlCfg.ManagedDNSRecordTypes,
+			ExcludeRecordTypes: ctrlCfg.ExcludeDNSRecordTypes,
+		})
+	}
+	log.Info("Applied the changed config file")
+	return cfg
+}
+
+// buildProvider creates the DNS provider called name, managing the zones matched by domainFilter.
+func buildProvider(ctx context.Context, cfg *externaldns.Config, name string, domainFilter endpoint.DomainFilter, endpointsSource source.Source) (provider.Provider, error) {
 	zoneNameFilter := endpoint.NewDomainFilter(cfg.ZoneNameFilter)
 	zoneIDFilter := provider.NewZoneIDFilter(cfg.ZoneIDFilter)
 	zoneTypeFilter := provider.NewZoneTypeFilter(cfg.AWSZoneType)
 	zoneTagFilter := provider.NewZoneTagFilter(cfg.AWSZoneTagFilter)
 
 	var p provider.Provider
-	switch cfg.Provider {
+	var err error
+	switch name {
 	case "akamai":
 		p, err = akamai.NewAkamaiProvider(
 			akamai.AkamaiConfig{
@@ -230,11 +574,6 @@ func main() {
 			clients,
 		)
 	case "aws-sd":
-		// Check that only compatible Registry is used with AWS-SD
-		if cfg.Registry != "noop" && cfg.Registry != "aws-sd" {
-			log.Infof("Registry \"%s\" cannot be used with AWS Cloud Map. Switching to \"aws-sd\".", cfg.Registry)
-			cfg.Registry = "aws-sd"
-		}
 		p, err = awssd.NewAWSSDProvider(domainFilter, cfg.AWSZoneType, cfg.DryRun, cfg.AWSSDServiceCleanup, cfg.TXTOwnerID, cfg.AWSSDCreateTag, sd.NewFromConfig(aws.CreateDefaultV2Config(cfg)))
 	case "azure-dns", "azure":
 		p, err = azure.NewAzureProvider(cfg.AzureConfigFile, domainFilter, zoneNameFilter, zoneIDFilter, cfg.AzureSubscriptionID, cfg.AzureResourceGroup, cfg.AzureUserAssignedIdentityClientID, cfg.AzureActiveDirectoryAuthorityHost, cfg.AzureZonesCacheDuration, cfg.DryRun)
@@ -362,17 +701,25 @@ func main() {
 	case "webhook":
 		p, err = webhook.NewWebhookProvider(cfg.WebhookProviderURL)
 	default:
-		log.Fatalf("unknown dns provider: %s", cfg.Provider)
-	}
-	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("unknown dns provider: %s", name)
 	}
+	return p, err
+}
 
-	if cfg.WebhookServer {
-		webhookapi.StartHTTPApi(p, nil, cfg.WebhookProviderReadTimeout, cfg.WebhookProviderWriteTimeout, "127.0.0.1:8888")
-		os.Exit(0)
+// newController creates the registry of the provider p and the controller reconciling
+// the DNS names matched by domainFilter with it.
+func newController(cfg *externaldns.Config, providerName string, p provider.Provider, policy plan.Policy, endpointsSource source.Source, domainFilter endpoint.DomainFilterInterface) (*controller.Controller, error) {
+	var propertyComparisons map[string]plan.PropertyComparison
+	if comparer, ok := p.(provider.PropertyComparisonProvider); ok {
+		propertyComparisons = comparer.PropertyComparisons()
+	}
+	if cfg.ApplyConcurrency > 1 {
+		if parallel, err := provider.NewZoneParallelProvider(p, cfg.ApplyConcurrency); err != nil {
+			log.Warnf("Ignoring --apply-concurrency=%d for provider %s, applying the changes of zones one after the other: %v", cfg.ApplyConcurrency, providerName, err)
+		} else {
+			p = parallel
+		}
 	}
-
 	if cfg.ProviderCacheTime > 0 {
 		p = provider.NewCachedProvider(
 			p,
@@ -380,8 +727,16 @@ func main() {
 		)
 	}
 
+	registryName := cfg.Registry
+	// Check that only compatible Registry is used with AWS-SD
+	if providerName == "aws-sd" && registryName != "noop" && registryName != "aws-sd" {
+		log.Infof("Registry \"%s\" cannot be used with AWS Cloud Map. Switching to \"aws-sd\".", registryName)
+		registryName = "aws-sd"
+	}
+
 	var r registry.Registry
-	switch cfg.Registry {
+	var err error
+	switch registryName {
 	case "dynamodb":
 		var dynamodbOpts []func(*dynamodb.Options)
 		if cfg.AWSDynamoDBRegion != "" {
@@ -395,51 +750,48 @@ func main() {
 	case "noop":
 		r, err = registry.NewNoopRegistry(p)
 	case "txt":
-		r, err = registry.NewTXTRegistry(p, cfg.TXTPrefix, cfg.TXTSuffix, cfg.TXTOwnerID, cfg.TXTCacheInterval, cfg.TXTWildcardReplacement, cfg.ManagedDNSRecordTypes, cfg.ExcludeDNSRecordTypes, cfg.TXTEncryptEnabled, []byte(cfg.TXTEncryptAESKey))
+		var legacySchemes []registry.TXTNameScheme
+		for _, prefix := range cfg.TXTLegacyPrefixes {
+			legacySchemes = append(legacySchemes, registry.TXTNameScheme{Prefix: prefix})
+		}
+		for _, suffix := range cfg.TXTLegacySuffixes {
+			legacySchemes = append(legacySchemes, registry.TXTNameScheme{Suffix: suffix})
+		}
+		r, err = registry.NewTXTRegistry(p, cfg.TXTPrefix, cfg.TXTSuffix, cfg.TXTOwnerID, cfg.TXTCacheInterval, cfg.TXTWildcardReplacement, cfg.ManagedDNSRecordTypes, cfg.ExcludeDNSRecordTypes, cfg.TXTEncryptEnabled, []byte(cfg.TXTEncryptAESKey), cfg.TXTGarbageCollect, legacySchemes, cfg.TXTMigrateDryRun)
 	case "aws-sd":
 		r, err = registry.NewAWSSDRegistry(p, cfg.TXTOwnerID)
 	default:
-		log.Fatalf("unknown registry: %s", cfg.Registry)
+		return nil, fmt.Errorf("unknown registry: %s", registryName)
 	}
-
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	policy, exists := plan.Policies[cfg.Policy]
-	if !exists {
-		log.Fatalf("unknown policy: %s", cfg.Policy)
-	}
-
-	ctrl := controller.Controller{
-		Source:               endpointsSource,
-		Registry:             r,
-		Policy:               policy,
-		Interval:             cfg.Interval,
-		DomainFilter:         domainFilter,
-		ManagedRecordTypes:   cfg.ManagedDNSRecordTypes,
-		ExcludeRecordTypes:   cfg.ExcludeDNSRecordTypes,
-		MinEventSyncInterval: cfg.MinEventSyncInterval,
-	}
-
-	if cfg.Once {
-		err := ctrl.RunOnce(ctx)
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		os.Exit(0)
-	}
-
-	if cfg.UpdateEvents {
-		// Add RunOnce as the handler function that will be called when ingress/service sources have changed.
-		// Note that k8s Informers will perform an initial list operation, which results in the handler
-		// function initially being called for every Service/Ingress that exists
-		ctrl.Source.AddEventHandler(ctx, func() { ctrl.ScheduleRunOnce(time.Now()) })
+	ttlPolicy, err := plan.NewTTLPolicy(cfg.DefaultTTL, cfg.MinTTL, cfg.MaxTTL, cfg.RecordTypeTTLs, cfg.DomainTTLs)
+	if err != nil {
+		return nil, err
 	}
 
-	ctrl.ScheduleRunOnce(time.Now())
-	ctrl.Run(ctx)
+	return &controller.Controller{
+		Source:                  endpointsSource,
+		Registry:                r,
+		Policy:                  policy,
+		ConflictResolver:        plan.ConflictResolvers[cfg.ConflictResolver],
+		Interval:                cfg.Interval,
+		DomainFilter:            domainFilter,
+		ManagedRecordTypes:      cfg.ManagedDNSRecordTypes,
+		ExcludeRecordTypes:      cfg.ExcludeDNSRecordTypes,
+		PropertyComparisons:     propertyComparisons,
+		IgnoredProperties:       cfg.IgnoredProviderSpecific,
+		MinEventSyncInterval:    cfg.MinEventSyncInterval,
+		ProviderName:            providerName,
+		MaxChangesPerApply:      cfg.MaxChangesPerApply,
+		MaxBackoff:              cfg.SyncMaxBackoff,
+		CircuitBreakerThreshold: cfg.CircuitBreakerThreshold,
+		CircuitBreakerCooldown:  cfg.CircuitBreakerCooldown,
+		FullResyncInterval:      cfg.FullResyncInterval,
+		TTLPolicy:               ttlPolicy,
+	}, nil
 }
 
 func handleSigterm(cancel func()) {
@@ -450,13 +802,78 @@ func handleSigterm(cancel func()) {
 	cancel()
 }
 
-func serveMetrics(address string) {
-	http.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+func serveMetrics(address string, leaderElection *controller.LeaderElection, state *syncState, debugEndpoints bool) {
+	http.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if leaderElection == nil {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("OK"))
+			return
+		}
+		if err := leaderElection.Check(r); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		if leaderElection.IsLeader() {
+			w.Write([]byte("OK (leader)"))
+		} else {
+			w.Write([]byte("OK (standby)"))
+		}
+	})
+
+	http.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+		if leaderElection != nil && !leaderElection.IsLeader() {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("OK (standby)"))
+			return
+		}
+		router := state.router.Load()
+		if router == nil {
+			http.Error(w, "the controllers are not started yet", http.StatusServiceUnavailable)
+			return
+		}
+		if err := router.Ready(time.Now(), state.maxSyncAge); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
 	})
 
+	if debugEndpoints {
+		http.HandleFunc("/debug/records", serveSnapshots(state, func(s controller.Snapshot) interface{} { return s.Records }))
+		http.HandleFunc("/debug/desired", serveSnapshots(state, func(s controller.Snapshot) interface{} { return s.Desired }))
+		http.HandleFunc("/debug/plan", serveSnapshots(state, func(s controller.Snapshot) interface{} { return s.Changes }))
+		http.HandleFunc("/debug/names", serveSnapshots(state, func(s controller.Snapshot) interface{} { return s.UnicodeNames }))
+	}
+
 	http.Handle("/metrics", promhttp.Handler())
 
 	log.Fatal(http.ListenAndServe(address, nil))
 }
+
+// syncState holds the router whose synchronizations are reflected by the readiness and debug endpoints,
+// which are served before the router is created.
+type syncState struct {
+	router     atomic.Pointer[controller.Router]
+	maxSyncAge time.Duration
+}
+
+// serveSnapshots returns a handler writing the given part of the last synchronization of every provider as JSON.
+func serveSnapshots(state *syncState, part func(controller.Snapshot) interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		router := state.router.Load()
+		if router == nil {
+			http.Error(w, "the controllers are not started yet", http.StatusServiceUnavailable)
+			return
+		}
+		result := map[string]interface{}{}
+		for providerName, snapshot := range router.Snapshots() {
+			result[providerName] = part(snapshot)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			log.Errorf("Failed to write debug snapshot: %v", err)
+		}
+	}
+}
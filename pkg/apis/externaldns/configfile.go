@@ -0,0 +1,323 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externaldns
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	log "github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	configFlag   = "config"
+	envarPrefix  = "EXTERNAL_DNS_"
+	configEnvar  = envarPrefix + "CONFIG"
+	flagArgument = "--"
+)
+
+var envarTransformRegexp = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// ReloadableFields are the fields of Config which can change while ExternalDNS runs,
+// changes of the other fields require a restart. The providers select their zones with the
+// domain filters when they are created, so the reloaded domain filters apply within these zones.
+var ReloadableFields = []string{
+	"AnnotationFilter",
+	"LabelFilter",
+	"DomainFilter",
+	"ExcludeDomains",
+	"RegexDomainFilter",
+	"RegexDomainExclusion",
+	"Policy",
+	"Interval",
+	"ManagedDNSRecordTypes",
+	"ExcludeDNSRecordTypes",
+}
+
+// configFilePath returns the path of the configuration file given by the --config flag,
+// or by the EXTERNAL_DNS_CONFIG environment variable.
+func configFilePath(args []string) string {
+	for i, arg := range args {
+		if arg == flagArgument+configFlag && i+1 < len(args) {
+			return args[i+1]
+		}
+		if value, ok := strings.CutPrefix(arg, flagArgument+configFlag+"="); ok {
+			return value
+		}
+	}
+	return os.Getenv(configEnvar)
+}
+
+// configFileArgs reads the configuration file given by args and returns the flags it sets.
+func configFileArgs(app *kingpin.Application, args []string) ([]string, error) {
+	path := configFilePath(args)
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	fileArgs, err := parseConfigFile(app, data, func(name string) bool { return flagOverridden(name, args) })
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return fileArgs, nil
+}
+
+// flagOverridden returns whether the flag name is set by args or by its environment variable,
+// which take precedence over the config file.
+func flagOverridden(name string, args []string) bool {
+	if flagInArgs(name, args) {
+		log.Debugf("Flag --%s overrides the config file", name)
+		return true
+	}
+	if _, ok := os.LookupEnv(flagEnvar(name)); ok {
+		log.Debugf("Environment variable %s overrides the config file", flagEnvar(name))
+		return true
+	}
+	return false
+}
+
+// parseConfigFile converts the YAML or JSON configuration file, whose keys are the names of the
+// flags of app, into the flags it sets. The flags for which overridden returns true are skipped.
+func parseConfigFile(app *kingpin.Application, data []byte, overridden func(name string) bool) ([]string, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return nil, fmt.Errorf("the config file must be a map of flag names to values: %w", err)
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var fileArgs []string
+	for _, name := range names {
+		flag := app.GetFlag(name)
+		if flag == nil || name == configFlag || name == "help" || name == "version" {
+			return nil, fmt.Errorf("unknown key %q", name)
+		}
+		if overridden(name) {
+			continue
+		}
+		flagArgs, err := configValueArgs(name, flag.Model().IsBoolFlag(), values[name])
+		if err != nil {
+			return nil, err
+		}
+		fileArgs = append(fileArgs, flagArgs...)
+	}
+	return fileArgs, nil
+}
+
+// NewInstanceConfig returns the configuration of the provider instance whose configuration file is at
+// path: the configuration given by args, with the flags set by the file, e.g. the provider, its credentials
+// and the TXT owner ID, replacing the ones of args, of their environment variables and of the config file.
+func NewInstanceConfig(args []string, path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read provider instance config file: %w", err)
+	}
+	app := NewConfig().flagApp()
+	instanceArgs, err := parseConfigFile(app, data, func(string) bool { return false })
+	if err != nil {
+		return nil, fmt.Errorf("invalid provider instance config file %s: %w", path, err)
+	}
+	for _, name := range []string{"provider-route", "provider-instance", "split-horizon-private-provider"} {
+		if flagInArgs(name, instanceArgs) {
+			return nil, fmt.Errorf("invalid provider instance config file %s: %s can't be set for a provider instance", path, name)
+		}
+	}
+
+	cfg := NewConfig()
+	if err := cfg.ParseFlags(append(withoutFlags(app, args, instanceArgs), instanceArgs...)); err != nil {
+		return nil, fmt.Errorf("invalid provider instance config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// withoutFlags returns args without the flags set by other, along with their values.
+func withoutFlags(app *kingpin.Application, args, other []string) []string {
+	var result []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == flagArgument {
+			return append(result, args[i:]...)
+		}
+		name, hasValue := strings.CutPrefix(arg, flagArgument)
+		if !hasValue {
+			result = append(result, arg)
+			continue
+		}
+		name, _, hasValue = strings.Cut(name, "=")
+		flag := app.GetFlag(name)
+		if flag == nil {
+			if negated, ok := strings.CutPrefix(name, "no-"); ok {
+				name, flag = negated, app.GetFlag(negated)
+			}
+		}
+		if flag == nil || !flagInArgs(name, other) {
+			result = append(result, arg)
+			continue
+		}
+		// skip the value of the flag given as the next argument
+		if !hasValue && !flag.Model().IsBoolFlag() {
+			i++
+		}
+	}
+	return result
+}
+
+// configValueArgs returns the flags setting the flag name to value.
+func configValueArgs(name string, isBool bool, value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case bool:
+		if !isBool {
+			return nil, fmt.Errorf("key %q must not be a boolean", name)
+		}
+		if v {
+			return []string{flagArgument + name}, nil
+		}
+		return []string{flagArgument + "no-" + name}, nil
+	case []interface{}:
+		var args []string
+		for _, item := range v {
+			if _, ok := item.(string); !ok {
+				if _, ok := item.(json.Number); !ok {
+					return nil, fmt.Errorf("key %q must be a list of scalar values", name)
+				}
+			}
+			args = append(args, fmt.Sprintf("%s%s=%v", flagArgument, name, item))
+		}
+		return args, nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var args []string
+		for _, key := range keys {
+			args = append(args, fmt.Sprintf("%s%s=%s=%v", flagArgument, name, key, v[key]))
+		}
+		return args, nil
+	default:
+		if isBool {
+			return nil, fmt.Errorf("key %q must be a boolean", name)
+		}
+		return []string{fmt.Sprintf("%s%s=%v", flagArgument, name, v)}, nil
+	}
+}
+
+// flagInArgs returns whether the flag name is set by args.
+func flagInArgs(name string, args []string) bool {
+	for _, arg := range args {
+		if arg == flagArgument {
+			return false
+		}
+		if arg == flagArgument+name || arg == flagArgument+"no-"+name || strings.HasPrefix(arg, flagArgument+name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// flagEnvar returns the environment variable of the flag name.
+func flagEnvar(name string) string {
+	return envarPrefix + strings.ToUpper(envarTransformRegexp.ReplaceAllString(name, "_"))
+}
+
+// RestartRequired returns the fields which differ between cfg and other and can't change
+// while ExternalDNS runs.
+func (cfg *Config) RestartRequired(other *Config) []string {
+	reloadable := map[string]struct{}{}
+	for _, field := range ReloadableFields {
+		reloadable[field] = struct{}{}
+	}
+
+	var fields []string
+	v, o := reflect.ValueOf(cfg).Elem(), reflect.ValueOf(other).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Name
+		if _, ok := reloadable[name]; ok {
+			continue
+		}
+		if !reflect.DeepEqual(v.Field(i).Interface(), o.Field(i).Interface()) {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// WatchConfigFile calls reload whenever the content of the configuration file at path changes,
+// checking it every interval until ctx is done.
+func WatchConfigFile(ctx context.Context, path string, interval time.Duration, reload func()) {
+	last, err := fileHash(path)
+	if err != nil {
+		log.Errorf("Failed to read config file: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		current, err := fileHash(path)
+		if err != nil {
+			log.Errorf("Failed to read config file: %v", err)
+			continue
+		}
+		if current == last {
+			continue
+		}
+		last = current
+		log.Infof("Config file %s changed, reloading", path)
+		reload()
+	}
+}
+
+func fileHash(path string) ([sha256.Size]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(data), nil
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externaldns
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestParseFlagsConfigFile(t *testing.T) {
+	yamlFile := writeConfigFile(t, `
+source: [service, ingress]
+provider: inmemory
+domain-filter:
+  - example.com
+  - example.org
+interval: 5m
+once: true
+aws-evaluate-target-health: false
+aws-batch-change-size: 10
+aws-sd-create-tag:
+  team: dns
+`)
+	jsonFile := writeConfigFile(t, `{"source": ["service"], "provider": "inmemory", "policy": "upsert-only"}`)
+
+	for _, tc := range []struct {
+		title    string
+		args     []string
+		envVars  map[string]string
+		expected func(t *testing.T, cfg *Config)
+		err      string
+	}{
+		{
+			title: "YAML file",
+			args:  []string{"--config", yamlFile},
+			expected: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, yamlFile, cfg.ConfigFile)
+				assert.Equal(t, []string{"service", "ingress"}, cfg.Sources)
+				assert.Equal(t, "inmemory", cfg.Provider)
+				assert.Equal(t, []string{"example.com", "example.org"}, cfg.DomainFilter)
+				assert.Equal(t, 5*time.Minute, cfg.Interval)
+				assert.True(t, cfg.Once)
+				assert.False(t, cfg.AWSEvaluateTargetHealth)
+				assert.Equal(t, 10, cfg.AWSBatchChangeSize)
+				assert.Equal(t, map[string]string{"team": "dns"}, cfg.AWSSDCreateTag)
+				assert.Equal(t, "sync", cfg.Policy)
+			},
+		},
+		{
+			title: "JSON file",
+			args:  []string{"--config=" + jsonFile},
+			expected: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, []string{"service"}, cfg.Sources)
+				assert.Equal(t, "upsert-only", cfg.Policy)
+			},
+		},
+		{
+			title:   "file from the environment",
+			envVars: map[string]string{"EXTERNAL_DNS_CONFIG": jsonFile},
+			expected: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, jsonFile, cfg.ConfigFile)
+				assert.Equal(t, "upsert-only", cfg.Policy)
+			},
+		},
+		{
+			title: "flags take precedence over the file",
+			args:  []string{"--config", yamlFile, "--domain-filter=example.net", "--interval", "1m", "--no-once"},
+			expected: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, []string{"example.net"}, cfg.DomainFilter)
+				assert.Equal(t, time.Minute, cfg.Interval)
+				assert.False(t, cfg.Once)
+				assert.Equal(t, "inmemory", cfg.Provider)
+			},
+		},
+		{
+			title:   "environment variables take precedence over the file",
+			args:    []string{"--config", yamlFile},
+			envVars: map[string]string{"EXTERNAL_DNS_DOMAIN_FILTER": "example.net"},
+			expected: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, []string{"example.net"}, cfg.DomainFilter)
+				assert.Equal(t, 5*time.Minute, cfg.Interval)
+			},
+		},
+		{
+			title: "unknown key",
+			args:  []string{"--config", writeConfigFile(t, "source: [service]\ndomain-filters: [example.com]\n")},
+			err:   `unknown key "domain-filters"`,
+		},
+		{
+			title: "invalid value",
+			args:  []string{"--config", writeConfigFile(t, "source: [service]\ninterval: soon\n")},
+			err:   "invalid duration",
+		},
+		{
+			title: "boolean of a non boolean flag",
+			args:  []string{"--config", writeConfigFile(t, "source: [service]\nprovider: true\n")},
+			err:   `key "provider" must not be a boolean`,
+		},
+		{
+			title: "nested config",
+			args:  []string{"--config", writeConfigFile(t, "config: other.yaml\n")},
+			err:   `unknown key "config"`,
+		},
+		{
+			title: "missing file",
+			args:  []string{"--config", filepath.Join(t.TempDir(), "missing.yaml")},
+			err:   "failed to read config file",
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			originalEnv := setEnv(t, tc.envVars)
+			defer restoreEnv(t, originalEnv)
+
+			cfg := NewConfig()
+			err := cfg.ParseFlags(tc.args)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			tc.expected(t, cfg)
+		})
+	}
+}
+
+func TestNewInstanceConfig(t *testing.T) {
+	instanceFile := writeConfigFile(t, `
+provider: aws
+aws-assume-role: arn:aws:iam::123456789012:role/dns
+txt-owner-id: partner
+txt-prefix: partner.
+dry-run: false
+`)
+	args := []string{"--source=service", "--provider=inmemory", "--domain-filter=example.com", "--txt-owner-id", "default", "--dry-run", "--provider-route=partner=partner.example.org", "--provider-instance=partner=" + instanceFile}
+
+	for _, tc := range []struct {
+		title    string
+		args     []string
+		envVars  map[string]string
+		path     string
+		expected func(t *testing.T, cfg *Config)
+		err      string
+	}{
+		{
+			title: "the instance file replaces the flags",
+			args:  args,
+			path:  instanceFile,
+			expected: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "aws", cfg.Provider)
+				assert.Equal(t, "arn:aws:iam::123456789012:role/dns", cfg.AWSAssumeRole)
+				assert.Equal(t, "partner", cfg.TXTOwnerID)
+				assert.Equal(t, "partner.", cfg.TXTPrefix)
+				assert.False(t, cfg.DryRun)
+				// the other flags are kept
+				assert.Equal(t, []string{"service"}, cfg.Sources)
+				assert.Equal(t, []string{"example.com"}, cfg.DomainFilter)
+			},
+		},
+		{
+			title:   "the instance file replaces the environment variables",
+			args:    []string{"--source=service", "--provider=inmemory"},
+			envVars: map[string]string{"EXTERNAL_DNS_TXT_OWNER_ID": "default"},
+			path:    instanceFile,
+			expected: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "partner", cfg.TXTOwnerID)
+			},
+		},
+		{
+			title: "routes can't be set for an instance",
+			args:  args,
+			path:  writeConfigFile(t, "provider: aws\nprovider-route: {other: example.net}\n"),
+			err:   "provider-route can't be set for a provider instance",
+		},
+		{
+			title: "missing file",
+			args:  args,
+			path:  filepath.Join(t.TempDir(), "missing.yaml"),
+			err:   "failed to read provider instance config file",
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			originalEnv := setEnv(t, tc.envVars)
+			defer restoreEnv(t, originalEnv)
+
+			cfg, err := NewInstanceConfig(tc.args, tc.path)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			tc.expected(t, cfg)
+		})
+	}
+}
+
+func TestRestartRequired(t *testing.T) {
+	cfg := &Config{Provider: "google", Interval: time.Minute, DomainFilter: []string{"example.com"}}
+	other := &Config{Provider: "google", Interval: time.Hour, DomainFilter: []string{"example.org"}}
+	assert.Empty(t, cfg.RestartRequired(other))
+
+	other.Provider = "aws"
+	other.Sources = []string{"service"}
+	assert.Equal(t, []string{"Sources", "Provider"}, cfg.RestartRequired(other))
+}
+
+func TestWatchConfigFile(t *testing.T) {
+	path := writeConfigFile(t, "interval: 1m\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reloads := make(chan struct{}, 10)
+	go WatchConfigFile(ctx, path, 10*time.Millisecond, func() { reloads <- struct{}{} })
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, reloads, "an unchanged file isn't reloaded")
+
+	require.NoError(t, os.WriteFile(path, []byte("interval: 2m\n"), 0o600))
+	select {
+	case <-reloads:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a reload")
+	}
+}
@@ -35,6 +35,21 @@ import (
 )
 
 const (
+	// CommandRun synchronizes the DNS records until ExternalDNS is stopped, or once with --once.
+	CommandRun = "run"
+	// CommandRecordsList prints the records of the providers.
+	CommandRecordsList = "records list"
+	// CommandPlan prints the changes of the next synchronization.
+	CommandPlan = "plan"
+	// CommandValidateConfig validates the configuration and the credentials of the providers.
+	CommandValidateConfig = "validate-config"
+	// CommandSimulate prints the records and changes of Kubernetes manifests against a zone snapshot.
+	CommandSimulate = "simulate"
+	// CommandZoneExport prints the records of the providers as a zone file.
+	CommandZoneExport = "zone export"
+	// CommandMigrate copies the records managed by ExternalDNS from --provider to --migrate-to-provider.
+	CommandMigrate = "migrate"
+
 	passwordMask = "******"
 )
 
@@ -66,8 +81,14 @@ type Config struct {
 	PublishHostIP                      bool
 	AlwaysPublishNotReadyAddresses     bool
 	ConnectorSourceServer              string
+	ZoneFile                           string
+	ZoneFileOrigin                     string
 	Provider                           string
 	ProviderCacheTime                  time.Duration
+	ApplyConcurrency                   int
+	ProviderRoutes                     map[string]string
+	ProviderInstances                  map[string]string
+	SplitHorizonPrivateProvider        string
 	GoogleProject                      string
 	GoogleBatchChangeSize              int
 	GoogleBatchChangeInterval          time.Duration
@@ -80,6 +101,11 @@ type Config struct {
 	ZoneIDFilter                       []string
 	TargetNetFilter                    []string
 	ExcludeTargetNets                  []string
+	TargetHostFilter                   []string
+	ExcludeTargetHosts                 []string
+	RegexTargetHostFilter              *regexp.Regexp
+	RegexTargetHostExclusion           *regexp.Regexp
+	TargetRewrites                     []string
 	AlibabaCloudConfigFile             string
 	AlibabaCloudZoneType               string
 	AWSZoneType                        string
@@ -132,19 +158,49 @@ type Config struct {
 	TLSClientCert                      string
 	TLSClientCertKey                   string
 	Policy                             string
+	ConflictResolver                   string
 	Registry                           string
 	TXTOwnerID                         string
 	TXTPrefix                          string
 	TXTSuffix                          string
 	TXTEncryptEnabled                  bool
 	TXTEncryptAESKey                   string `secure:"yes"`
+	TXTGarbageCollect                  bool
+	TXTLegacyPrefixes                  []string
+	TXTLegacySuffixes                  []string
+	TXTMigrateDryRun                   bool
 	Interval                           time.Duration
 	MinEventSyncInterval               time.Duration
+	FullResyncInterval                 time.Duration
+	SyncMaxBackoff                     time.Duration
+	CircuitBreakerThreshold            int
+	CircuitBreakerCooldown             time.Duration
+	MaxChangesPerApply                 int
 	Once                               bool
+	LeaderElect                        bool
+	LeaderElectionNamespace            string
+	LeaderElectionLeaseName            string
+	LeaderElectionLeaseDuration        time.Duration
+	LeaderElectionRenewDeadline        time.Duration
+	LeaderElectionRetryPeriod          time.Duration
 	DryRun                             bool
 	UpdateEvents                       bool
 	LogFormat                          string
 	MetricsAddress                     string
+	Tracing                            bool
+	TracingEndpoint                    string
+	TracingInsecure                    bool
+	TracingSampleRatio                 float64
+	ReadinessMaxSyncAge                time.Duration
+	DebugEndpoints                     bool
+	ConfigFile                         string
+	Command                            string
+	SimulateManifests                  string
+	SimulateZoneSnapshot               string
+	ZoneExportOwnedOnly                bool
+	MigrateToProvider                  string
+	MigrateBatchSize                   int
+	MigrateDryRun                      bool
 	LogLevel                           string
 	TXTCacheInterval                   time.Duration
 	TXTWildcardReplacement             string
@@ -185,6 +241,12 @@ type Config struct {
 	DigitalOceanAPIPageSize            int
 	ManagedDNSRecordTypes              []string
 	ExcludeDNSRecordTypes              []string
+	IgnoredProviderSpecific            []string
+	DefaultTTL                         time.Duration
+	MinTTL                             time.Duration
+	MaxTTL                             time.Duration
+	RecordTypeTTLs                     map[string]string
+	DomainTTLs                         map[string]string
 	GoDaddyAPIKey                      string `secure:"yes"`
 	GoDaddySecretKey                   string `secure:"yes"`
 	GoDaddyTTL                         int64
@@ -206,6 +268,9 @@ type Config struct {
 	TraefikDisableLegacy               bool
 	TraefikDisableNew                  bool
 	NAT64Networks                      []string
+	FlattenCNAMEs                      bool
+	FlattenCNAMEZones                  []string
+	FlattenCNAMEResolver               string
 }
 
 var defaultConfig = &Config{
@@ -231,8 +296,14 @@ var defaultConfig = &Config{
 	PublishInternal:             false,
 	PublishHostIP:               false,
 	ConnectorSourceServer:       "localhost:8080",
+	ZoneFile:                    "",
+	ZoneFileOrigin:              "",
 	Provider:                    "",
 	ProviderCacheTime:           0,
+	ApplyConcurrency:            1,
+	ProviderRoutes:              map[string]string{},
+	ProviderInstances:           map[string]string{},
+	SplitHorizonPrivateProvider: "",
 	GoogleProject:               "",
 	GoogleBatchChangeSize:       1000,
 	GoogleBatchChangeInterval:   time.Second,
@@ -244,6 +315,11 @@ var defaultConfig = &Config{
 	RegexDomainExclusion:        regexp.MustCompile(""),
 	TargetNetFilter:             []string{},
 	ExcludeTargetNets:           []string{},
+	TargetHostFilter:            []string{},
+	ExcludeTargetHosts:          []string{},
+	RegexTargetHostFilter:       regexp.MustCompile(""),
+	RegexTargetHostExclusion:    regexp.MustCompile(""),
+	TargetRewrites:              []string{},
 	AlibabaCloudConfigFile:      "/etc/kubernetes/alibaba-cloud.json",
 	AWSZoneType:                 "",
 	AWSZoneTagFilter:            []string{},
@@ -290,6 +366,7 @@ var defaultConfig = &Config{
 	TLSClientCert:               "",
 	TLSClientCertKey:            "",
 	Policy:                      "sync",
+	ConflictResolver:            "per-resource",
 	Registry:                    "txt",
 	TXTOwnerID:                  "default",
 	TXTPrefix:                   "",
@@ -297,14 +374,43 @@ var defaultConfig = &Config{
 	TXTCacheInterval:            0,
 	TXTWildcardReplacement:      "",
 	MinEventSyncInterval:        5 * time.Second,
+	FullResyncInterval:          0,
+	SyncMaxBackoff:              10 * time.Minute,
+	CircuitBreakerThreshold:     0,
+	CircuitBreakerCooldown:      15 * time.Minute,
+	MaxChangesPerApply:          0,
 	TXTEncryptEnabled:           false,
 	TXTEncryptAESKey:            "",
+	TXTGarbageCollect:           false,
+	TXTLegacyPrefixes:           []string{},
+	TXTLegacySuffixes:           []string{},
+	TXTMigrateDryRun:            false,
 	Interval:                    time.Minute,
 	Once:                        false,
+	LeaderElect:                 false,
+	LeaderElectionNamespace:     "",
+	LeaderElectionLeaseName:     "external-dns",
+	LeaderElectionLeaseDuration: 15 * time.Second,
+	LeaderElectionRenewDeadline: 10 * time.Second,
+	LeaderElectionRetryPeriod:   2 * time.Second,
 	DryRun:                      false,
 	UpdateEvents:                false,
 	LogFormat:                   "text",
 	MetricsAddress:              ":7979",
+	Tracing:                     false,
+	TracingEndpoint:             "",
+	TracingInsecure:             false,
+	TracingSampleRatio:          1.0,
+	ReadinessMaxSyncAge:         0,
+	DebugEndpoints:              false,
+	ConfigFile:                  "",
+	Command:                     CommandRun,
+	SimulateManifests:           "",
+	SimulateZoneSnapshot:        "",
+	ZoneExportOwnedOnly:         false,
+	MigrateToProvider:           "",
+	MigrateBatchSize:            100,
+	MigrateDryRun:               false,
 	LogLevel:                    logrus.InfoLevel.String(),
 	ExoscaleAPIEnvironment:      "api",
 	ExoscaleAPIZone:             "ch-gva-2",
@@ -339,6 +445,12 @@ var defaultConfig = &Config{
 	DigitalOceanAPIPageSize:     50,
 	ManagedDNSRecordTypes:       []string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME},
 	ExcludeDNSRecordTypes:       []string{},
+	IgnoredProviderSpecific:     []string{},
+	DefaultTTL:                  0,
+	MinTTL:                      0,
+	MaxTTL:                      0,
+	RecordTypeTTLs:              map[string]string{},
+	DomainTTLs:                  map[string]string{},
 	GoDaddyAPIKey:               "",
 	GoDaddySecretKey:            "",
 	GoDaddyTTL:                  600,
@@ -359,12 +471,19 @@ var defaultConfig = &Config{
 	TraefikDisableLegacy:        false,
 	TraefikDisableNew:           false,
 	NAT64Networks:               []string{},
+	FlattenCNAMEs:               false,
+	FlattenCNAMEZones:           []string{},
+	FlattenCNAMEResolver:        "",
 }
 
 // NewConfig returns new Config object
 func NewConfig() *Config {
 	return &Config{
-		AWSSDCreateTag: map[string]string{},
+		AWSSDCreateTag:    map[string]string{},
+		ProviderRoutes:    map[string]string{},
+		ProviderInstances: map[string]string{},
+		RecordTypeTTLs:    map[string]string{},
+		DomainTTLs:        map[string]string{},
 	}
 }
 
@@ -400,6 +519,24 @@ func allLogLevelsAsStrings() []string {
 
 // ParseFlags adds and parses flags from command line
 func (cfg *Config) ParseFlags(args []string) error {
+	app := cfg.flagApp()
+
+	fileArgs, err := configFileArgs(app, args)
+	if err != nil {
+		return err
+	}
+
+	command, err := app.Parse(append(fileArgs, args...))
+	if err != nil {
+		return err
+	}
+	cfg.Command = command
+
+	return nil
+}
+
+// flagApp returns the application parsing the flags and commands into cfg.
+func (cfg *Config) flagApp() *kingpin.Application {
 	app := kingpin.New("external-dns", "ExternalDNS synchronizes exposed Kubernetes Services and Ingresses with DNS providers.\n\nNote that all flags may be replaced with env vars - `--flag` -> `EXTERNAL_DNS_FLAG=1` or `--flag value` -> `EXTERNAL_DNS_FLAG=value`")
 	app.Version(Version)
 	app.DefaultEnvars()
@@ -422,7 +559,7 @@ func (cfg *Config) ParseFlags(args []string) error {
 	app.Flag("skipper-routegroup-groupversion", "The resource version for skipper routegroup").Default(source.DefaultRoutegroupVersion).StringVar(&cfg.SkipperRouteGroupVersion)
 
 	// Flags related to processing source
-	app.Flag("source", "The resource types that are queried for endpoints; specify multiple times for multiple sources (required, options: service, ingress, node, pod, fake, connector, gateway-httproute, gateway-grpcroute, gateway-tlsroute, gateway-tcproute, gateway-udproute, istio-gateway, istio-virtualservice, cloudfoundry, contour-httpproxy, gloo-proxy, crd, empty, skipper-routegroup, openshift-route, ambassador-host, kong-tcpingress, f5-virtualserver, traefik-proxy)").Required().PlaceHolder("source").EnumsVar(&cfg.Sources, "service", "ingress", "node", "pod", "gateway-httproute", "gateway-grpcroute", "gateway-tlsroute", "gateway-tcproute", "gateway-udproute", "istio-gateway", "istio-virtualservice", "cloudfoundry", "contour-httpproxy", "gloo-proxy", "fake", "connector", "crd", "empty", "skipper-routegroup", "openshift-route", "ambassador-host", "kong-tcpingress", "f5-virtualserver", "traefik-proxy")
+	app.Flag("source", "The resource types that are queried for endpoints; specify multiple times for multiple sources (required, options: service, ingress, node, pod, fake, connector, gateway-httproute, gateway-grpcroute, gateway-tlsroute, gateway-tcproute, gateway-udproute, istio-gateway, istio-virtualservice, cloudfoundry, contour-httpproxy, gloo-proxy, crd, empty, skipper-routegroup, openshift-route, ambassador-host, kong-tcpingress, f5-virtualserver, traefik-proxy, zone-file)").Required().PlaceHolder("source").EnumsVar(&cfg.Sources, "service", "ingress", "node", "pod", "gateway-httproute", "gateway-grpcroute", "gateway-tlsroute", "gateway-tcproute", "gateway-udproute", "istio-gateway", "istio-virtualservice", "cloudfoundry", "contour-httpproxy", "gloo-proxy", "fake", "connector", "crd", "empty", "skipper-routegroup", "openshift-route", "ambassador-host", "kong-tcpingress", "f5-virtualserver", "traefik-proxy", "zone-file")
 	app.Flag("openshift-router-name", "if source is openshift-route then you can pass the ingress controller name. Based on this name external-dns will select the respective router from the route status and map that routerCanonicalHostname to the route host while creating a CNAME record.").StringVar(&cfg.OCPRouterName)
 	app.Flag("namespace", "Limit resources queried for endpoints to a specific namespace (default: all namespaces)").Default(defaultConfig.Namespace).StringVar(&cfg.Namespace)
 	app.Flag("annotation-filter", "Filter resources queried for endpoints by annotation, using label selector semantics").Default(defaultConfig.AnnotationFilter).StringVar(&cfg.AnnotationFilter)
@@ -440,22 +577,42 @@ func (cfg *Config) ParseFlags(args []string) error {
 	app.Flag("publish-host-ip", "Allow external-dns to publish host-ip for headless services (optional)").BoolVar(&cfg.PublishHostIP)
 	app.Flag("always-publish-not-ready-addresses", "Always publish also not ready addresses for headless services (optional)").BoolVar(&cfg.AlwaysPublishNotReadyAddresses)
 	app.Flag("connector-source-server", "The server to connect for connector source, valid only when using connector source").Default(defaultConfig.ConnectorSourceServer).StringVar(&cfg.ConnectorSourceServer)
+	app.Flag("zone-file", "The RFC 1035 zone file read for zone-file source, valid only when using zone-file source").Default(defaultConfig.ZoneFile).StringVar(&cfg.ZoneFile)
+	app.Flag("zone-file-origin", "The origin of the relative names of the zone file of zone-file source, unless set by $ORIGIN in the file (optional)").Default(defaultConfig.ZoneFileOrigin).StringVar(&cfg.ZoneFileOrigin)
 	app.Flag("crd-source-apiversion", "API version of the CRD for crd source, e.g. `externaldns.k8s.io/v1alpha1`, valid only when using crd source").Default(defaultConfig.CRDSourceAPIVersion).StringVar(&cfg.CRDSourceAPIVersion)
 	app.Flag("crd-source-kind", "Kind of the CRD for the crd source in API group and version specified by crd-source-apiversion").Default(defaultConfig.CRDSourceKind).StringVar(&cfg.CRDSourceKind)
 	app.Flag("service-type-filter", "The service types to take care about (default: all, expected: ClusterIP, NodePort, LoadBalancer or ExternalName)").StringsVar(&cfg.ServiceTypeFilter)
-	app.Flag("managed-record-types", "Record types to manage; specify multiple times to include many; (default: A, AAAA, CNAME) (supported records: A, AAAA, CNAME, NS, SRV, TXT)").Default("A", "AAAA", "CNAME").StringsVar(&cfg.ManagedDNSRecordTypes)
+	app.Flag("managed-record-types", "Record types to manage; specify multiple times to include many; (default: A, AAAA, CNAME) (supported records: A, AAAA, CNAME, NS, SRV, TXT, MX, NAPTR, CAA, TLSA, SSHFP, HTTPS, SVCB)").Default("A", "AAAA", "CNAME").StringsVar(&cfg.ManagedDNSRecordTypes)
 	app.Flag("exclude-record-types", "Record types to exclude from management; specify multiple times to exclude many; (optional)").Default().StringsVar(&cfg.ExcludeDNSRecordTypes)
+	app.Flag("ignore-provider-specific", "Provider specific property never causing an update of a record when its value changes, e.g. aws/evaluate-target-health; specify multiple times to ignore many (optional)").StringsVar(&cfg.IgnoredProviderSpecific)
+	app.Flag("default-ttl", "The TTL of the records whose source configures none, e.g. with the TTL annotation; 0 leaves the TTL to the provider (default: 0)").Default(defaultConfig.DefaultTTL.String()).DurationVar(&cfg.DefaultTTL)
+	app.Flag("min-ttl", "The minimum TTL of the records, lower TTLs are raised to it; 0 for no minimum (default: 0)").Default(defaultConfig.MinTTL.String()).DurationVar(&cfg.MinTTL)
+	app.Flag("max-ttl", "The maximum TTL of the records, higher TTLs are lowered to it; 0 for no maximum (default: 0)").Default(defaultConfig.MaxTTL.String()).DurationVar(&cfg.MaxTTL)
+	app.Flag("record-type-ttl", "The TTL of the records of a type whose source configures none, in the form type=ttl, e.g. NS=1h; specify multiple times for multiple types (optional)").StringMapVar(&cfg.RecordTypeTTLs)
+	app.Flag("domain-ttl", "The TTL policy of a domain and its subdomains, in the form domain=default=ttl,min=ttl,max=ttl with any of the settings, e.g. example.org=default=5m,max=1h; specify multiple times for multiple domains (optional)").StringMapVar(&cfg.DomainTTLs)
 	app.Flag("default-targets", "Set globally default host/IP that will apply as a target instead of source addresses. Specify multiple times for multiple targets (optional)").StringsVar(&cfg.DefaultTargets)
 	app.Flag("target-net-filter", "Limit possible targets by a net filter; specify multiple times for multiple possible nets (optional)").StringsVar(&cfg.TargetNetFilter)
 	app.Flag("exclude-target-net", "Exclude target nets (optional)").StringsVar(&cfg.ExcludeTargetNets)
+	app.Flag("target-host-filter", "Limit possible hostname targets, e.g. the targets of CNAME records, by a glob like *.elb.amazonaws.com; specify multiple times for multiple globs (optional)").StringsVar(&cfg.TargetHostFilter)
+	app.Flag("exclude-target-host", "Exclude hostname targets matching a glob; specify multiple times for multiple globs (optional)").StringsVar(&cfg.ExcludeTargetHosts)
+	app.Flag("regex-target-host-filter", "Limit possible hostname targets by a Regex filter (optional)").Default(defaultConfig.RegexTargetHostFilter.String()).RegexpVar(&cfg.RegexTargetHostFilter)
+	app.Flag("regex-target-host-exclusion", "Exclude hostname targets matching a Regex filter (optional)").Default(defaultConfig.RegexTargetHostExclusion.String()).RegexpVar(&cfg.RegexTargetHostExclusion)
+	app.Flag("target-rewrite", "Rewrite the targets of the records after filtering them, in the form from=to with hostnames, e.g. *.lb.internal=*.cdn.example.com, or networks of the same size, e.g. 10.0.0.0/24=203.0.113.0/24; the first matching rule applies, specify multiple times for multiple rules (optional)").StringsVar(&cfg.TargetRewrites)
 	app.Flag("traefik-disable-legacy", "Disable listeners on Resources under the traefik.containo.us API Group").Default(strconv.FormatBool(defaultConfig.TraefikDisableLegacy)).BoolVar(&cfg.TraefikDisableLegacy)
 	app.Flag("traefik-disable-new", "Disable listeners on Resources under the traefik.io API Group").Default(strconv.FormatBool(defaultConfig.TraefikDisableNew)).BoolVar(&cfg.TraefikDisableNew)
 	app.Flag("nat64-networks", "Adding an A record for each AAAA record in NAT64-enabled networks; specify multiple times for multiple possible nets (optional)").StringsVar(&cfg.NAT64Networks)
+	app.Flag("flatten-cnames", "When enabled, replaces the CNAME records at the apex of the zones, and the ones of resources annotated with external-dns.alpha.kubernetes.io/flatten, by A and AAAA records of the addresses of their targets, resolved in every synchronization (default: disabled)").BoolVar(&cfg.FlattenCNAMEs)
+	app.Flag("flatten-cname-zone", "The zone whose apex CNAME records are flattened with --flatten-cnames; specify multiple times for multiple zones (default: none, only the CNAME records of annotated resources are flattened)").StringsVar(&cfg.FlattenCNAMEZones)
+	app.Flag("flatten-cname-resolver", "The address of the DNS server resolving the targets of flattened CNAME records in host:port format (default: the system resolver)").Default(defaultConfig.FlattenCNAMEResolver).StringVar(&cfg.FlattenCNAMEResolver)
 
 	// Flags related to providers
 	providers := []string{"akamai", "alibabacloud", "aws", "aws-sd", "azure", "azure-dns", "azure-private-dns", "civo", "cloudflare", "cloudflare-tunnel", "coredns", "designate", "digitalocean", "dnsimple", "exoscale", "gandi", "godaddy", "google", "ibmcloud", "inmemory", "linode", "ns1", "oci", "ovh", "pdns", "pihole", "plural", "rfc2136", "scaleway", "skydns", "tencentcloud", "transip", "ultradns", "webhook"}
 	app.Flag("provider", "The DNS provider where the DNS records will be created (required, options: "+strings.Join(providers, ", ")+")").Required().PlaceHolder("provider").EnumVar(&cfg.Provider, providers...)
 	app.Flag("provider-cache-time", "The time to cache the DNS provider record list requests.").Default(defaultConfig.ProviderCacheTime.String()).DurationVar(&cfg.ProviderCacheTime)
+	app.Flag("apply-concurrency", "The maximum number of zones whose changes are applied concurrently, for the aws, google, inmemory and rfc2136 providers (default: 1, applies the zones one after the other)").Default(strconv.Itoa(defaultConfig.ApplyConcurrency)).IntVar(&cfg.ApplyConcurrency)
+	app.Flag("provider-route", "Route the DNS names below the given domains to an additional provider instead of --provider, in the form name=domain[,domain...] where name is a provider or a --provider-instance; specify multiple times for multiple providers (optional)").StringMapVar(&cfg.ProviderRoutes)
+	app.Flag("provider-instance", "Configure a named provider instance for --provider-route and --split-horizon-private-provider, in the form name=path of a YAML or JSON file setting flags of the instance like --config, e.g. provider, its credentials and txt-owner-id; specify multiple times for multiple instances (optional)").StringMapVar(&cfg.ProviderInstances)
+	app.Flag("split-horizon-private-provider", "Publish the endpoints marked private by the internal-hostname or access annotations to this provider, and the other endpoints to --provider (optional)").Default(defaultConfig.SplitHorizonPrivateProvider).StringVar(&cfg.SplitHorizonPrivateProvider)
 	app.Flag("domain-filter", "Limit possible target zones by a domain suffix; specify multiple times for multiple domains (optional)").Default("").StringsVar(&cfg.DomainFilter)
 	app.Flag("exclude-domains", "Exclude subdomains (optional)").Default("").StringsVar(&cfg.ExcludeDomains)
 	app.Flag("regex-domain-filter", "Limit possible domains and target zones by a Regex filter; Overrides domain-filter (optional)").Default(defaultConfig.RegexDomainFilter.String()).RegexpVar(&cfg.RegexDomainFilter)
@@ -571,6 +728,7 @@ func (cfg *Config) ParseFlags(args []string) error {
 
 	// Flags related to policies
 	app.Flag("policy", "Modify how DNS records are synchronized between sources and providers (default: sync, options: sync, upsert-only, create-only)").Default(defaultConfig.Policy).EnumVar(&cfg.Policy, "sync", "upsert-only", "create-only")
+	app.Flag("conflict-resolver", "Modify how the conflicts between resources claiming the same DNS name are resolved (default: per-resource, options: per-resource, merge-targets, priority, oldest-resource)").Default(defaultConfig.ConflictResolver).EnumVar(&cfg.ConflictResolver, "per-resource", "merge-targets", "priority", "oldest-resource")
 
 	// Flags related to the registry
 	app.Flag("registry", "The registry implementation to use to keep track of DNS record ownership (default: txt, options: txt, noop, dynamodb, aws-sd)").Default(defaultConfig.Registry).EnumVar(&cfg.Registry, "txt", "noop", "dynamodb", "aws-sd")
@@ -580,6 +738,10 @@ func (cfg *Config) ParseFlags(args []string) error {
 	app.Flag("txt-wildcard-replacement", "When using the TXT registry, a custom string that's used instead of an asterisk for TXT records corresponding to wildcard DNS records (optional)").Default(defaultConfig.TXTWildcardReplacement).StringVar(&cfg.TXTWildcardReplacement)
 	app.Flag("txt-encrypt-enabled", "When using the TXT registry, set if TXT records should be encrypted before stored (default: disabled)").BoolVar(&cfg.TXTEncryptEnabled)
 	app.Flag("txt-encrypt-aes-key", "When using the TXT registry, set TXT record decryption and encryption 32 byte aes key (required when --txt-encrypt=true)").Default(defaultConfig.TXTEncryptAESKey).StringVar(&cfg.TXTEncryptAESKey)
+	app.Flag("txt-garbage-collect", "When using the TXT registry, delete ownership records owned by this instance whose managed record no longer exists, unless the policy forbids deletions (default: disabled)").BoolVar(&cfg.TXTGarbageCollect)
+	app.Flag("txt-legacy-prefix", "When using the TXT registry, a prefix previously used for ownership DNS records, which are read and migrated to the current naming scheme; specify multiple times for multiple prefixes, an empty value refers to records without prefix or suffix (optional)").StringsVar(&cfg.TXTLegacyPrefixes)
+	app.Flag("txt-legacy-suffix", "When using the TXT registry, a suffix previously used for ownership DNS records, which are read and migrated to the current naming scheme; specify multiple times for multiple suffixes (optional)").StringsVar(&cfg.TXTLegacySuffixes)
+	app.Flag("txt-migrate-dry-run", "When using the TXT registry, only report the ownership DNS records which would be migrated from a legacy naming scheme (default: disabled)").BoolVar(&cfg.TXTMigrateDryRun)
 	app.Flag("dynamodb-region", "When using the DynamoDB registry, the AWS region of the DynamoDB table (optional)").Default(cfg.AWSDynamoDBRegion).StringVar(&cfg.AWSDynamoDBRegion)
 	app.Flag("dynamodb-table", "When using the DynamoDB registry, the name of the DynamoDB table (default: \"external-dns\")").Default(defaultConfig.AWSDynamoDBTable).StringVar(&cfg.AWSDynamoDBTable)
 
@@ -587,15 +749,45 @@ func (cfg *Config) ParseFlags(args []string) error {
 	app.Flag("txt-cache-interval", "The interval between cache synchronizations in duration format (default: disabled)").Default(defaultConfig.TXTCacheInterval.String()).DurationVar(&cfg.TXTCacheInterval)
 	app.Flag("interval", "The interval between two consecutive synchronizations in duration format (default: 1m)").Default(defaultConfig.Interval.String()).DurationVar(&cfg.Interval)
 	app.Flag("min-event-sync-interval", "The minimum interval between two consecutive synchronizations triggered from kubernetes events in duration format (default: 5s)").Default(defaultConfig.MinEventSyncInterval.String()).DurationVar(&cfg.MinEventSyncInterval)
+	app.Flag("full-resync-interval", "When enabled together with --events, synchronizations only reconcile the DNS names of the resources changed since the previous synchronization, and all DNS names are reconciled at least once per this interval in duration format (default: disabled)").Default(defaultConfig.FullResyncInterval.String()).DurationVar(&cfg.FullResyncInterval)
+	app.Flag("sync-max-backoff", "The maximum interval between two consecutive synchronizations after failures in duration format, the interval doubles with every failure in a row; 0 disables the backoff (default: 10m)").Default(defaultConfig.SyncMaxBackoff.String()).DurationVar(&cfg.SyncMaxBackoff)
+	app.Flag("circuit-breaker-threshold", "The number of failed synchronizations in a row after which synchronizations are paused for the circuit breaker cooldown, a hard error pauses them at once; 0 disables the circuit breaker and stops ExternalDNS on a hard error (default: disabled)").Default(strconv.Itoa(defaultConfig.CircuitBreakerThreshold)).IntVar(&cfg.CircuitBreakerThreshold)
+	app.Flag("circuit-breaker-cooldown", "The time synchronizations are paused while the circuit breaker is open in duration format (default: 15m)").Default(defaultConfig.CircuitBreakerCooldown.String()).DurationVar(&cfg.CircuitBreakerCooldown)
+	app.Flag("max-changes-per-apply", "The maximum number of changes applied to the provider at once; larger plans are split into batches applied one after the other in dependency order; 0 applies all changes at once (default: 0)").Default(strconv.Itoa(defaultConfig.MaxChangesPerApply)).IntVar(&cfg.MaxChangesPerApply)
 	app.Flag("once", "When enabled, exits the synchronization loop after the first iteration (default: disabled)").BoolVar(&cfg.Once)
+	app.Flag("leader-elect", "When enabled, only the replica holding the leader election lease synchronizes DNS records while the others wait as standby (default: disabled)").BoolVar(&cfg.LeaderElect)
+	app.Flag("leader-election-namespace", "The namespace of the leader election lease (default: namespace of the pod, or \"default\")").Default(defaultConfig.LeaderElectionNamespace).StringVar(&cfg.LeaderElectionNamespace)
+	app.Flag("leader-election-lease-name", "The name of the leader election lease shared by all replicas (default: external-dns)").Default(defaultConfig.LeaderElectionLeaseName).StringVar(&cfg.LeaderElectionLeaseName)
+	app.Flag("leader-election-lease-duration", "The duration standby replicas wait before taking over a lease which was not renewed (default: 15s)").Default(defaultConfig.LeaderElectionLeaseDuration.String()).DurationVar(&cfg.LeaderElectionLeaseDuration)
+	app.Flag("leader-election-renew-deadline", "The duration the leader retries renewing the lease before giving up leadership (default: 10s)").Default(defaultConfig.LeaderElectionRenewDeadline.String()).DurationVar(&cfg.LeaderElectionRenewDeadline)
+	app.Flag("leader-election-retry-period", "The duration between attempts to acquire or renew the leader election lease (default: 2s)").Default(defaultConfig.LeaderElectionRetryPeriod.String()).DurationVar(&cfg.LeaderElectionRetryPeriod)
 	app.Flag("dry-run", "When enabled, prints DNS record changes rather than actually performing them (default: disabled)").BoolVar(&cfg.DryRun)
 	app.Flag("events", "When enabled, in addition to running every interval, the reconciliation loop will get triggered when supported sources change (default: disabled)").BoolVar(&cfg.UpdateEvents)
 
 	// Miscellaneous flags
 	app.Flag("log-format", "The format in which log messages are printed (default: text, options: text, json)").Default(defaultConfig.LogFormat).EnumVar(&cfg.LogFormat, "text", "json")
 	app.Flag("metrics-address", "Specify where to serve the metrics and health check endpoint (default: :7979)").Default(defaultConfig.MetricsAddress).StringVar(&cfg.MetricsAddress)
+	app.Flag("tracing", "When enabled, export OpenTelemetry traces of the synchronizations via OTLP/HTTP (default: disabled)").BoolVar(&cfg.Tracing)
+	app.Flag("tracing-endpoint", "The host and port of the OTLP/HTTP collector receiving the traces (default: OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4318)").Default(defaultConfig.TracingEndpoint).StringVar(&cfg.TracingEndpoint)
+	app.Flag("tracing-insecure", "When enabled, send the traces to the collector without TLS (default: disabled)").BoolVar(&cfg.TracingInsecure)
+	app.Flag("tracing-sample-ratio", "The ratio of the synchronizations traced, between 0 and 1 (default: 1)").Default(strconv.FormatFloat(defaultConfig.TracingSampleRatio, 'f', -1, 64)).Float64Var(&cfg.TracingSampleRatio)
+	app.Flag("readiness-max-sync-age", "The maximum age of the last successful synchronization before the readiness endpoint fails in duration format (default: disabled)").Default(defaultConfig.ReadinessMaxSyncAge.String()).DurationVar(&cfg.ReadinessMaxSyncAge)
+	app.Flag("debug-endpoints", "When enabled, serve the snapshots of the last synchronization on /debug/records, /debug/desired, /debug/plan and /debug/names of the metrics address (default: disabled)").BoolVar(&cfg.DebugEndpoints)
+	app.Flag("config", "Read flags from a YAML or JSON file mapping flag names to values, e.g. \"domain-filter: [example.com]\"; flags and environment variables take precedence over the file. Changes of the managed record types, policy, interval, of the domain filters within the zones selected at start, and of the annotation and label filters when all sources support it, are applied while running (default: disabled)").Default(defaultConfig.ConfigFile).StringVar(&cfg.ConfigFile)
 	app.Flag("log-level", "Set the level of logging. (default: info, options: panic, debug, info, warning, error, fatal)").Default(defaultConfig.LogLevel).EnumVar(&cfg.LogLevel, allLogLevelsAsStrings()...)
 
+	// Flags related to the simulate command
+	app.Flag("simulate-manifests", "The directory or file of the Kubernetes YAML or JSON manifests read by the sources of the simulate command").Default(defaultConfig.SimulateManifests).StringVar(&cfg.SimulateManifests)
+	app.Flag("simulate-zone-snapshot", "The YAML or JSON file of the zones and records the changes of the simulate command are calculated against").Default(defaultConfig.SimulateZoneSnapshot).StringVar(&cfg.SimulateZoneSnapshot)
+
+	// Flags related to the zone export command
+	app.Flag("zone-export-owned-only", "Only export the records owned by --txt-owner-id with the zone export command (default: disabled)").BoolVar(&cfg.ZoneExportOwnedOnly)
+
+	// Flags related to the migrate command
+	app.Flag("migrate-to-provider", "The DNS provider the migrate command copies the records of --provider to, configured by the same provider flags (required with the migrate command)").Default(defaultConfig.MigrateToProvider).StringVar(&cfg.MigrateToProvider)
+	app.Flag("migrate-batch-size", "The maximum number of records created or updated by every call to the destination provider of the migrate command (default: 100)").Default(strconv.Itoa(defaultConfig.MigrateBatchSize)).IntVar(&cfg.MigrateBatchSize)
+	app.Flag("migrate-dry-run", "Only print the changes and skipped records of the migrate command, without changing the destination provider (default: disabled)").BoolVar(&cfg.MigrateDryRun)
+
 	// Webhook provider
 	app.Flag("webhook-provider-url", "The URL of the remote endpoint to call for the webhook provider (default: http://localhost:8888)").Default(defaultConfig.WebhookProviderURL).StringVar(&cfg.WebhookProviderURL)
 	app.Flag("webhook-provider-read-timeout", "The read timeout for the webhook provider in duration format (default: 5s)").Default(defaultConfig.WebhookProviderReadTimeout.String()).DurationVar(&cfg.WebhookProviderReadTimeout)
@@ -603,10 +795,14 @@ func (cfg *Config) ParseFlags(args []string) error {
 
 	app.Flag("webhook-server", "When enabled, runs as a webhook server instead of a controller. (default: false).").BoolVar(&cfg.WebhookServer)
 
-	_, err := app.Parse(args)
-	if err != nil {
-		return err
-	}
+	// Commands
+	app.Command(CommandRun, "Synchronize the DNS records of the sources with the providers (default)").Default()
+	app.Command("records", "Inspect the DNS records of the providers").Command("list", "Print the records of the providers with the labels of their owner")
+	app.Command(CommandPlan, "Print the changes the next synchronization would apply, without applying them")
+	app.Command(CommandValidateConfig, "Validate the configuration, the sources and the credentials of the providers")
+	app.Command(CommandSimulate, "Print the records and changes resulting from the Kubernetes manifests of --simulate-manifests and the zones of --simulate-zone-snapshot, without accessing any cluster or DNS provider")
+	app.Command(CommandMigrate, "Copy the records owned by --txt-owner-id from --provider to --migrate-to-provider, and verify the destination")
+	app.Command("zone", "Export the DNS records of the providers").Command("export", "Print the records of the providers as an RFC 1035 zone file")
 
-	return nil
+	return app
 }
@@ -42,6 +42,7 @@ var (
 		FQDNTemplate:                "",
 		Compatibility:               "",
 		Provider:                    "google",
+		ApplyConcurrency:            1,
 		GoogleProject:               "",
 		GoogleBatchChangeSize:       1000,
 		GoogleBatchChangeInterval:   time.Second,
@@ -50,6 +51,8 @@ var (
 		ExcludeDomains:              []string{""},
 		RegexDomainFilter:           regexp.MustCompile(""),
 		RegexDomainExclusion:        regexp.MustCompile(""),
+		RegexTargetHostFilter:       regexp.MustCompile(""),
+		RegexTargetHostExclusion:    regexp.MustCompile(""),
 		ZoneNameFilter:              []string{""},
 		ZoneIDFilter:                []string{""},
 		AlibabaCloudConfigFile:      "/etc/kubernetes/alibaba-cloud.json",
@@ -69,6 +72,10 @@ var (
 		AWSZoneCacheDuration:        0 * time.Second,
 		AWSSDServiceCleanup:         false,
 		AWSSDCreateTag:              map[string]string{},
+		ProviderRoutes:              map[string]string{},
+		ProviderInstances:           map[string]string{},
+		RecordTypeTTLs:              map[string]string{},
+		DomainTTLs:                  map[string]string{},
 		AWSDynamoDBTable:            "external-dns",
 		AzureConfigFile:             "/etc/kubernetes/azure.json",
 		AzureResourceGroup:          "",
@@ -93,17 +100,27 @@ var (
 		PDNSServerID:                "localhost",
 		PDNSAPIKey:                  "",
 		Policy:                      "sync",
+		ConflictResolver:            "per-resource",
 		Registry:                    "txt",
 		TXTOwnerID:                  "default",
 		TXTPrefix:                   "",
 		TXTCacheInterval:            0,
 		Interval:                    time.Minute,
 		MinEventSyncInterval:        5 * time.Second,
+		SyncMaxBackoff:              10 * time.Minute,
+		CircuitBreakerCooldown:      15 * time.Minute,
 		Once:                        false,
+		LeaderElectionLeaseName:     "external-dns",
+		LeaderElectionLeaseDuration: 15 * time.Second,
+		LeaderElectionRenewDeadline: 10 * time.Second,
+		LeaderElectionRetryPeriod:   2 * time.Second,
 		DryRun:                      false,
 		UpdateEvents:                false,
 		LogFormat:                   "text",
 		MetricsAddress:              ":7979",
+		TracingSampleRatio:          1.0,
+		Command:                     CommandRun,
+		MigrateBatchSize:            100,
 		LogLevel:                    logrus.InfoLevel.String(),
 		ConnectorSourceServer:       "localhost:8080",
 		ExoscaleAPIEnvironment:      "api",
@@ -141,6 +158,7 @@ var (
 		FQDNTemplate:                "{{.Name}}.service.example.com",
 		Compatibility:               "mate",
 		Provider:                    "google",
+		ApplyConcurrency:            10,
 		GoogleProject:               "project",
 		GoogleBatchChangeSize:       100,
 		GoogleBatchChangeInterval:   time.Second * 2,
@@ -153,6 +171,11 @@ var (
 		ZoneIDFilter:                []string{"/hostedzone/ZTST1", "/hostedzone/ZTST2"},
 		TargetNetFilter:             []string{"10.0.0.0/9", "10.1.0.0/9"},
 		ExcludeTargetNets:           []string{"1.0.0.0/9", "1.1.0.0/9"},
+		TargetHostFilter:            []string{"*.elb.amazonaws.com", "*.cloudfront.net"},
+		ExcludeTargetHosts:          []string{"internal-*"},
+		RegexTargetHostFilter:       regexp.MustCompile(`^lb-\d+\.example\.com$`),
+		RegexTargetHostExclusion:    regexp.MustCompile(`\.internal$`),
+		TargetRewrites:              []string{"*.lb.internal=*.cdn.example.com", "10.0.0.0/24=203.0.113.0/24"},
 		AlibabaCloudConfigFile:      "/etc/kubernetes/alibaba-cloud.json",
 		AWSZoneType:                 "private",
 		AWSZoneTagFilter:            []string{"tag=foo"},
@@ -170,6 +193,9 @@ var (
 		AWSZoneCacheDuration:        10 * time.Second,
 		AWSSDServiceCleanup:         true,
 		AWSSDCreateTag:              map[string]string{"key1": "value1", "key2": "value2"},
+		ProviderRoutes:              map[string]string{"rfc2136": "internal.example.com", "aws": "partner.example.org,partner.example.net"},
+		ProviderInstances:           map[string]string{"partner": "/etc/external-dns/partner.yaml"},
+		SplitHorizonPrivateProvider: "azure-private-dns",
 		AWSDynamoDBTable:            "custom-table",
 		AzureConfigFile:             "azure.json",
 		AzureResourceGroup:          "arg",
@@ -198,19 +224,46 @@ var (
 		TLSClientCert:               "/path/to/cert.pem",
 		TLSClientCertKey:            "/path/to/key.pem",
 		Policy:                      "upsert-only",
+		ConflictResolver:            "priority",
 		Registry:                    "noop",
 		TXTOwnerID:                  "owner-1",
 		TXTPrefix:                   "associated-txt-record",
 		TXTCacheInterval:            12 * time.Hour,
 		Interval:                    10 * time.Minute,
 		MinEventSyncInterval:        50 * time.Second,
+		FullResyncInterval:          time.Hour,
+		SyncMaxBackoff:              time.Hour,
+		CircuitBreakerThreshold:     5,
+		CircuitBreakerCooldown:      30 * time.Minute,
+		MaxChangesPerApply:          50,
 		Once:                        true,
+		LeaderElect:                 true,
+		LeaderElectionNamespace:     "kube-system",
+		LeaderElectionLeaseName:     "external-dns-leader",
+		LeaderElectionLeaseDuration: 30 * time.Second,
+		LeaderElectionRenewDeadline: 20 * time.Second,
+		LeaderElectionRetryPeriod:   5 * time.Second,
 		DryRun:                      true,
 		UpdateEvents:                true,
 		LogFormat:                   "json",
 		MetricsAddress:              "127.0.0.1:9099",
+		Tracing:                     true,
+		TracingEndpoint:             "otel-collector:4318",
+		TracingInsecure:             true,
+		TracingSampleRatio:          0.25,
+		ReadinessMaxSyncAge:         20 * time.Minute,
+		DebugEndpoints:              true,
+		Command:                     CommandRun,
+		SimulateManifests:           "manifests/",
+		SimulateZoneSnapshot:        "zones.yaml",
+		ZoneExportOwnedOnly:         true,
+		MigrateToProvider:           "azure",
+		MigrateBatchSize:            20,
+		MigrateDryRun:               true,
 		LogLevel:                    logrus.DebugLevel.String(),
 		ConnectorSourceServer:       "localhost:8081",
+		ZoneFile:                    "example.com.zone",
+		ZoneFileOrigin:              "example.com",
 		ExoscaleAPIEnvironment:      "api1",
 		ExoscaleAPIZone:             "zone1",
 		ExoscaleAPIKey:              "1",
@@ -223,6 +276,15 @@ var (
 		TransIPPrivateKeyFile:       "/path/to/transip.key",
 		DigitalOceanAPIPageSize:     100,
 		ManagedDNSRecordTypes:       []string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME, endpoint.RecordTypeNS},
+		IgnoredProviderSpecific:     []string{"aws/weight", "cloudflare/proxied"},
+		DefaultTTL:                  5 * time.Minute,
+		MinTTL:                      time.Minute,
+		MaxTTL:                      24 * time.Hour,
+		RecordTypeTTLs:              map[string]string{"NS": "1h"},
+		DomainTTLs:                  map[string]string{"dynamic.example.org": "default=30s,min=10s"},
+		FlattenCNAMEs:               true,
+		FlattenCNAMEZones:           []string{"example.org", "example.com"},
+		FlattenCNAMEResolver:        "10.0.0.10:53",
 		RFC2136BatchChangeSize:      100,
 		IBMCloudProxied:             true,
 		IBMCloudConfigFile:          "ibmcloud.json",
@@ -315,6 +377,13 @@ func TestParseFlags(t *testing.T) {
 				"--target-net-filter=10.1.0.0/9",
 				"--exclude-target-net=1.0.0.0/9",
 				"--exclude-target-net=1.1.0.0/9",
+				"--target-host-filter=*.elb.amazonaws.com",
+				"--target-host-filter=*.cloudfront.net",
+				"--exclude-target-host=internal-*",
+				"--regex-target-host-filter=^lb-\\d+\\.example\\.com$",
+				"--regex-target-host-exclusion=\\.internal$",
+				"--target-rewrite=*.lb.internal=*.cdn.example.com",
+				"--target-rewrite=10.0.0.0/24=203.0.113.0/24",
 				"--aws-zone-type=private",
 				"--aws-zone-tags=tag=foo",
 				"--aws-zone-match-parent",
@@ -332,8 +401,14 @@ func TestParseFlags(t *testing.T) {
 				"--aws-sd-service-cleanup",
 				"--aws-sd-create-tag=key1=value1",
 				"--aws-sd-create-tag=key2=value2",
+				"--provider-route=rfc2136=internal.example.com",
+				"--provider-route=aws=partner.example.org,partner.example.net",
+				"--provider-instance=partner=/etc/external-dns/partner.yaml",
+				"--split-horizon-private-provider=azure-private-dns",
+				"--apply-concurrency=10",
 				"--no-aws-evaluate-target-health",
 				"--policy=upsert-only",
+				"--conflict-resolver=priority",
 				"--registry=noop",
 				"--txt-owner-id=owner-1",
 				"--txt-prefix=associated-txt-record",
@@ -341,13 +416,38 @@ func TestParseFlags(t *testing.T) {
 				"--dynamodb-table=custom-table",
 				"--interval=10m",
 				"--min-event-sync-interval=50s",
+				"--full-resync-interval=1h",
+				"--sync-max-backoff=1h",
+				"--circuit-breaker-threshold=5",
+				"--circuit-breaker-cooldown=30m",
+				"--max-changes-per-apply=50",
 				"--once",
+				"--leader-elect",
+				"--leader-election-namespace=kube-system",
+				"--leader-election-lease-name=external-dns-leader",
+				"--leader-election-lease-duration=30s",
+				"--leader-election-renew-deadline=20s",
+				"--leader-election-retry-period=5s",
 				"--dry-run",
 				"--events",
 				"--log-format=json",
 				"--metrics-address=127.0.0.1:9099",
+				"--tracing",
+				"--tracing-endpoint=otel-collector:4318",
+				"--tracing-insecure",
+				"--tracing-sample-ratio=0.25",
+				"--readiness-max-sync-age=20m",
+				"--debug-endpoints",
+				"--simulate-manifests=manifests/",
+				"--simulate-zone-snapshot=zones.yaml",
+				"--zone-export-owned-only",
+				"--migrate-to-provider=azure",
+				"--migrate-batch-size=20",
+				"--migrate-dry-run",
 				"--log-level=debug",
 				"--connector-source-server=localhost:8081",
+				"--zone-file=example.com.zone",
+				"--zone-file-origin=example.com",
 				"--exoscale-apienv=api1",
 				"--exoscale-apizone=zone1",
 				"--exoscale-apikey=1",
@@ -363,6 +463,17 @@ func TestParseFlags(t *testing.T) {
 				"--managed-record-types=AAAA",
 				"--managed-record-types=CNAME",
 				"--managed-record-types=NS",
+				"--ignore-provider-specific=aws/weight",
+				"--ignore-provider-specific=cloudflare/proxied",
+				"--default-ttl=5m",
+				"--min-ttl=1m",
+				"--max-ttl=24h",
+				"--record-type-ttl=NS=1h",
+				"--domain-ttl=dynamic.example.org=default=30s,min=10s",
+				"--flatten-cnames",
+				"--flatten-cname-zone=example.org",
+				"--flatten-cname-zone=example.com",
+				"--flatten-cname-resolver=10.0.0.10:53",
 				"--rfc2136-batch-change-size=100",
 				"--ibmcloud-proxied",
 				"--ibmcloud-config-file=ibmcloud.json",
@@ -419,6 +530,11 @@ func TestParseFlags(t *testing.T) {
 				"EXTERNAL_DNS_REGEX_DOMAIN_EXCLUSION":          "xapi\\.(example\\.org|company\\.com)$",
 				"EXTERNAL_DNS_TARGET_NET_FILTER":               "10.0.0.0/9\n10.1.0.0/9",
 				"EXTERNAL_DNS_EXCLUDE_TARGET_NET":              "1.0.0.0/9\n1.1.0.0/9",
+				"EXTERNAL_DNS_TARGET_HOST_FILTER":              "*.elb.amazonaws.com\n*.cloudfront.net",
+				"EXTERNAL_DNS_EXCLUDE_TARGET_HOST":             "internal-*",
+				"EXTERNAL_DNS_REGEX_TARGET_HOST_FILTER":        "^lb-\\d+\\.example\\.com$",
+				"EXTERNAL_DNS_REGEX_TARGET_HOST_EXCLUSION":     "\\.internal$",
+				"EXTERNAL_DNS_TARGET_REWRITE":                  "*.lb.internal=*.cdn.example.com\n10.0.0.0/24=203.0.113.0/24",
 				"EXTERNAL_DNS_PDNS_SERVER":                     "http://ns.example.com:8081",
 				"EXTERNAL_DNS_PDNS_ID":                         "localhost",
 				"EXTERNAL_DNS_PDNS_API_KEY":                    "some-secret-key",
@@ -445,19 +561,49 @@ func TestParseFlags(t *testing.T) {
 				"EXTERNAL_DNS_AWS_ZONES_CACHE_DURATION":        "10s",
 				"EXTERNAL_DNS_AWS_SD_SERVICE_CLEANUP":          "true",
 				"EXTERNAL_DNS_AWS_SD_CREATE_TAG":               "key1=value1\nkey2=value2",
+				"EXTERNAL_DNS_PROVIDER_ROUTE":                  "rfc2136=internal.example.com\naws=partner.example.org,partner.example.net",
+				"EXTERNAL_DNS_PROVIDER_INSTANCE":               "partner=/etc/external-dns/partner.yaml",
+				"EXTERNAL_DNS_SPLIT_HORIZON_PRIVATE_PROVIDER":  "azure-private-dns",
+				"EXTERNAL_DNS_APPLY_CONCURRENCY":               "10",
 				"EXTERNAL_DNS_DYNAMODB_TABLE":                  "custom-table",
 				"EXTERNAL_DNS_POLICY":                          "upsert-only",
+				"EXTERNAL_DNS_CONFLICT_RESOLVER":               "priority",
 				"EXTERNAL_DNS_REGISTRY":                        "noop",
 				"EXTERNAL_DNS_TXT_OWNER_ID":                    "owner-1",
 				"EXTERNAL_DNS_TXT_PREFIX":                      "associated-txt-record",
 				"EXTERNAL_DNS_TXT_CACHE_INTERVAL":              "12h",
 				"EXTERNAL_DNS_INTERVAL":                        "10m",
 				"EXTERNAL_DNS_MIN_EVENT_SYNC_INTERVAL":         "50s",
+				"EXTERNAL_DNS_FULL_RESYNC_INTERVAL":            "1h",
+				"EXTERNAL_DNS_SYNC_MAX_BACKOFF":                "1h",
+				"EXTERNAL_DNS_CIRCUIT_BREAKER_THRESHOLD":       "5",
+				"EXTERNAL_DNS_CIRCUIT_BREAKER_COOLDOWN":        "30m",
+				"EXTERNAL_DNS_MAX_CHANGES_PER_APPLY":           "50",
 				"EXTERNAL_DNS_ONCE":                            "1",
+				"EXTERNAL_DNS_LEADER_ELECT":                    "1",
+				"EXTERNAL_DNS_LEADER_ELECTION_NAMESPACE":       "kube-system",
+				"EXTERNAL_DNS_LEADER_ELECTION_LEASE_NAME":      "external-dns-leader",
+				"EXTERNAL_DNS_LEADER_ELECTION_LEASE_DURATION":  "30s",
+				"EXTERNAL_DNS_LEADER_ELECTION_RENEW_DEADLINE":  "20s",
+				"EXTERNAL_DNS_LEADER_ELECTION_RETRY_PERIOD":    "5s",
 				"EXTERNAL_DNS_DRY_RUN":                         "1",
 				"EXTERNAL_DNS_EVENTS":                          "1",
 				"EXTERNAL_DNS_LOG_FORMAT":                      "json",
 				"EXTERNAL_DNS_METRICS_ADDRESS":                 "127.0.0.1:9099",
+				"EXTERNAL_DNS_TRACING":                         "1",
+				"EXTERNAL_DNS_TRACING_ENDPOINT":                "otel-collector:4318",
+				"EXTERNAL_DNS_TRACING_INSECURE":                "1",
+				"EXTERNAL_DNS_TRACING_SAMPLE_RATIO":            "0.25",
+				"EXTERNAL_DNS_READINESS_MAX_SYNC_AGE":          "20m",
+				"EXTERNAL_DNS_DEBUG_ENDPOINTS":                 "1",
+				"EXTERNAL_DNS_SIMULATE_MANIFESTS":              "manifests/",
+				"EXTERNAL_DNS_SIMULATE_ZONE_SNAPSHOT":          "zones.yaml",
+				"EXTERNAL_DNS_ZONE_EXPORT_OWNED_ONLY":          "1",
+				"EXTERNAL_DNS_MIGRATE_TO_PROVIDER":             "azure",
+				"EXTERNAL_DNS_MIGRATE_BATCH_SIZE":              "20",
+				"EXTERNAL_DNS_MIGRATE_DRY_RUN":                 "1",
+				"EXTERNAL_DNS_ZONE_FILE":                       "example.com.zone",
+				"EXTERNAL_DNS_ZONE_FILE_ORIGIN":                "example.com",
 				"EXTERNAL_DNS_LOG_LEVEL":                       "debug",
 				"EXTERNAL_DNS_CONNECTOR_SOURCE_SERVER":         "localhost:8081",
 				"EXTERNAL_DNS_EXOSCALE_APIENV":                 "api1",
@@ -472,6 +618,15 @@ func TestParseFlags(t *testing.T) {
 				"EXTERNAL_DNS_TRANSIP_KEYFILE":                 "/path/to/transip.key",
 				"EXTERNAL_DNS_DIGITALOCEAN_API_PAGE_SIZE":      "100",
 				"EXTERNAL_DNS_MANAGED_RECORD_TYPES":            "A\nAAAA\nCNAME\nNS",
+				"EXTERNAL_DNS_IGNORE_PROVIDER_SPECIFIC":        "aws/weight\ncloudflare/proxied",
+				"EXTERNAL_DNS_DEFAULT_TTL":                     "5m",
+				"EXTERNAL_DNS_MIN_TTL":                         "1m",
+				"EXTERNAL_DNS_MAX_TTL":                         "24h",
+				"EXTERNAL_DNS_RECORD_TYPE_TTL":                 "NS=1h",
+				"EXTERNAL_DNS_DOMAIN_TTL":                      "dynamic.example.org=default=30s,min=10s",
+				"EXTERNAL_DNS_FLATTEN_CNAMES":                  "1",
+				"EXTERNAL_DNS_FLATTEN_CNAME_ZONE":              "example.org\nexample.com",
+				"EXTERNAL_DNS_FLATTEN_CNAME_RESOLVER":          "10.0.0.10:53",
 				"EXTERNAL_DNS_RFC2136_BATCH_CHANGE_SIZE":       "100",
 				"EXTERNAL_DNS_IBMCLOUD_PROXIED":                "1",
 				"EXTERNAL_DNS_IBMCLOUD_CONFIG_FILE":            "ibmcloud.json",
@@ -522,3 +677,33 @@ func TestPasswordsNotLogged(t *testing.T) {
 	assert.False(t, strings.Contains(s, "pdns-api-key"))
 	assert.False(t, strings.Contains(s, "tsig-secret"))
 }
+
+func TestParseFlagsCommands(t *testing.T) {
+	for _, tc := range []struct {
+		args     []string
+		expected string
+		err      string
+	}{
+		{args: []string{"--source=service", "--provider=google"}, expected: CommandRun},
+		{args: []string{"run", "--source=service", "--provider=google"}, expected: CommandRun},
+		{args: []string{"records", "list", "--source=service", "--provider=google"}, expected: CommandRecordsList},
+		{args: []string{"--source=service", "plan", "--provider=google"}, expected: CommandPlan},
+		{args: []string{"validate-config", "--source=service", "--provider=google"}, expected: CommandValidateConfig},
+		{args: []string{"simulate", "--source=service", "--provider=inmemory"}, expected: CommandSimulate},
+		{args: []string{"migrate", "--source=service", "--provider=aws", "--migrate-to-provider=google"}, expected: CommandMigrate},
+		{args: []string{"zone", "export", "--source=service", "--provider=google"}, expected: CommandZoneExport},
+		{args: []string{"records", "--source=service", "--provider=google"}, err: "must select a subcommand"},
+		{args: []string{"apply", "--source=service", "--provider=google"}, err: "unexpected apply"},
+	} {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			cfg := NewConfig()
+			err := cfg.ParseFlags(tc.args)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, cfg.Command)
+		})
+	}
+}
@@ -19,10 +19,14 @@ package validation
 import (
 	"errors"
 	"fmt"
+	"net"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/labels"
 
+	"sigs.k8s.io/external-dns/endpoint"
 	"sigs.k8s.io/external-dns/pkg/apis/externaldns"
+	"sigs.k8s.io/external-dns/plan"
 )
 
 // ValidateConfig performs validation on the Config object
@@ -39,14 +43,14 @@ func ValidateConfig(cfg *externaldns.Config) error {
 	}
 
 	// Azure provider specific validations
-	if cfg.Provider == "azure" {
+	if usesProvider(cfg, "azure") {
 		if cfg.AzureConfigFile == "" {
 			return errors.New("no Azure config file specified")
 		}
 	}
 
 	// Akamai provider specific validations
-	if cfg.Provider == "akamai" {
+	if usesProvider(cfg, "akamai") {
 		if cfg.AkamaiServiceConsumerDomain == "" && cfg.AkamaiEdgercPath != "" {
 			return errors.New("no Akamai ServiceConsumerDomain specified")
 		}
@@ -61,7 +65,7 @@ func ValidateConfig(cfg *externaldns.Config) error {
 		}
 	}
 
-	if cfg.Provider == "rfc2136" {
+	if usesProvider(cfg, "rfc2136") {
 		if cfg.RFC2136MinTTL < 0 {
 			return errors.New("TTL specified for rfc2136 is negative")
 		}
@@ -81,6 +85,30 @@ func ValidateConfig(cfg *externaldns.Config) error {
 		}
 	}
 
+	for name, domains := range cfg.ProviderRoutes {
+		if name == cfg.Provider {
+			return fmt.Errorf("--provider-route for %s routes to the default provider", name)
+		}
+		if strings.Trim(domains, " ,") == "" {
+			return fmt.Errorf("--provider-route for %s specifies no domains", name)
+		}
+	}
+
+	for name := range cfg.ProviderInstances {
+		if _, ok := cfg.ProviderRoutes[name]; !ok && name != cfg.SplitHorizonPrivateProvider {
+			return fmt.Errorf("--provider-instance %s is used by neither --provider-route nor --split-horizon-private-provider", name)
+		}
+	}
+
+	if cfg.SplitHorizonPrivateProvider != "" {
+		if cfg.SplitHorizonPrivateProvider == cfg.Provider {
+			return errors.New("--split-horizon-private-provider must differ from --provider")
+		}
+		if _, ok := cfg.ProviderRoutes[cfg.SplitHorizonPrivateProvider]; ok {
+			return errors.New("--split-horizon-private-provider must not be used in --provider-route")
+		}
+	}
+
 	if cfg.IgnoreHostnameAnnotation && cfg.FQDNTemplate == "" {
 		return errors.New("FQDN Template must be set if ignoring annotations")
 	}
@@ -89,9 +117,84 @@ func ValidateConfig(cfg *externaldns.Config) error {
 		return errors.New("txt-prefix and txt-suffix are mutual exclusive")
 	}
 
+	if cfg.SyncMaxBackoff != 0 && cfg.SyncMaxBackoff < cfg.Interval {
+		return errors.New("--sync-max-backoff must not be shorter than --interval")
+	}
+	if cfg.CircuitBreakerThreshold < 0 {
+		return errors.New("--circuit-breaker-threshold must not be negative")
+	}
+	if cfg.MaxChangesPerApply < 0 {
+		return errors.New("--max-changes-per-apply must not be negative")
+	}
+	if _, err := endpoint.ParseTargetRewriteRules(cfg.TargetRewrites); err != nil {
+		return err
+	}
+	if _, err := plan.NewTTLPolicy(cfg.DefaultTTL, cfg.MinTTL, cfg.MaxTTL, cfg.RecordTypeTTLs, cfg.DomainTTLs); err != nil {
+		return fmt.Errorf("invalid TTL policy: %w", err)
+	}
+	if cfg.FlattenCNAMEResolver != "" {
+		if _, _, err := net.SplitHostPort(cfg.FlattenCNAMEResolver); err != nil {
+			return fmt.Errorf("--flatten-cname-resolver must be in host:port format: %w", err)
+		}
+	}
+	if cfg.ApplyConcurrency < 0 {
+		return errors.New("--apply-concurrency must not be negative")
+	}
+	if cfg.TracingSampleRatio < 0 || cfg.TracingSampleRatio > 1 {
+		return errors.New("--tracing-sample-ratio must be between 0 and 1")
+	}
+	if cfg.FullResyncInterval > 0 && !cfg.UpdateEvents {
+		return errors.New("--full-resync-interval requires --events")
+	}
+	// the flattened CNAME records are resolved again in full synchronizations only
+	if cfg.FullResyncInterval > 0 && cfg.FlattenCNAMEs {
+		return errors.New("--full-resync-interval can't be used with --flatten-cnames")
+	}
+	if cfg.Command == externaldns.CommandSimulate {
+		if cfg.SimulateManifests == "" || cfg.SimulateZoneSnapshot == "" {
+			return errors.New("the simulate command requires --simulate-manifests and --simulate-zone-snapshot")
+		}
+		if cfg.Registry != "txt" && cfg.Registry != "noop" {
+			return errors.New("the simulate command only supports the txt and noop registries")
+		}
+	}
+	if cfg.Command == externaldns.CommandMigrate {
+		if cfg.MigrateToProvider == "" {
+			return errors.New("the migrate command requires --migrate-to-provider")
+		}
+		if cfg.MigrateBatchSize < 0 {
+			return errors.New("--migrate-batch-size must not be negative")
+		}
+	}
+
+	if cfg.LeaderElect {
+		if cfg.LeaderElectionLeaseDuration <= cfg.LeaderElectionRenewDeadline {
+			return errors.New("--leader-election-lease-duration must be greater than --leader-election-renew-deadline")
+		}
+		if cfg.LeaderElectionRenewDeadline <= cfg.LeaderElectionRetryPeriod {
+			return errors.New("--leader-election-renew-deadline must be greater than --leader-election-retry-period")
+		}
+	}
+
 	_, err := labels.Parse(cfg.LabelFilter)
 	if err != nil {
 		return errors.New("--label-filter does not specify a valid label selector")
 	}
 	return nil
 }
+
+// usesProvider returns whether the provider is the default provider, the private provider or one of the routed providers.
+// The provider instances configured by --provider-instance are validated with the configuration of their file.
+func usesProvider(cfg *externaldns.Config, name string) bool {
+	if cfg.Provider == name {
+		return true
+	}
+	if _, ok := cfg.ProviderInstances[name]; ok {
+		return false
+	}
+	if cfg.SplitHorizonPrivateProvider == name {
+		return true
+	}
+	_, ok := cfg.ProviderRoutes[name]
+	return ok
+}
@@ -18,6 +18,7 @@ package validation
 
 import (
 	"testing"
+	"time"
 
 	"sigs.k8s.io/external-dns/pkg/apis/externaldns"
 
@@ -72,6 +73,180 @@ func TestValidateBadIgnoreHostnameAnnotationsConfig(t *testing.T) {
 	assert.Error(t, ValidateConfig(cfg))
 }
 
+func TestValidateBadBackoffConfig(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.Interval = time.Minute
+	cfg.SyncMaxBackoff = 30 * time.Second
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.SyncMaxBackoff = 0
+	require.NoError(t, ValidateConfig(cfg))
+
+	cfg.CircuitBreakerThreshold = -1
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateFlattenCNAMEResolver(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.FlattenCNAMEResolver = "10.0.0.10:53"
+	require.NoError(t, ValidateConfig(cfg))
+
+	cfg.FlattenCNAMEResolver = "10.0.0.10"
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateMaxChangesPerApply(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.MaxChangesPerApply = 10
+	require.NoError(t, ValidateConfig(cfg))
+
+	cfg.MaxChangesPerApply = -1
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateTargetRewrites(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.TargetRewrites = []string{"*.lb.internal=*.cdn.example.com", "10.0.0.0/24=203.0.113.0/24"}
+	require.NoError(t, ValidateConfig(cfg))
+
+	cfg.TargetRewrites = []string{"10.0.0.0/24=203.0.113.0/25"}
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateTTLPolicy(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.MinTTL = time.Minute
+	cfg.MaxTTL = time.Hour
+	cfg.DomainTTLs = map[string]string{"example.org": "default=5m,max=30m"}
+	require.NoError(t, ValidateConfig(cfg))
+
+	cfg.MinTTL = 2 * time.Hour
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.MinTTL = -time.Minute
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.MinTTL = 0
+	cfg.RecordTypeTTLs = map[string]string{"NS": "soon"}
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateFullResyncIntervalRequiresEvents(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.FullResyncInterval = time.Hour
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.UpdateEvents = true
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.FlattenCNAMEs = true
+	assert.EqualError(t, ValidateConfig(cfg), "--full-resync-interval can't be used with --flatten-cnames")
+}
+
+func TestValidateSimulate(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.Command = externaldns.CommandSimulate
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.SimulateManifests = "manifests/"
+	cfg.SimulateZoneSnapshot = "zones.yaml"
+	cfg.Registry = "txt"
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.Registry = "dynamodb"
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateMigrate(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.Command = externaldns.CommandMigrate
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.MigrateToProvider = "google"
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.MigrateBatchSize = -1
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateBadTracingSampleRatio(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.TracingSampleRatio = 1.5
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.TracingSampleRatio = 0.5
+	assert.NoError(t, ValidateConfig(cfg))
+}
+
+func TestValidateBadApplyConcurrency(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.ApplyConcurrency = -1
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateBadLeaderElectionConfig(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.LeaderElect = true
+	cfg.LeaderElectionLeaseDuration = 15 * time.Second
+	cfg.LeaderElectionRenewDeadline = 10 * time.Second
+	cfg.LeaderElectionRetryPeriod = 2 * time.Second
+	require.NoError(t, ValidateConfig(cfg))
+
+	cfg.LeaderElectionRenewDeadline = cfg.LeaderElectionLeaseDuration
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.LeaderElectionRenewDeadline = cfg.LeaderElectionRetryPeriod
+	cfg.LeaderElectionLeaseDuration = 15 * time.Second
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateProviderRoutes(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.ProviderRoutes = map[string]string{"rfc2136": "internal.example.com"}
+	cfg.RFC2136BatchChangeSize = 50
+	require.NoError(t, ValidateConfig(cfg))
+
+	cfg.RFC2136BatchChangeSize = 0
+	assert.Error(t, ValidateConfig(cfg), "routed providers are validated")
+
+	cfg = newValidConfig(t)
+	cfg.ProviderRoutes = map[string]string{cfg.Provider: "internal.example.com"}
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg = newValidConfig(t)
+	cfg.ProviderRoutes = map[string]string{"rfc2136": ","}
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateProviderInstances(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.ProviderRoutes = map[string]string{"rfc2136": "internal.example.com"}
+	cfg.ProviderInstances = map[string]string{"rfc2136": "/etc/external-dns/rfc2136.yaml"}
+	cfg.RFC2136BatchChangeSize = 0
+	require.NoError(t, ValidateConfig(cfg), "provider instances are validated with the configuration of their file")
+
+	cfg.ProviderInstances = map[string]string{"partner": "/etc/external-dns/partner.yaml"}
+	cfg.RFC2136BatchChangeSize = 50
+	assert.Error(t, ValidateConfig(cfg), "provider instances must be routed to")
+
+	cfg.SplitHorizonPrivateProvider = "partner"
+	assert.NoError(t, ValidateConfig(cfg))
+}
+
+func TestValidateSplitHorizonPrivateProvider(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.SplitHorizonPrivateProvider = "rfc2136"
+	cfg.RFC2136BatchChangeSize = 50
+	require.NoError(t, ValidateConfig(cfg))
+
+	cfg.ProviderRoutes = map[string]string{"rfc2136": "internal.example.com"}
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg = newValidConfig(t)
+	cfg.SplitHorizonPrivateProvider = cfg.Provider
+	assert.Error(t, ValidateConfig(cfg))
+}
+
 func TestValidateBadRfc2136Config(t *testing.T) {
 	cfg := externaldns.NewConfig()
 
@@ -0,0 +1,212 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package migration copies the records managed by ExternalDNS from one provider to another.
+package migration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/registry"
+)
+
+// Translator adapts a record read from the source provider to the destination provider, usually
+// by translating its provider-specific properties. It returns nil to skip the record.
+type Translator func(ep *endpoint.Endpoint) (*endpoint.Endpoint, error)
+
+var (
+	translatorsMutex sync.RWMutex
+	translators      = map[[2]string]Translator{}
+)
+
+// RegisterTranslator registers the translator of the records migrated from the provider called from
+// to the provider called to.
+func RegisterTranslator(from, to string, translator Translator) {
+	translatorsMutex.Lock()
+	defer translatorsMutex.Unlock()
+	translators[[2]string{from, to}] = translator
+}
+
+// TranslatorFor returns the translator registered for the migration from the provider called from to
+// the provider called to. Without one, the provider-specific properties of the records are kept
+// between providers of the same name and dropped otherwise.
+func TranslatorFor(from, to string) Translator {
+	translatorsMutex.RLock()
+	defer translatorsMutex.RUnlock()
+	if translator, ok := translators[[2]string{from, to}]; ok {
+		return translator
+	}
+	if from == to {
+		return func(ep *endpoint.Endpoint) (*endpoint.Endpoint, error) { return ep, nil }
+	}
+	return DropProviderSpecific
+}
+
+// DropProviderSpecific is the Translator removing the provider-specific properties of the records.
+func DropProviderSpecific(ep *endpoint.Endpoint) (*endpoint.Endpoint, error) {
+	ep.ProviderSpecific = nil
+	return ep, nil
+}
+
+// Migration copies the records of a source registry to a destination registry. The records owned by
+// the owner ID of the source registry are created or updated in the destination registry, which
+// claims them with its own owner ID; the records of other owners are skipped. Nothing is deleted
+// from the destination.
+type Migration struct {
+	From registry.Registry
+	To   registry.Registry
+	// DomainFilter selects the records migrated.
+	DomainFilter endpoint.DomainFilterInterface
+	// ManagedRecordTypes are the record types supported by the destination, other records are skipped.
+	ManagedRecordTypes []string
+	// ExcludeRecordTypes are record types which are never migrated.
+	ExcludeRecordTypes []string
+	// Translate adapts the records to the destination provider.
+	Translate Translator
+	// BatchSize is the maximum number of records of every call to the destination, 0 for no limit.
+	BatchSize int
+	// SetIdentifiers keeps the records with a set identifier, the routing policies they belong to must
+	// be supported by the destination provider.
+	SetIdentifiers bool
+}
+
+// SkippedRecord is a record of the source which isn't migrated.
+type SkippedRecord struct {
+	Endpoint *endpoint.Endpoint
+	Reason   string
+}
+
+// Result describes the records of a migration.
+type Result struct {
+	// Desired are the records expected in the destination after the migration.
+	Desired []*endpoint.Endpoint
+	// Changes are the changes applied to the destination.
+	Changes *plan.Changes
+	// Skipped are the records of the source which aren't migrated.
+	Skipped []SkippedRecord
+}
+
+// Plan reads both registries and returns the records to migrate with the changes of the destination.
+func (m *Migration) Plan(ctx context.Context) (*Result, error) {
+	sourceRecords, err := m.From.Records(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading the records of the source: %w", err)
+	}
+	destinationRecords, err := m.To.Records(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading the records of the destination: %w", err)
+	}
+
+	result := &Result{}
+	translate := m.Translate
+	if translate == nil {
+		translate = DropProviderSpecific
+	}
+	ownerID := m.From.OwnerID()
+	for _, ep := range sourceRecords {
+		if m.DomainFilter != nil && !m.DomainFilter.Match(ep.DNSName) {
+			continue
+		}
+		if reason := m.skipReason(ep, ownerID); reason != "" {
+			result.Skipped = append(result.Skipped, SkippedRecord{Endpoint: ep, Reason: reason})
+			continue
+		}
+		translated, err := translate(ep.DeepCopy())
+		if err != nil {
+			return nil, fmt.Errorf("translating %s %s: %w", ep.DNSName, ep.RecordType, err)
+		}
+		if translated == nil {
+			result.Skipped = append(result.Skipped, SkippedRecord{Endpoint: ep, Reason: "skipped by the translator"})
+			continue
+		}
+		result.Desired = append(result.Desired, translated)
+	}
+
+	p := &plan.Plan{
+		Policies:       []plan.Policy{&plan.UpsertOnlyPolicy{}},
+		Current:        destinationRecords,
+		Desired:        result.Desired,
+		DomainFilter:   endpoint.MatchAllDomainFilters{m.DomainFilter, m.To.GetDomainFilter()},
+		ManagedRecords: m.ManagedRecordTypes,
+		ExcludeRecords: m.ExcludeRecordTypes,
+		OwnerID:        m.To.OwnerID(),
+	}
+	result.Changes = p.Calculate().Changes
+	return result, nil
+}
+
+// skipReason returns why ep isn't migrated, or an empty string when it is.
+func (m *Migration) skipReason(ep *endpoint.Endpoint, ownerID string) string {
+	if ownerID != "" && !ep.IsOwnedBy(ownerID) {
+		if owner := ep.Labels[endpoint.OwnerLabelKey]; owner != "" {
+			return fmt.Sprintf("owned by %s", owner)
+		}
+		return "not owned by ExternalDNS"
+	}
+	if !plan.IsManagedRecord(ep.RecordType, m.ManagedRecordTypes, m.ExcludeRecordTypes) {
+		return fmt.Sprintf("record type %s is not managed", ep.RecordType)
+	}
+	if ep.SetIdentifier != "" && !m.SetIdentifiers {
+		return "set identifiers are specific to the routing policies of the source provider"
+	}
+	return ""
+}
+
+// Apply applies the changes of result to the destination in batches of at most BatchSize records.
+func (m *Migration) Apply(ctx context.Context, result *Result) error {
+	for _, batch := range result.Changes.Batches(m.BatchSize) {
+		if err := m.To.ApplyChanges(ctx, batch); err != nil {
+			return err
+		}
+		log.Infof("Migrated %d records", len(batch.Create)+len(batch.UpdateNew))
+	}
+	return nil
+}
+
+// Verify reads the destination again and returns the differences with the desired records of result.
+func (m *Migration) Verify(ctx context.Context, result *Result) ([]string, error) {
+	records, err := m.To.Records(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading the records of the destination: %w", err)
+	}
+	current := make(map[endpoint.EndpointKey]*endpoint.Endpoint, len(records))
+	for _, ep := range records {
+		current[ep.Key()] = ep
+	}
+
+	var differences []string
+	for _, desired := range result.Desired {
+		if !plan.IsManagedRecord(desired.RecordType, m.ManagedRecordTypes, m.ExcludeRecordTypes) {
+			continue
+		}
+		ep, ok := current[desired.Key()]
+		switch {
+		case !ok:
+			differences = append(differences, fmt.Sprintf("%s %s is missing", desired.DNSName, desired.RecordType))
+		case !ep.Targets.SameAs(desired.RecordType, desired.Targets):
+			differences = append(differences, fmt.Sprintf("%s %s has targets %s instead of %s", desired.DNSName, desired.RecordType, ep.Targets, desired.Targets))
+		case desired.RecordTTL.IsConfigured() && ep.RecordTTL.IsConfigured() && ep.RecordTTL != desired.RecordTTL:
+			differences = append(differences, fmt.Sprintf("%s %s has TTL %d instead of %d", desired.DNSName, desired.RecordType, ep.RecordTTL, desired.RecordTTL))
+		}
+	}
+	return differences, nil
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider/inmemory"
+	"sigs.k8s.io/external-dns/registry"
+)
+
+var managedRecordTypes = []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME}
+
+func newTXTRegistry(t *testing.T, p *inmemory.InMemoryProvider, ownerID string) *registry.TXTRegistry {
+	t.Helper()
+	r, err := registry.NewTXTRegistry(p, "", "", ownerID, 0, "", managedRecordTypes, nil, false, nil, false, nil, false)
+	require.NoError(t, err)
+	return r
+}
+
+func TestMigration(t *testing.T) {
+	ctx := context.Background()
+	from := inmemory.NewInMemoryProvider(inmemory.InMemoryInitZones([]string{"example.com"}))
+	fromRegistry := newTXTRegistry(t, from, "cluster")
+	other := newTXTRegistry(t, from, "other")
+	require.NoError(t, fromRegistry.ApplyChanges(ctx, &plan.Changes{Create: []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("www.example.com", endpoint.RecordTypeA, 300, "1.2.3.4").WithProviderSpecific("alias", "false"),
+		endpoint.NewEndpoint("api.example.com", endpoint.RecordTypeCNAME, "lb.example.net"),
+		endpoint.NewEndpoint("weighted.example.com", endpoint.RecordTypeA, "5.6.7.8").WithSetIdentifier("blue"),
+	}}))
+	require.NoError(t, other.ApplyChanges(ctx, &plan.Changes{Create: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("other.example.com", endpoint.RecordTypeA, "9.9.9.9"),
+	}}))
+
+	to := inmemory.NewInMemoryProvider(inmemory.InMemoryInitZones([]string{"example.com"}))
+	require.NoError(t, to.ApplyChanges(ctx, &plan.Changes{Create: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("unmanaged.example.com", endpoint.RecordTypeA, "10.0.0.1"),
+	}}))
+	toRegistry := newTXTRegistry(t, to, "cluster")
+
+	m := &Migration{
+		From:               fromRegistry,
+		To:                 toRegistry,
+		ManagedRecordTypes: managedRecordTypes,
+		Translate:          TranslatorFor("aws", "inmemory"),
+		BatchSize:          1,
+	}
+	result, err := m.Plan(ctx)
+	require.NoError(t, err)
+	assert.Len(t, result.Changes.Create, 2)
+	assert.Empty(t, result.Changes.Delete)
+	reasons := map[string]string{}
+	for _, skipped := range result.Skipped {
+		reasons[skipped.Endpoint.DNSName] = skipped.Reason
+	}
+	assert.Equal(t, map[string]string{
+		"other.example.com":    "owned by other",
+		"weighted.example.com": "set identifiers are specific to the routing policies of the source provider",
+	}, reasons)
+
+	differences, err := m.Verify(ctx, result)
+	require.NoError(t, err)
+	assert.Len(t, differences, 2)
+
+	require.NoError(t, m.Apply(ctx, result))
+	differences, err = m.Verify(ctx, result)
+	require.NoError(t, err)
+	assert.Empty(t, differences)
+
+	records, err := toRegistry.Records(ctx)
+	require.NoError(t, err)
+	for _, ep := range records {
+		if ep.DNSName == "www.example.com" {
+			assert.True(t, ep.IsOwnedBy("cluster"))
+			assert.Empty(t, ep.ProviderSpecific)
+		}
+	}
+
+	result, err = m.Plan(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, result.Changes.Create, "a second migration has nothing to do")
+	assert.Empty(t, result.Changes.UpdateNew)
+}
+
+func TestTranslatorFor(t *testing.T) {
+	ep := endpoint.NewEndpoint("www.example.com", endpoint.RecordTypeA, "1.2.3.4").WithProviderSpecific("alias", "false")
+
+	same, err := TranslatorFor("aws", "aws")(ep.DeepCopy())
+	require.NoError(t, err)
+	assert.Len(t, same.ProviderSpecific, 1)
+
+	RegisterTranslator("aws", "test", func(ep *endpoint.Endpoint) (*endpoint.Endpoint, error) { return nil, nil })
+	skipped, err := TranslatorFor("aws", "test")(ep.DeepCopy())
+	require.NoError(t, err)
+	assert.Nil(t, skipped)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package zonefile converts endpoints from and to zone files in the RFC 1035 master file format
+// read by BIND and most other DNS servers.
+package zonefile
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/miekg/dns"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// DefaultTTL is the TTL of the records written without a configured TTL.
+const DefaultTTL = 300
+
+// maxTXTStringLength is the maximum length of a character string of a TXT record.
+const maxTXTStringLength = 255
+
+// Write writes the endpoints as a zone file to w, with one record for every target. The names of the
+// records are absolute, so the file can be read without an origin, and the records are sorted by
+// name and type. The labels and provider-specific properties of the endpoints aren't written.
+func Write(w io.Writer, endpoints []*endpoint.Endpoint) error {
+	sorted := make([]*endpoint.Endpoint, len(endpoints))
+	copy(sorted, endpoints)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].DNSName != sorted[j].DNSName {
+			return sorted[i].DNSName < sorted[j].DNSName
+		}
+		return sorted[i].RecordType < sorted[j].RecordType
+	})
+
+	if _, err := fmt.Fprintf(w, "$TTL %d\n", DefaultTTL); err != nil {
+		return err
+	}
+	for _, ep := range sorted {
+		for _, target := range ep.Targets {
+			rr, err := newRR(ep, target)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintln(w, rr.String()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// newRR returns the record of the target of ep.
+func newRR(ep *endpoint.Endpoint, target string) (dns.RR, error) {
+	ttl := uint32(DefaultTTL)
+	if ep.RecordTTL.IsConfigured() {
+		ttl = uint32(ep.RecordTTL)
+	}
+	name := dns.Fqdn(ep.DNSName)
+
+	if ep.RecordType == endpoint.RecordTypeTXT {
+		// The TXT registry stores its labels with quotes, other sources without.
+		if unquoted, err := strconv.Unquote(target); err == nil {
+			target = unquoted
+		}
+		return &dns.TXT{
+			Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: ttl},
+			Txt: splitTXT(target),
+		}, nil
+	}
+
+	rr, err := dns.NewRR(fmt.Sprintf("%s %d IN %s %s", name, ttl, ep.RecordType, target))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s record %s: %w", ep.RecordType, ep.DNSName, err)
+	}
+	if rr == nil {
+		return nil, fmt.Errorf("empty %s record %s", ep.RecordType, ep.DNSName)
+	}
+	return rr, nil
+}
+
+// splitTXT splits text into character strings of at most 255 bytes.
+func splitTXT(text string) []string {
+	strs := []string{}
+	for len(text) > maxTXTStringLength {
+		strs = append(strs, text[:maxTXTStringLength])
+		text = text[maxTXTStringLength:]
+	}
+	return append(strs, text)
+}
+
+// Read parses the zone file of r into endpoints, one for every name and type with the targets of its
+// records. Relative names are relative to origin, unless the file sets its own with $ORIGIN. The SOA
+// records and the records of other classes than IN are skipped, and a record that isn't a valid
+// endpoint, such as a name that can't be converted to ASCII, is an error.
+func Read(r io.Reader, origin string) ([]*endpoint.Endpoint, error) {
+	if origin != "" {
+		origin = dns.Fqdn(origin)
+	}
+	parser := dns.NewZoneParser(r, origin, "")
+	parser.SetIncludeAllowed(false)
+
+	var endpoints []*endpoint.Endpoint
+	index := map[endpoint.EndpointKey]*endpoint.Endpoint{}
+	for rr, ok := parser.Next(); ok; rr, ok = parser.Next() {
+		hdr := rr.Header()
+		if hdr.Class != dns.ClassINET || hdr.Rrtype == dns.TypeSOA {
+			continue
+		}
+
+		key := endpoint.EndpointKey{
+			DNSName:    strings.TrimSuffix(hdr.Name, "."),
+			RecordType: dns.TypeToString[hdr.Rrtype],
+		}
+		target := rdata(rr)
+		if ep, ok := index[key]; ok {
+			ep.Targets = append(ep.Targets, target)
+			continue
+		}
+		ep := endpoint.NewEndpointWithTTL(key.DNSName, key.RecordType, endpoint.TTL(hdr.Ttl), target)
+		if ep == nil {
+			return nil, fmt.Errorf("invalid zone file: invalid %s record %s", key.RecordType, key.DNSName)
+		}
+		index[key] = ep
+		endpoints = append(endpoints, ep)
+	}
+	if err := parser.Err(); err != nil {
+		return nil, fmt.Errorf("invalid zone file: %w", err)
+	}
+	return endpoints, nil
+}
+
+// rdata returns the target of the endpoints for the data of rr. The character strings of TXT records
+// are joined, and the trailing dot of the names is removed as in the targets of the sources, except
+// for the record types validated by the endpoints.
+func rdata(rr dns.RR) string {
+	if txt, ok := rr.(*dns.TXT); ok {
+		return strings.Join(txt.Txt, "")
+	}
+	data := endpoint.RData(rr)
+	if recordType := dns.TypeToString[rr.Header().Rrtype]; endpoint.IsValidatedRecordType(recordType) {
+		if canonical, err := endpoint.ValidateTarget(recordType, data); err == nil {
+			return canonical
+		}
+		return data
+	}
+	return strings.TrimSuffix(data, ".")
+}
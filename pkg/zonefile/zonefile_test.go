@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zonefile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestWrite(t *testing.T) {
+	endpoints := []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("www.example.com", endpoint.RecordTypeA, 60, "1.2.3.4", "5.6.7.8"),
+		endpoint.NewEndpoint("api.example.com", endpoint.RecordTypeCNAME, "lb.example.net"),
+		endpoint.NewEndpoint("api.example.com", endpoint.RecordTypeTXT, `"heritage=external-dns,external-dns/owner=default"`),
+		endpoint.NewEndpointWithTTL("example.com", endpoint.RecordTypeMX, 3600, "10 mail.example.com"),
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, endpoints))
+	assert.Equal(t, strings.Join([]string{
+		"$TTL 300",
+		"api.example.com.\t300\tIN\tCNAME\tlb.example.net.",
+		"api.example.com.\t300\tIN\tTXT\t\"heritage=external-dns,external-dns/owner=default\"",
+		"example.com.\t3600\tIN\tMX\t10 mail.example.com.",
+		"www.example.com.\t60\tIN\tA\t1.2.3.4",
+		"www.example.com.\t60\tIN\tA\t5.6.7.8",
+		"",
+	}, "\n"), buf.String())
+
+	err := Write(&buf, []*endpoint.Endpoint{endpoint.NewEndpoint("www.example.com", endpoint.RecordTypeA, "invalid")})
+	assert.ErrorContains(t, err, "invalid A record www.example.com")
+}
+
+func TestRead(t *testing.T) {
+	zone := `$TTL 600
+@       IN SOA ns1.example.com. admin.example.com. 1 7200 3600 1209600 300
+@       IN NS  ns1.example.com.
+www  60 IN A   1.2.3.4
+www  60 IN A   5.6.7.8
+api     IN CNAME lb.example.net.
+txt     IN TXT "first" "second"
+srv     IN SRV 10 5 443 api.example.com.
+other.example.org. IN A 9.9.9.9
+`
+	endpoints, err := Read(strings.NewReader(zone), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("example.com", endpoint.RecordTypeNS, 600, "ns1.example.com"),
+		endpoint.NewEndpointWithTTL("www.example.com", endpoint.RecordTypeA, 60, "1.2.3.4", "5.6.7.8"),
+		endpoint.NewEndpointWithTTL("api.example.com", endpoint.RecordTypeCNAME, 600, "lb.example.net"),
+		endpoint.NewEndpointWithTTL("txt.example.com", endpoint.RecordTypeTXT, 600, "firstsecond"),
+		endpoint.NewEndpointWithTTL("srv.example.com", endpoint.RecordTypeSRV, 600, "10 5 443 api.example.com"),
+		endpoint.NewEndpointWithTTL("other.example.org", endpoint.RecordTypeA, 600, "9.9.9.9"),
+	}, endpoints)
+
+	_, err = Read(strings.NewReader("www IN A invalid\n"), "example.com")
+	assert.ErrorContains(t, err, "invalid zone file")
+
+	// the escaped bytes make the label longer than 63 characters
+	label := strings.Repeat(`\000`, 20)
+	_, err = Read(strings.NewReader(label+" IN A 1.2.3.4\n"), "example.com")
+	assert.EqualError(t, err, "invalid zone file: invalid A record "+label+".example.com")
+}
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	endpoints := []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("a.example.com", endpoint.RecordTypeAAAA, 300, "2001:db8::1"),
+		endpoint.NewEndpointWithTTL("b.example.com", endpoint.RecordTypeTXT, 300, strings.Repeat("x", 300)),
+		endpoint.NewEndpointWithTTL("c.example.com", endpoint.RecordTypeNAPTR, 300, `100 10 "S" "SIP+D2U" "" _sip._udp.example.com`),
+		endpoint.NewEndpointWithTTL("d.example.com", endpoint.RecordTypeCAA, 300, `0 issue "letsencrypt.org"`),
+		endpoint.NewEndpointWithTTL("d.example.com", endpoint.RecordTypeHTTPS, 300, `1 . alpn="h2,h3"`),
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, endpoints))
+	read, err := Read(&buf, "")
+	require.NoError(t, err)
+	assert.Equal(t, endpoints, read)
+}
@@ -17,7 +17,10 @@ limitations under the License.
 package plan
 
 import (
+	"math"
 	"sort"
+	"strconv"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -122,4 +125,118 @@ func (s PerResource) less(x, y *endpoint.Endpoint) bool {
 	return x.Targets.IsLess(y.Targets)
 }
 
-// TODO: with cross-resource/cross-cluster setup alternative variations of ConflictResolver can be used
+// ConflictResolvers are the ConflictResolver implementations selectable by name.
+var ConflictResolvers = map[string]ConflictResolver{
+	"per-resource":    PerResource{},
+	"merge-targets":   MergeTargets{},
+	"priority":        Priority{},
+	"oldest-resource": OldestResource{},
+}
+
+// MergeTargets publishes the targets of all the resources acquiring the same DNS name in a single record.
+// CNAME records have a single target, so their conflicts are resolved as with PerResource.
+type MergeTargets struct {
+	PerResource
+}
+
+// ResolveCreate returns the "minimal" candidate with the targets of all the candidates
+func (s MergeTargets) ResolveCreate(candidates []*endpoint.Endpoint) *endpoint.Endpoint {
+	return s.merge(s.PerResource.ResolveCreate(candidates), candidates)
+}
+
+// ResolveUpdate returns the candidate of the resource owning the current record with the targets of all the candidates
+func (s MergeTargets) ResolveUpdate(current *endpoint.Endpoint, candidates []*endpoint.Endpoint) *endpoint.Endpoint {
+	return s.merge(s.PerResource.ResolveUpdate(current, candidates), candidates)
+}
+
+// merge returns a copy of base with the targets of all the candidates.
+func (s MergeTargets) merge(base *endpoint.Endpoint, candidates []*endpoint.Endpoint) *endpoint.Endpoint {
+	if len(candidates) <= 1 || base.RecordType == endpoint.RecordTypeCNAME {
+		return base
+	}
+	seen := map[string]struct{}{}
+	targets := endpoint.Targets{}
+	for _, ep := range candidates {
+		for _, target := range ep.Targets {
+			if _, ok := seen[target]; !ok {
+				seen[target] = struct{}{}
+				targets = append(targets, target)
+			}
+		}
+	}
+	sort.Sort(targets)
+	merged := base.DeepCopy()
+	merged.Targets = targets
+	return merged
+}
+
+// Priority lets the resource with the highest priority annotation acquire a DNS name, even if another resource
+// already owns it. Resources without priority have the priority 0, and ties are resolved as with PerResource.
+type Priority struct {
+	PerResource
+}
+
+// ResolveCreate returns the "minimal" candidate of the highest priority
+func (s Priority) ResolveCreate(candidates []*endpoint.Endpoint) *endpoint.Endpoint {
+	return s.PerResource.ResolveCreate(s.highest(candidates))
+}
+
+// ResolveUpdate keeps the resource owning the current record if it has the highest priority
+func (s Priority) ResolveUpdate(current *endpoint.Endpoint, candidates []*endpoint.Endpoint) *endpoint.Endpoint {
+	return s.PerResource.ResolveUpdate(current, s.highest(candidates))
+}
+
+// highest returns the candidates of the highest priority.
+func (s Priority) highest(candidates []*endpoint.Endpoint) []*endpoint.Endpoint {
+	return best(candidates, func(ep *endpoint.Endpoint) int64 {
+		priority, err := strconv.ParseInt(ep.Labels[endpoint.PriorityLabelKey], 10, 64)
+		if err != nil {
+			return 0
+		}
+		return priority
+	})
+}
+
+// OldestResource lets the oldest resource acquire a DNS name, even if another resource already owns it. Resources
+// without creation timestamp are the newest, and ties are resolved as with PerResource.
+type OldestResource struct {
+	PerResource
+}
+
+// ResolveCreate returns the "minimal" candidate of the oldest resource
+func (s OldestResource) ResolveCreate(candidates []*endpoint.Endpoint) *endpoint.Endpoint {
+	return s.PerResource.ResolveCreate(s.oldest(candidates))
+}
+
+// ResolveUpdate keeps the resource owning the current record if it is the oldest
+func (s OldestResource) ResolveUpdate(current *endpoint.Endpoint, candidates []*endpoint.Endpoint) *endpoint.Endpoint {
+	return s.PerResource.ResolveUpdate(current, s.oldest(candidates))
+}
+
+// oldest returns the candidates of the oldest resource.
+func (s OldestResource) oldest(candidates []*endpoint.Endpoint) []*endpoint.Endpoint {
+	return best(candidates, func(ep *endpoint.Endpoint) int64 {
+		created, err := time.Parse(time.RFC3339, ep.Labels[endpoint.CreatedLabelKey])
+		if err != nil {
+			return math.MinInt64
+		}
+		return -created.Unix()
+	})
+}
+
+// best returns the candidates with the highest score.
+func best(candidates []*endpoint.Endpoint, score func(*endpoint.Endpoint) int64) []*endpoint.Endpoint {
+	var result []*endpoint.Endpoint
+	var max int64
+	for _, ep := range candidates {
+		s := score(ep)
+		switch {
+		case len(result) == 0 || s > max:
+			result = []*endpoint.Endpoint{ep}
+			max = s
+		case s == max:
+			result = append(result, ep)
+		}
+	}
+	return result
+}
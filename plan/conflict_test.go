@@ -20,11 +20,17 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"sigs.k8s.io/external-dns/endpoint"
 )
 
-var _ ConflictResolver = PerResource{}
+var (
+	_ ConflictResolver = PerResource{}
+	_ ConflictResolver = MergeTargets{}
+	_ ConflictResolver = Priority{}
+	_ ConflictResolver = OldestResource{}
+)
 
 type ResolverSuite struct {
 	// resolvers
@@ -295,3 +301,119 @@ func (suite *ResolverSuite) TestPerResource_ResolveRecordTypes() {
 func TestConflictResolver(t *testing.T) {
 	suite.Run(t, new(ResolverSuite))
 }
+
+// newClaim returns an A record of the resource with the given targets and labels
+func newClaim(resource string, targets []string, labels map[string]string) *endpoint.Endpoint {
+	ep := endpoint.NewEndpoint("foo", endpoint.RecordTypeA, targets...)
+	ep.Labels[endpoint.ResourceLabelKey] = resource
+	for key, value := range labels {
+		ep.Labels[key] = value
+	}
+	return ep
+}
+
+func TestConflictResolvers(t *testing.T) {
+	plain := newClaim("ingress/default/plain", []string{"1.1.1.1"}, nil)
+	second := newClaim("ingress/default/second", []string{"2.2.2.2", "1.1.1.1"}, nil)
+	high := newClaim("ingress/default/high", []string{"3.3.3.3"}, map[string]string{endpoint.PriorityLabelKey: "10"})
+	negative := newClaim("ingress/default/negative", []string{"0.0.0.1"}, map[string]string{endpoint.PriorityLabelKey: "-1"})
+	old := newClaim("ingress/default/old", []string{"4.4.4.4"}, map[string]string{endpoint.CreatedLabelKey: "2020-01-01T00:00:00Z"})
+	young := newClaim("ingress/default/young", []string{"0.0.0.2"}, map[string]string{endpoint.CreatedLabelKey: "2024-01-01T00:00:00Z"})
+	cnameA := endpoint.NewEndpoint("foo", endpoint.RecordTypeCNAME, "a.elb.com")
+	cnameB := endpoint.NewEndpoint("foo", endpoint.RecordTypeCNAME, "b.elb.com")
+
+	for _, tc := range []struct {
+		name       string
+		resolver   ConflictResolver
+		current    *endpoint.Endpoint
+		candidates []*endpoint.Endpoint
+		resource   string
+		targets    endpoint.Targets
+	}{
+		{
+			name:       "per-resource picks the minimal targets",
+			resolver:   ConflictResolvers["per-resource"],
+			candidates: []*endpoint.Endpoint{second, plain},
+			resource:   "ingress/default/plain",
+			targets:    endpoint.Targets{"1.1.1.1"},
+		},
+		{
+			name:       "merge-targets unions the targets",
+			resolver:   ConflictResolvers["merge-targets"],
+			candidates: []*endpoint.Endpoint{second, plain, high},
+			resource:   "ingress/default/plain",
+			targets:    endpoint.Targets{"1.1.1.1", "2.2.2.2", "3.3.3.3"},
+		},
+		{
+			name:       "merge-targets keeps the resource of the current record",
+			resolver:   ConflictResolvers["merge-targets"],
+			current:    second,
+			candidates: []*endpoint.Endpoint{plain, second},
+			resource:   "ingress/default/second",
+			targets:    endpoint.Targets{"1.1.1.1", "2.2.2.2"},
+		},
+		{
+			name:       "merge-targets doesn't merge CNAME records",
+			resolver:   ConflictResolvers["merge-targets"],
+			candidates: []*endpoint.Endpoint{cnameB, cnameA},
+			targets:    endpoint.Targets{"a.elb.com"},
+		},
+		{
+			name:       "priority picks the highest priority",
+			resolver:   ConflictResolvers["priority"],
+			candidates: []*endpoint.Endpoint{plain, high, negative},
+			resource:   "ingress/default/high",
+			targets:    endpoint.Targets{"3.3.3.3"},
+		},
+		{
+			name:       "priority takes over the current record",
+			resolver:   ConflictResolvers["priority"],
+			current:    plain,
+			candidates: []*endpoint.Endpoint{plain, high},
+			resource:   "ingress/default/high",
+			targets:    endpoint.Targets{"3.3.3.3"},
+		},
+		{
+			name:       "priority defaults to 0",
+			resolver:   ConflictResolvers["priority"],
+			candidates: []*endpoint.Endpoint{negative, second, plain},
+			resource:   "ingress/default/plain",
+			targets:    endpoint.Targets{"1.1.1.1"},
+		},
+		{
+			name:       "oldest-resource picks the oldest resource",
+			resolver:   ConflictResolvers["oldest-resource"],
+			candidates: []*endpoint.Endpoint{young, plain, old},
+			resource:   "ingress/default/old",
+			targets:    endpoint.Targets{"4.4.4.4"},
+		},
+		{
+			name:       "oldest-resource takes over the current record",
+			resolver:   ConflictResolvers["oldest-resource"],
+			current:    young,
+			candidates: []*endpoint.Endpoint{young, old},
+			resource:   "ingress/default/old",
+			targets:    endpoint.Targets{"4.4.4.4"},
+		},
+		{
+			name:       "oldest-resource without timestamps",
+			resolver:   ConflictResolvers["oldest-resource"],
+			candidates: []*endpoint.Endpoint{second, plain},
+			resource:   "ingress/default/plain",
+			targets:    endpoint.Targets{"1.1.1.1"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var got *endpoint.Endpoint
+			if tc.current != nil {
+				got = tc.resolver.ResolveUpdate(tc.current, tc.candidates)
+			} else {
+				got = tc.resolver.ResolveCreate(tc.candidates)
+			}
+			assert.Equal(t, tc.resource, got.Labels[endpoint.ResourceLabelKey])
+			assert.Equal(t, tc.targets, got.Targets)
+		})
+	}
+
+	assert.Equal(t, endpoint.Targets{"2.2.2.2", "1.1.1.1"}, second.Targets, "the candidates are not modified")
+}
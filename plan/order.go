@@ -0,0 +1,283 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"container/heap"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// ChangeAction is the kind of a single change.
+type ChangeAction string
+
+const (
+	// ActionCreate creates a record.
+	ActionCreate ChangeAction = "create"
+	// ActionUpdate replaces the record Old by the record Endpoint.
+	ActionUpdate ChangeAction = "update"
+	// ActionDelete deletes a record.
+	ActionDelete ChangeAction = "delete"
+)
+
+// Change is a single action of Changes.
+type Change struct {
+	Action ChangeAction
+	// Endpoint is the created, desired or deleted record
+	Endpoint *endpoint.Endpoint
+	// Old is the current record of an update, nil otherwise
+	Old *endpoint.Endpoint
+}
+
+// Ordered returns the changes in the order providers should apply them:
+//   - a record is created or updated before the CNAMEs pointing at it,
+//   - a CNAME is deleted, or moved away, before the record it points at,
+//   - the records of a name changing from or to a CNAME are deleted right before the new record is created.
+//
+// Changes without dependencies keep their order, creates first, then updates, then deletes.
+func (c *Changes) Ordered() []Change {
+	var ordered []Change
+	for _, unit := range c.units() {
+		ordered = append(ordered, unit...)
+	}
+	return ordered
+}
+
+// Batches splits the ordered changes into consecutive batches of at most size changes, which must be
+// applied one after another. The records of a name changing from or to a CNAME are never split, so a
+// batch may exceed size by them. A size of 0 or less returns all the changes in a single batch.
+func (c *Changes) Batches(size int) []*Changes {
+	units := c.units()
+	if len(units) == 0 {
+		return nil
+	}
+
+	var batches []*Changes
+	count := 0
+	for _, unit := range units {
+		if len(batches) == 0 || (size > 0 && count > 0 && count+len(unit) > size) {
+			batches = append(batches, &Changes{})
+			count = 0
+		}
+		batches[len(batches)-1].add(unit...)
+		count += len(unit)
+	}
+	return batches
+}
+
+// sortByDependencies sorts the lists of the changes by Ordered.
+func (c *Changes) sortByDependencies() {
+	if len(c.UpdateOld) != len(c.UpdateNew) {
+		return
+	}
+	sorted := &Changes{}
+	sorted.add(c.Ordered()...)
+	*c = *sorted
+}
+
+func (c *Changes) add(changes ...Change) {
+	for _, change := range changes {
+		switch change.Action {
+		case ActionCreate:
+			c.Create = append(c.Create, change.Endpoint)
+		case ActionUpdate:
+			c.UpdateOld = append(c.UpdateOld, change.Old)
+			c.UpdateNew = append(c.UpdateNew, change.Endpoint)
+		case ActionDelete:
+			c.Delete = append(c.Delete, change.Endpoint)
+		}
+	}
+}
+
+// units groups the changes which must be applied together and sorts the groups topologically.
+func (c *Changes) units() [][]Change {
+	var changes []Change
+	for _, ep := range c.Create {
+		changes = append(changes, Change{Action: ActionCreate, Endpoint: ep})
+	}
+	for i, ep := range c.UpdateNew {
+		change := Change{Action: ActionUpdate, Endpoint: ep}
+		if i < len(c.UpdateOld) {
+			change.Old = c.UpdateOld[i]
+		}
+		changes = append(changes, change)
+	}
+	for _, ep := range c.Delete {
+		changes = append(changes, Change{Action: ActionDelete, Endpoint: ep})
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	// records of a name switching from or to a CNAME are deleted and created in the same unit
+	unitOf := make([]int, len(changes))
+	for i := range unitOf {
+		unitOf[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if unitOf[i] != i {
+			unitOf[i] = find(unitOf[i])
+		}
+		return unitOf[i]
+	}
+
+	written := map[planKey][]int{}
+	deleted := map[planKey][]int{}
+	for i, change := range changes {
+		key := planKey{dnsName: normalizeDNSName(change.Endpoint.DNSName), setIdentifier: change.Endpoint.SetIdentifier}
+		if change.Action == ActionDelete {
+			deleted[key] = append(deleted[key], i)
+		} else {
+			written[key] = append(written[key], i)
+		}
+	}
+	for key, deletes := range deleted {
+		for _, d := range deletes {
+			for _, w := range written[key] {
+				if changes[w].Action != ActionCreate || !typeSwitch(changes[d].Endpoint, changes[w].Endpoint) {
+					continue
+				}
+				a, b := find(d), find(w)
+				unitOf[max(a, b)] = min(a, b)
+			}
+		}
+	}
+
+	// every unit is named after its lowest change, which is also its position in the default order
+	members := map[int][]int{}
+	for i := range changes {
+		members[find(i)] = append(members[find(i)], i)
+	}
+
+	edges := map[int]map[int]bool{}
+	indegree := map[int]int{}
+	addEdge := func(from, to int) {
+		from, to = find(from), find(to)
+		if from == to || edges[from][to] {
+			return
+		}
+		if edges[from] == nil {
+			edges[from] = map[int]bool{}
+		}
+		edges[from][to] = true
+		indegree[to]++
+	}
+	for i, change := range changes {
+		setIdentifier := change.Endpoint.SetIdentifier
+		switch change.Action {
+		case ActionCreate, ActionUpdate:
+			for _, target := range cnameTargets(change.Endpoint) {
+				for _, w := range written[planKey{dnsName: target, setIdentifier: setIdentifier}] {
+					addEdge(w, i)
+				}
+			}
+			if change.Old != nil {
+				for _, target := range cnameTargets(change.Old) {
+					for _, d := range deleted[planKey{dnsName: target, setIdentifier: setIdentifier}] {
+						addEdge(i, d)
+					}
+				}
+			}
+		case ActionDelete:
+			for _, target := range cnameTargets(change.Endpoint) {
+				for _, d := range deleted[planKey{dnsName: target, setIdentifier: setIdentifier}] {
+					addEdge(i, d)
+				}
+			}
+		}
+	}
+
+	ready := &intHeap{}
+	for unit := range members {
+		if indegree[unit] == 0 {
+			heap.Push(ready, unit)
+		}
+	}
+	var order []int
+	for ready.Len() > 0 {
+		unit := heap.Pop(ready).(int)
+		order = append(order, unit)
+		for next := range edges[unit] {
+			indegree[next]--
+			if indegree[next] == 0 {
+				heap.Push(ready, next)
+			}
+		}
+	}
+	if len(order) < len(members) {
+		log.Debugf("Changes depend on each other in a cycle, applying %d changes in their default order", len(members)-len(order))
+		remaining := []int{}
+		for unit := range members {
+			if indegree[unit] > 0 {
+				remaining = append(remaining, unit)
+			}
+		}
+		sort.Ints(remaining)
+		order = append(order, remaining...)
+	}
+
+	units := make([][]Change, 0, len(order))
+	for _, unit := range order {
+		indexes := members[unit]
+		// deletes first, the other changes of a unit create what the deletes make room for
+		sort.SliceStable(indexes, func(i, j int) bool {
+			return changes[indexes[i]].Action == ActionDelete && changes[indexes[j]].Action != ActionDelete
+		})
+		group := make([]Change, 0, len(indexes))
+		for _, i := range indexes {
+			group = append(group, changes[i])
+		}
+		units = append(units, group)
+	}
+	return units
+}
+
+// typeSwitch returns true if the records can't exist together because one of them is a CNAME.
+func typeSwitch(old, new *endpoint.Endpoint) bool {
+	return old.RecordType != new.RecordType && (old.RecordType == endpoint.RecordTypeCNAME || new.RecordType == endpoint.RecordTypeCNAME)
+}
+
+// cnameTargets returns the normalized targets of a CNAME record.
+func cnameTargets(ep *endpoint.Endpoint) []string {
+	if ep.RecordType != endpoint.RecordTypeCNAME {
+		return nil
+	}
+	targets := make([]string, 0, len(ep.Targets))
+	for _, target := range ep.Targets {
+		targets = append(targets, normalizeDNSName(target))
+	}
+	return targets
+}
+
+// intHeap is a min-heap of ints.
+type intHeap []int
+
+func (h intHeap) Len() int           { return len(h) }
+func (h intHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h intHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *intHeap) Push(x any)        { *h = append(*h, x.(int)) }
+
+func (h *intHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func orderedNames(changes []Change) []string {
+	names := make([]string, 0, len(changes))
+	for _, change := range changes {
+		names = append(names, string(change.Action)+" "+change.Endpoint.DNSName+" "+change.Endpoint.RecordType)
+	}
+	return names
+}
+
+func TestOrdered(t *testing.T) {
+	alias := endpoint.NewEndpoint("alias.example.com", endpoint.RecordTypeCNAME, "target.example.com")
+	target := endpoint.NewEndpoint("target.example.com", endpoint.RecordTypeA, "1.2.3.4")
+	other := endpoint.NewEndpoint("other.example.com", endpoint.RecordTypeA, "1.2.3.5")
+	switchedOld := endpoint.NewEndpoint("switch.example.com", endpoint.RecordTypeCNAME, "target.example.com")
+	switchedNew := endpoint.NewEndpoint("switch.example.com", endpoint.RecordTypeA, "1.2.3.6")
+	loopA := endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeCNAME, "b.example.com")
+	loopB := endpoint.NewEndpoint("b.example.com", endpoint.RecordTypeCNAME, "a.example.com")
+
+	for _, tc := range []struct {
+		title    string
+		changes  *Changes
+		expected []string
+	}{
+		{
+			title:    "targets are created before aliases",
+			changes:  &Changes{Create: []*endpoint.Endpoint{alias, other, target}},
+			expected: []string{"create other.example.com A", "create target.example.com A", "create alias.example.com CNAME"},
+		},
+		{
+			title:    "aliases are deleted before targets",
+			changes:  &Changes{Delete: []*endpoint.Endpoint{target, alias}},
+			expected: []string{"delete alias.example.com CNAME", "delete target.example.com A"},
+		},
+		{
+			title:    "aliases are moved away before targets are deleted",
+			changes:  &Changes{UpdateOld: []*endpoint.Endpoint{alias}, UpdateNew: []*endpoint.Endpoint{endpoint.NewEndpoint("alias.example.com", endpoint.RecordTypeCNAME, "other.example.com")}, Delete: []*endpoint.Endpoint{target}},
+			expected: []string{"update alias.example.com CNAME", "delete target.example.com A"},
+		},
+		{
+			title:    "type switches delete before creating",
+			changes:  &Changes{Create: []*endpoint.Endpoint{other, switchedNew}, Delete: []*endpoint.Endpoint{switchedOld}},
+			expected: []string{"create other.example.com A", "delete switch.example.com CNAME", "create switch.example.com A"},
+		},
+		{
+			title:    "cycles keep the default order",
+			changes:  &Changes{Create: []*endpoint.Endpoint{loopA, loopB, other}},
+			expected: []string{"create other.example.com A", "create a.example.com CNAME", "create b.example.com CNAME"},
+		},
+		{
+			title:   "no changes",
+			changes: &Changes{},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			if tc.expected == nil {
+				assert.Empty(t, tc.changes.Ordered())
+				return
+			}
+			assert.Equal(t, tc.expected, orderedNames(tc.changes.Ordered()))
+		})
+	}
+}
+
+func TestBatches(t *testing.T) {
+	a := endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "1.2.3.4")
+	b := endpoint.NewEndpoint("b.example.com", endpoint.RecordTypeA, "1.2.3.4")
+	c := endpoint.NewEndpoint("c.example.com", endpoint.RecordTypeCNAME, "a.example.com")
+	bAlias := endpoint.NewEndpoint("b.example.com", endpoint.RecordTypeCNAME, "a.example.com")
+	changes := &Changes{Create: []*endpoint.Endpoint{c, a, bAlias}, Delete: []*endpoint.Endpoint{b}}
+
+	assert.Len(t, changes.Batches(0), 1)
+	assert.Empty(t, (&Changes{}).Batches(2))
+
+	batches := changes.Batches(1)
+	assert.Len(t, batches, 3)
+	assert.Equal(t, []*endpoint.Endpoint{a}, batches[0].Create)
+	assert.Equal(t, []*endpoint.Endpoint{c}, batches[1].Create)
+	// the type switch of b.example.com is not split
+	assert.Equal(t, []*endpoint.Endpoint{b}, batches[2].Delete)
+	assert.Equal(t, []*endpoint.Endpoint{bAlias}, batches[2].Create)
+}
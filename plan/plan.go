@@ -18,6 +18,7 @@ package plan
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/google/go-cmp/cmp"
@@ -26,7 +27,8 @@ import (
 	"sigs.k8s.io/external-dns/endpoint"
 )
 
-// PropertyComparator is used in Plan for comparing the previous and current custom annotations.
+// PropertyComparator is used in Plan for comparing the previous and current values of a provider specific property,
+// it returns true if they are equivalent.
 type PropertyComparator func(name string, previous string, current string) bool
 
 // Plan can convert a list of desired and current records to a series of create,
@@ -49,6 +51,14 @@ type Plan struct {
 	ExcludeRecords []string
 	// OwnerID of records to manage
 	OwnerID string
+	// Resolver decides which resources acquire the DNS names they claim together, PerResource if nil
+	Resolver ConflictResolver
+	// PropertyComparisons define how provider specific properties are compared by their name, others are compared as strings
+	PropertyComparisons map[string]PropertyComparison
+	// IgnoredProperties are the names of provider specific properties never causing an update
+	IgnoredProperties []string
+	// OrphanedRecords are ownership records of the registry whose records are gone, deleted under the policies
+	OrphanedRecords []*endpoint.Endpoint
 }
 
 // Changes holds lists of actions to be executed by dns providers
@@ -92,8 +102,11 @@ type planTable struct {
 	resolver ConflictResolver
 }
 
-func newPlanTable() planTable { // TODO: make resolver configurable
-	return planTable{map[planKey]*planTableRow{}, PerResource{}}
+func newPlanTable(resolver ConflictResolver) planTable {
+	if resolver == nil {
+		resolver = PerResource{}
+	}
+	return planTable{map[planKey]*planTableRow{}, resolver}
 }
 
 // planTableRow represents a set of current and desired domain resource records.
@@ -165,7 +178,7 @@ func (c *Changes) HasChanges() bool {
 // state. It then passes those changes to the current policy for further
 // processing. It returns a copy of Plan with the changes populated.
 func (p *Plan) Calculate() *Plan {
-	t := newPlanTable()
+	t := newPlanTable(p.Resolver)
 
 	if p.DomainFilter == nil {
 		p.DomainFilter = endpoint.MatchAllDomainFilters(nil)
@@ -221,7 +234,7 @@ func (p *Plan) Calculate() *Plan {
 				if records.current != nil && len(records.candidates) > 0 {
 					update := t.resolver.ResolveUpdate(records.current, records.candidates)
 
-					if shouldUpdateTTL(update, records.current) || targetChanged(update, records.current) || p.shouldUpdateProviderSpecific(update, records.current) {
+					if shouldUpdateTTL(update, records.current) || targetChanged(update, records.current) || p.shouldUpdateProviderSpecific(update, records.current) || p.policyChanged(update, records.current) {
 						inheritOwner(records.current, update)
 						changes.UpdateNew = append(changes.UpdateNew, update)
 						changes.UpdateOld = append(changes.UpdateOld, records.current)
@@ -249,9 +262,16 @@ func (p *Plan) Calculate() *Plan {
 		}
 	}
 
+	for _, orphan := range p.OrphanedRecords {
+		if p.DomainFilter.Match(orphan.DNSName) {
+			changes.Delete = append(changes.Delete, orphan)
+		}
+	}
+
 	for _, pol := range p.Policies {
 		changes = pol.Apply(changes)
 	}
+	changes = (&RecordPolicy{LabelsStored: p.OwnerID != ""}).Apply(changes)
 
 	// filter out updates this external dns does not have ownership claim over
 	if p.OwnerID != "" {
@@ -261,6 +281,8 @@ func (p *Plan) Calculate() *Plan {
 		changes.UpdateNew = endpoint.FilterEndpointsByOwnerID(p.OwnerID, changes.UpdateNew)
 	}
 
+	changes.sortByDependencies()
+
 	plan := &Plan{
 		Current:        p.Current,
 		Desired:        p.Desired,
@@ -282,7 +304,12 @@ func inheritOwner(from, to *endpoint.Endpoint) {
 }
 
 func targetChanged(desired, current *endpoint.Endpoint) bool {
-	return !desired.Targets.Same(current.Targets)
+	return !desired.Targets.SameAs(desired.RecordType, current.Targets)
+}
+
+// policyChanged returns whether the policy label of the record changed, only when the registry stores the labels.
+func (p *Plan) policyChanged(desired, current *endpoint.Endpoint) bool {
+	return p.OwnerID != "" && desired.Labels[endpoint.PolicyLabelKey] != current.Labels[endpoint.PolicyLabelKey]
 }
 
 func shouldUpdateTTL(desired, current *endpoint.Endpoint) bool {
@@ -293,23 +320,38 @@ func shouldUpdateTTL(desired, current *endpoint.Endpoint) bool {
 }
 
 func (p *Plan) shouldUpdateProviderSpecific(desired, current *endpoint.Endpoint) bool {
-	desiredProperties := map[string]endpoint.ProviderSpecificProperty{}
-
+	desiredProperties := map[string]string{}
 	for _, d := range desired.ProviderSpecific {
-		desiredProperties[d.Name] = d
+		desiredProperties[d.Name] = d.Value
 	}
+	currentProperties := map[string]string{}
 	for _, c := range current.ProviderSpecific {
-		if d, ok := desiredProperties[c.Name]; ok {
-			if c.Value != d.Value {
-				return true
-			}
-			delete(desiredProperties, c.Name)
-		} else {
+		currentProperties[c.Name] = c.Value
+	}
+
+	changed := func(name string) bool {
+		if slices.Contains(p.IgnoredProperties, name) {
+			return false
+		}
+		c, currentSet := currentProperties[name]
+		d, desiredSet := desiredProperties[name]
+		if p.PropertyComparisons[name].equal(name, c, currentSet, d, desiredSet) {
+			return false
+		}
+		log.Debugf("Provider specific property %s of %s %s changed from %q to %q", name, desired.DNSName, desired.RecordType, c, d)
+		return true
+	}
+	for name := range currentProperties {
+		if changed(name) {
 			return true
 		}
 	}
-
-	return len(desiredProperties) > 0
+	for name := range desiredProperties {
+		if _, ok := currentProperties[name]; !ok && changed(name) {
+			return true
+		}
+	}
+	return false
 }
 
 // filterRecordsForPlan removes records that are not relevant to the planner.
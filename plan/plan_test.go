@@ -1030,6 +1030,8 @@ func TestShouldUpdateProviderSpecific(tt *testing.T) {
 		name         string
 		current      *endpoint.Endpoint
 		desired      *endpoint.Endpoint
+		comparisons  map[string]PropertyComparison
+		ignored      []string
 		shouldUpdate bool
 	}{
 		{
@@ -1090,15 +1092,224 @@ func TestShouldUpdateProviderSpecific(tt *testing.T) {
 			},
 			shouldUpdate: true,
 		},
+		{
+			name: "property equivalent by comparator",
+			current: &endpoint.Endpoint{
+				ProviderSpecific: []endpoint.ProviderSpecificProperty{
+					{Name: "custom/proxied", Value: "True"},
+					{Name: "custom/weight", Value: "10.0"},
+				},
+			},
+			desired: &endpoint.Endpoint{
+				ProviderSpecific: []endpoint.ProviderSpecificProperty{
+					{Name: "custom/proxied", Value: "true"},
+					{Name: "custom/weight", Value: "10"},
+				},
+			},
+			comparisons: map[string]PropertyComparison{
+				"custom/proxied": {Equal: EqualBool},
+				"custom/weight":  {Equal: EqualNumber},
+			},
+			shouldUpdate: false,
+		},
+		{
+			name: "property changed by comparator",
+			current: &endpoint.Endpoint{
+				ProviderSpecific: []endpoint.ProviderSpecificProperty{
+					{Name: "custom/proxied", Value: "true"},
+				},
+			},
+			desired: &endpoint.Endpoint{
+				ProviderSpecific: []endpoint.ProviderSpecificProperty{
+					{Name: "custom/proxied", Value: "0"},
+				},
+			},
+			comparisons: map[string]PropertyComparison{
+				"custom/proxied": {Equal: EqualBool},
+			},
+			shouldUpdate: true,
+		},
+		{
+			name: "missing property equal to default",
+			current: &endpoint.Endpoint{
+				ProviderSpecific: []endpoint.ProviderSpecificProperty{
+					{Name: "custom/proxied", Value: "false"},
+				},
+			},
+			desired: &endpoint.Endpoint{},
+			comparisons: map[string]PropertyComparison{
+				"custom/proxied": {Equal: EqualBool, Default: "false"},
+			},
+			shouldUpdate: false,
+		},
+		{
+			name:    "missing property different from default",
+			current: &endpoint.Endpoint{},
+			desired: &endpoint.Endpoint{
+				ProviderSpecific: []endpoint.ProviderSpecificProperty{
+					{Name: "custom/proxied", Value: "true"},
+				},
+			},
+			comparisons: map[string]PropertyComparison{
+				"custom/proxied": {Equal: EqualBool, Default: "false"},
+			},
+			shouldUpdate: true,
+		},
+		{
+			name: "ignored property",
+			current: &endpoint.Endpoint{
+				ProviderSpecific: []endpoint.ProviderSpecificProperty{
+					{Name: "custom/property", Value: "true"},
+				},
+			},
+			desired: &endpoint.Endpoint{
+				ProviderSpecific: []endpoint.ProviderSpecificProperty{
+					{Name: "custom/property", Value: "false"},
+					{Name: "new/property", Value: "true"},
+				},
+			},
+			ignored:      []string{"custom/property", "new/property"},
+			shouldUpdate: false,
+		},
 	} {
 		tt.Run(test.name, func(t *testing.T) {
 			plan := &Plan{
-				Current:        []*endpoint.Endpoint{test.current},
-				Desired:        []*endpoint.Endpoint{test.desired},
-				ManagedRecords: []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME},
+				Current:             []*endpoint.Endpoint{test.current},
+				Desired:             []*endpoint.Endpoint{test.desired},
+				ManagedRecords:      []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME},
+				PropertyComparisons: test.comparisons,
+				IgnoredProperties:   test.ignored,
 			}
 			b := plan.shouldUpdateProviderSpecific(test.desired, test.current)
 			assert.Equal(t, test.shouldUpdate, b)
 		})
 	}
 }
+
+func TestPlanConflictResolver(t *testing.T) {
+	first := endpoint.NewEndpoint("foo.com", endpoint.RecordTypeA, "1.1.1.1")
+	first.Labels[endpoint.ResourceLabelKey] = "service/default/first"
+	second := endpoint.NewEndpoint("foo.com", endpoint.RecordTypeA, "2.2.2.2")
+	second.Labels[endpoint.ResourceLabelKey] = "service/default/second"
+
+	p := &Plan{
+		Policies:       []Policy{&SyncPolicy{}},
+		Desired:        []*endpoint.Endpoint{second, first},
+		ManagedRecords: []string{endpoint.RecordTypeA},
+	}
+	changes := p.Calculate().Changes
+	assert.Len(t, changes.Create, 1)
+	assert.Equal(t, endpoint.Targets{"1.1.1.1"}, changes.Create[0].Targets)
+
+	p.Resolver = MergeTargets{}
+	changes = p.Calculate().Changes
+	assert.Len(t, changes.Create, 1)
+	assert.Equal(t, endpoint.Targets{"1.1.1.1", "2.2.2.2"}, changes.Create[0].Targets)
+}
+
+func TestPlanRecordPolicy(t *testing.T) {
+	current := endpoint.NewEndpoint("foo.com", endpoint.RecordTypeA, "1.1.1.1")
+	current.Labels[endpoint.OwnerLabelKey] = "owner"
+	protected := endpoint.NewEndpoint("bar.com", endpoint.RecordTypeA, "2.2.2.2")
+	protected.Labels[endpoint.OwnerLabelKey] = "owner"
+	protected.Labels[endpoint.PolicyLabelKey] = "upsert-only"
+	desired := endpoint.NewEndpoint("foo.com", endpoint.RecordTypeA, "1.1.1.1")
+	desired.Labels[endpoint.PolicyLabelKey] = "upsert-only"
+
+	p := &Plan{
+		Policies:       []Policy{&SyncPolicy{}},
+		Current:        []*endpoint.Endpoint{current, protected},
+		Desired:        []*endpoint.Endpoint{desired},
+		ManagedRecords: []string{endpoint.RecordTypeA},
+		OwnerID:        "owner",
+	}
+	changes := p.Calculate().Changes
+	assert.Empty(t, changes.Delete, "the record of the deleted upsert-only resource is kept")
+	assert.Len(t, changes.UpdateNew, 1, "the policy of the record is updated")
+	assert.Equal(t, "upsert-only", changes.UpdateNew[0].Labels[endpoint.PolicyLabelKey])
+
+	p.OwnerID = ""
+	changes = p.Calculate().Changes
+	assert.Empty(t, changes.UpdateNew, "the policy isn't updated without registry labels")
+}
+
+func TestPlanOrphanedRecords(t *testing.T) {
+	orphan := endpoint.NewEndpoint("txt.gone.foo.com", endpoint.RecordTypeTXT, "\"heritage=external-dns,external-dns/owner=owner\"")
+	orphan.Labels[endpoint.OwnerLabelKey] = "owner"
+	otherDomain := endpoint.NewEndpoint("txt.gone.bar.com", endpoint.RecordTypeTXT, "\"heritage=external-dns,external-dns/owner=owner\"")
+	otherDomain.Labels[endpoint.OwnerLabelKey] = "owner"
+
+	for _, tc := range []struct {
+		title           string
+		policy          Policy
+		expectedDeletes []*endpoint.Endpoint
+	}{
+		{
+			title:           "orphaned records are deleted under the sync policy",
+			policy:          &SyncPolicy{},
+			expectedDeletes: []*endpoint.Endpoint{orphan},
+		},
+		{
+			title:  "orphaned records are kept under the upsert-only policy",
+			policy: &UpsertOnlyPolicy{},
+		},
+		{
+			title:  "orphaned records are kept under the create-only policy",
+			policy: &CreateOnlyPolicy{},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			p := &Plan{
+				Policies:        []Policy{tc.policy},
+				DomainFilter:    endpoint.MatchAllDomainFilters{endpoint.NewDomainFilter([]string{"foo.com"})},
+				OwnerID:         "owner",
+				OrphanedRecords: []*endpoint.Endpoint{orphan, otherDomain},
+			}
+			assert.ElementsMatch(t, tc.expectedDeletes, p.Calculate().Changes.Delete)
+		})
+	}
+}
+
+func TestPlanComparesTargetsByRecordType(t *testing.T) {
+	currentMX := endpoint.NewEndpoint("foo.com", endpoint.RecordTypeMX, "10  mail.foo.com.")
+	currentMX.Labels[endpoint.OwnerLabelKey] = "owner"
+	currentTXT := endpoint.NewEndpoint("foo.com", endpoint.RecordTypeTXT, "10  mail.foo.com")
+	currentTXT.Labels[endpoint.OwnerLabelKey] = "owner"
+
+	p := &Plan{
+		Policies: []Policy{&SyncPolicy{}},
+		Current:  []*endpoint.Endpoint{currentMX, currentTXT},
+		Desired: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("foo.com", endpoint.RecordTypeMX, "10 mail.foo.com"),
+			endpoint.NewEndpoint("foo.com", endpoint.RecordTypeTXT, "10 mail.foo.com"),
+		},
+		ManagedRecords: []string{endpoint.RecordTypeMX, endpoint.RecordTypeTXT},
+		OwnerID:        "owner",
+	}
+	changes := p.Calculate().Changes
+	// the MX targets are compared in their canonical form, the TXT targets as they are
+	if assert.Len(t, changes.UpdateNew, 1) {
+		assert.Equal(t, endpoint.RecordTypeTXT, changes.UpdateNew[0].RecordType)
+	}
+}
+
+func TestPlanOrdersChanges(t *testing.T) {
+	alias := endpoint.NewEndpoint("alias.example.com", endpoint.RecordTypeCNAME, "target.example.com")
+	target := endpoint.NewEndpoint("target.example.com", endpoint.RecordTypeA, "1.2.3.4")
+
+	for i := 0; i < 10; i++ {
+		p := &Plan{
+			Policies:       []Policy{&SyncPolicy{}},
+			Desired:        []*endpoint.Endpoint{alias, target},
+			ManagedRecords: []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME},
+		}
+		assert.Equal(t, []*endpoint.Endpoint{target, alias}, p.Calculate().Changes.Create)
+
+		p = &Plan{
+			Policies:       []Policy{&SyncPolicy{}},
+			Current:        []*endpoint.Endpoint{target, alias},
+			ManagedRecords: []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME},
+		}
+		assert.Equal(t, []*endpoint.Endpoint{alias, target}, p.Calculate().Changes.Delete)
+	}
+}
@@ -16,6 +16,12 @@ limitations under the License.
 
 package plan
 
+import (
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
 // Policy allows to apply different rules to a set of changes.
 type Policy interface {
 	Apply(changes *Changes) *Changes
@@ -57,3 +63,63 @@ func (p *CreateOnlyPolicy) Apply(changes *Changes) *Changes {
 		Create: changes.Create,
 	}
 }
+
+// policyStrictness orders the policies from the most permissive to the strictest.
+var policyStrictness = map[string]int{
+	"sync":        0,
+	"upsert-only": 1,
+	"create-only": 2,
+}
+
+// IsValidPolicy returns whether name is the name of one of the Policies.
+func IsValidPolicy(name string) bool {
+	_, ok := policyStrictness[name]
+	return ok
+}
+
+// RecordPolicy applies the policies of the records, set by the policy label of their resource, on top of the
+// policy of the plan. The deletion of a record is checked against its current policy, so that the records of a
+// deleted resource stay protected, and the update against the strictest of its current and desired policies.
+// An update changing the policy label is always applied when the registry stores the labels, but only changes the
+// label under create-only.
+type RecordPolicy struct {
+	// LabelsStored is set when the registry stores the labels of the records.
+	LabelsStored bool
+}
+
+// Apply strips out the changes forbidden by the policies of the records.
+func (p *RecordPolicy) Apply(changes *Changes) *Changes {
+	filtered := &Changes{Create: changes.Create}
+	for _, ep := range changes.Delete {
+		if policy := recordPolicy(ep); policyStrictness[policy] >= policyStrictness["upsert-only"] {
+			log.Infof("Skipping the deletion of %s %s because of its %s policy", ep.DNSName, ep.RecordType, policy)
+			continue
+		}
+		filtered.Delete = append(filtered.Delete, ep)
+	}
+	for i, desired := range changes.UpdateNew {
+		current := changes.UpdateOld[i]
+		currentPolicy, desiredPolicy := recordPolicy(current), recordPolicy(desired)
+		if max(policyStrictness[currentPolicy], policyStrictness[desiredPolicy]) >= policyStrictness["create-only"] {
+			if currentPolicy == desiredPolicy || !p.LabelsStored {
+				log.Infof("Skipping the update of %s %s because of its %s policy", desired.DNSName, desired.RecordType, currentPolicy)
+				continue
+			}
+			// keep the record, only update its policy
+			labelOnly := current.DeepCopy()
+			labelOnly.Labels = desired.Labels
+			desired = labelOnly
+		}
+		filtered.UpdateOld = append(filtered.UpdateOld, current)
+		filtered.UpdateNew = append(filtered.UpdateNew, desired)
+	}
+	return filtered
+}
+
+// recordPolicy returns the policy of the label of ep, sync if it has none.
+func recordPolicy(ep *endpoint.Endpoint) string {
+	if policy := ep.Labels[endpoint.PolicyLabelKey]; IsValidPolicy(policy) {
+		return policy
+	}
+	return "sync"
+}
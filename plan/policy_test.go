@@ -20,6 +20,8 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"sigs.k8s.io/external-dns/endpoint"
 )
 
@@ -70,6 +72,42 @@ func TestApply(t *testing.T) {
 	}
 }
 
+// TestRecordPolicy tests that the policies of the records strip out the changes they forbid.
+func TestRecordPolicy(t *testing.T) {
+	withPolicy := func(name, target, policy string) *endpoint.Endpoint {
+		ep := &endpoint.Endpoint{DNSName: name, Targets: endpoint.Targets{target}, Labels: endpoint.Labels{}}
+		if policy != "" {
+			ep.Labels[endpoint.PolicyLabelKey] = policy
+		}
+		return ep
+	}
+	syncV1, syncV2 := withPolicy("sync", "v1", ""), withPolicy("sync", "v2", "")
+	upsertV1, upsertV2 := withPolicy("upsert", "v1", "upsert-only"), withPolicy("upsert", "v2", "upsert-only")
+	createV1, createV2 := withPolicy("create", "v1", "create-only"), withPolicy("create", "v2", "create-only")
+	newCreateV2 := withPolicy("new-create", "v2", "create-only")
+	newCreateV1 := withPolicy("new-create", "v1", "")
+	invalid := withPolicy("invalid", "v1", "never")
+
+	input := &Changes{
+		Create:    []*endpoint.Endpoint{createV1},
+		UpdateOld: []*endpoint.Endpoint{syncV1, upsertV1, createV1, newCreateV1},
+		UpdateNew: []*endpoint.Endpoint{syncV2, upsertV2, createV2, newCreateV2},
+		Delete:    []*endpoint.Endpoint{syncV1, upsertV1, createV1, invalid},
+	}
+	changes := (&RecordPolicy{LabelsStored: true}).Apply(input)
+	assert.Equal(t, []*endpoint.Endpoint{createV1}, changes.Create)
+	assert.Equal(t, []*endpoint.Endpoint{syncV1, upsertV1, newCreateV1}, changes.UpdateOld)
+	assert.Equal(t, []*endpoint.Endpoint{syncV1, invalid}, changes.Delete)
+	validateEntries(t, changes.UpdateNew[:2], []*endpoint.Endpoint{syncV2, upsertV2})
+	// a resource switching to create-only only updates the policy of its record
+	assert.Equal(t, endpoint.Targets{"v1"}, changes.UpdateNew[2].Targets)
+	assert.Equal(t, "create-only", changes.UpdateNew[2].Labels[endpoint.PolicyLabelKey])
+
+	// without stored labels, the update of the policy would be repeated on every synchronization
+	changes = (&RecordPolicy{}).Apply(input)
+	assert.Equal(t, []*endpoint.Endpoint{syncV1, upsertV1}, changes.UpdateOld)
+}
+
 // TestPolicies tests that policies are correctly registered.
 func TestPolicies(t *testing.T) {
 	validatePolicy(t, Policies["sync"], &SyncPolicy{})
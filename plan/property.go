@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PropertyComparison defines how the plan compares the values of a provider specific property.
+type PropertyComparison struct {
+	// Equal returns whether the values are equivalent, they are compared as strings if nil
+	Equal PropertyComparator
+	// Default is the value the provider assumes for records without the property, none if empty
+	Default string
+}
+
+// EqualBool compares the values as booleans, or as strings if they aren't booleans.
+func EqualBool(_ string, previous string, current string) bool {
+	p, perr := strconv.ParseBool(previous)
+	c, cerr := strconv.ParseBool(current)
+	if perr != nil || cerr != nil {
+		return previous == current
+	}
+	return p == c
+}
+
+// EqualNumber compares the values as numbers, or as strings if they aren't numbers.
+func EqualNumber(_ string, previous string, current string) bool {
+	p, perr := strconv.ParseFloat(strings.TrimSpace(previous), 64)
+	c, cerr := strconv.ParseFloat(strings.TrimSpace(current), 64)
+	if perr != nil || cerr != nil {
+		return previous == current
+	}
+	return p == c
+}
+
+// EqualFold compares the values as strings regardless of their case.
+func EqualFold(_ string, previous string, current string) bool {
+	return strings.EqualFold(previous, current)
+}
+
+// equal compares the values of the property name, using the value Default for a missing value.
+func (c PropertyComparison) equal(name string, previous string, previousSet bool, current string, currentSet bool) bool {
+	if !previousSet || !currentSet {
+		if c.Default == "" {
+			return false
+		}
+		if !previousSet {
+			previous = c.Default
+		}
+		if !currentSet {
+			current = c.Default
+		}
+	}
+	if c.Equal == nil {
+		return previous == current
+	}
+	return c.Equal(name, previous, current)
+}
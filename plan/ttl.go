@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// TTLPolicy normalizes the TTLs of the desired endpoints before planning, so that the plan compares
+// the TTLs the records should have with the current ones. A zero TTL of the policy doesn't apply.
+type TTLPolicy struct {
+	// Default is the TTL of the endpoints without one
+	Default endpoint.TTL
+	// Min and Max clamp the TTLs of the endpoints
+	Min endpoint.TTL
+	Max endpoint.TTL
+	// RecordTypeDefaults are the TTLs of the endpoints without one by record type, preferred to Default
+	RecordTypeDefaults map[string]endpoint.TTL
+	// Domains override the policy for the names below their domain, the most specific domain applies
+	Domains []DomainTTLPolicy
+}
+
+// DomainTTLPolicy overrides the TTL policy for a domain, e.g. a zone, and its subdomains.
+type DomainTTLPolicy struct {
+	Domain string
+	// Default is the TTL of the endpoints without one, preferred to the record type defaults
+	Default endpoint.TTL
+	// Min and Max replace the clamps of the policy
+	Min endpoint.TTL
+	Max endpoint.TTL
+}
+
+// NewTTLPolicy creates a TTLPolicy from the configuration. recordTypes maps record types to their
+// default TTL, e.g. "NS": "1h", domains maps domains to a comma separated list of their default,
+// min and max TTL, e.g. "example.org": "default=5m,min=1m,max=1h", or to their default TTL only.
+func NewTTLPolicy(defaultTTL, minTTL, maxTTL time.Duration, recordTypes, domains map[string]string) (*TTLPolicy, error) {
+	if defaultTTL < 0 || minTTL < 0 || maxTTL < 0 {
+		return nil, fmt.Errorf("the default, minimum and maximum TTL must not be negative")
+	}
+	policy := &TTLPolicy{
+		Default:            durationTTL(defaultTTL),
+		Min:                durationTTL(minTTL),
+		Max:                durationTTL(maxTTL),
+		RecordTypeDefaults: map[string]endpoint.TTL{},
+	}
+	if policy.Min > 0 && policy.Max > 0 && policy.Min > policy.Max {
+		return nil, fmt.Errorf("the minimum TTL %d is greater than the maximum TTL %d", policy.Min, policy.Max)
+	}
+
+	for recordType, value := range recordTypes {
+		ttl, err := parseTTL(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TTL of the record type %s: %w", recordType, err)
+		}
+		policy.RecordTypeDefaults[strings.ToUpper(recordType)] = ttl
+	}
+
+	for domain, value := range domains {
+		domainPolicy, err := parseDomainTTLPolicy(domain, value)
+		if err != nil {
+			return nil, err
+		}
+		policy.Domains = append(policy.Domains, domainPolicy)
+	}
+	// the most specific domain comes first
+	sort.Slice(policy.Domains, func(i, j int) bool {
+		if len(policy.Domains[i].Domain) != len(policy.Domains[j].Domain) {
+			return len(policy.Domains[i].Domain) > len(policy.Domains[j].Domain)
+		}
+		return policy.Domains[i].Domain < policy.Domains[j].Domain
+	})
+	return policy, nil
+}
+
+func parseDomainTTLPolicy(domain, value string) (DomainTTLPolicy, error) {
+	name, err := endpoint.ToASCII(strings.ToLower(strings.Trim(strings.TrimSpace(domain), ".")))
+	if err != nil || name == "" {
+		return DomainTTLPolicy{}, fmt.Errorf("invalid domain %q of a TTL policy", domain)
+	}
+	policy := DomainTTLPolicy{Domain: name}
+	for _, part := range strings.Split(value, ",") {
+		key, ttlValue, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			key, ttlValue = "default", key
+		}
+		ttl, err := parseTTL(ttlValue)
+		if err != nil {
+			return DomainTTLPolicy{}, fmt.Errorf("invalid TTL policy of the domain %s: %w", domain, err)
+		}
+		switch key {
+		case "default":
+			policy.Default = ttl
+		case "min":
+			policy.Min = ttl
+		case "max":
+			policy.Max = ttl
+		default:
+			return DomainTTLPolicy{}, fmt.Errorf("invalid TTL policy of the domain %s: unknown setting %q, expected default, min or max", domain, key)
+		}
+	}
+	if policy.Min > 0 && policy.Max > 0 && policy.Min > policy.Max {
+		return DomainTTLPolicy{}, fmt.Errorf("invalid TTL policy of the domain %s: the minimum TTL %d is greater than the maximum TTL %d", domain, policy.Min, policy.Max)
+	}
+	return policy, nil
+}
+
+// parseTTL parses a TTL in seconds or in duration format, like the TTL annotation.
+func parseTTL(value string) (endpoint.TTL, error) {
+	value = strings.TrimSpace(value)
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		if duration, err = time.ParseDuration(value + "s"); err != nil {
+			return 0, fmt.Errorf("%q is neither a number of seconds nor a duration", value)
+		}
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("%q is not positive", value)
+	}
+	return durationTTL(duration), nil
+}
+
+func durationTTL(d time.Duration) endpoint.TTL {
+	return endpoint.TTL(d / time.Second)
+}
+
+// Apply returns the endpoints with the TTLs of the policy. The endpoints whose TTL changes are copied,
+// the endpoints of the sources are left untouched.
+func (p *TTLPolicy) Apply(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	if p == nil {
+		return endpoints
+	}
+	result := make([]*endpoint.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		if ttl := p.ttl(ep); ttl != ep.RecordTTL {
+			log.Debugf("Setting the TTL of %s from %d to %d following the TTL policy", ep, ep.RecordTTL, ttl)
+			ep = ep.DeepCopy()
+			ep.RecordTTL = ttl
+		}
+		result = append(result, ep)
+	}
+	return result
+}
+
+// ttl returns the TTL of the endpoint following the policy: the configured TTL, or else the default
+// of its domain, of its record type or the global default, clamped by the minimum and maximum TTL of
+// its domain or the global ones.
+func (p *TTLPolicy) ttl(ep *endpoint.Endpoint) endpoint.TTL {
+	domain := p.domain(ep.DNSName)
+	ttl := ep.RecordTTL
+	if !ttl.IsConfigured() {
+		switch {
+		case domain != nil && domain.Default > 0:
+			ttl = domain.Default
+		case p.RecordTypeDefaults[ep.RecordType] > 0:
+			ttl = p.RecordTypeDefaults[ep.RecordType]
+		default:
+			ttl = p.Default
+		}
+	}
+	if !ttl.IsConfigured() {
+		return ttl
+	}
+
+	minTTL, maxTTL := p.Min, p.Max
+	if domain != nil && domain.Min > 0 {
+		minTTL = domain.Min
+	}
+	if domain != nil && domain.Max > 0 {
+		maxTTL = domain.Max
+	}
+	if minTTL > 0 && ttl < minTTL {
+		ttl = minTTL
+	}
+	if maxTTL > 0 && ttl > maxTTL {
+		ttl = maxTTL
+	}
+	return ttl
+}
+
+// domain returns the policy of the most specific domain of name, nil if there is none.
+func (p *TTLPolicy) domain(name string) *DomainTTLPolicy {
+	name = strings.TrimSuffix(normalizeDNSName(name), ".")
+	for i := range p.Domains {
+		domain := p.Domains[i].Domain
+		if name == domain || strings.HasSuffix(name, "."+domain) {
+			return &p.Domains[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestNewTTLPolicy(t *testing.T) {
+	policy, err := NewTTLPolicy(5*time.Minute, time.Minute, time.Hour,
+		map[string]string{"ns": "1h", "MX": "600"},
+		map[string]string{"example.org.": "10m", "Internal.Example.org": "default=30s,min=10s,max=2m"},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, endpoint.TTL(300), policy.Default)
+	assert.Equal(t, endpoint.TTL(60), policy.Min)
+	assert.Equal(t, endpoint.TTL(3600), policy.Max)
+	assert.Equal(t, map[string]endpoint.TTL{"NS": 3600, "MX": 600}, policy.RecordTypeDefaults)
+	assert.Equal(t, []DomainTTLPolicy{
+		{Domain: "internal.example.org", Default: 30, Min: 10, Max: 120},
+		{Domain: "example.org", Default: 600},
+	}, policy.Domains)
+
+	for _, tc := range []struct {
+		title       string
+		min, max    time.Duration
+		recordTypes map[string]string
+		domains     map[string]string
+	}{
+		{title: "min greater than max", min: time.Hour, max: time.Minute},
+		{title: "negative min", min: -time.Minute},
+		{title: "invalid record type TTL", recordTypes: map[string]string{"NS": "soon"}},
+		{title: "zero record type TTL", recordTypes: map[string]string{"NS": "0"}},
+		{title: "unknown domain setting", domains: map[string]string{"example.org": "ttl=5m"}},
+		{title: "domain min greater than max", domains: map[string]string{"example.org": "min=1h,max=1m"}},
+		{title: "empty domain", domains: map[string]string{".": "5m"}},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			_, err := NewTTLPolicy(0, tc.min, tc.max, tc.recordTypes, tc.domains)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTTLPolicyApply(t *testing.T) {
+	policy, err := NewTTLPolicy(5*time.Minute, time.Minute, time.Hour,
+		map[string]string{"NS": "2h"},
+		map[string]string{"example.org": "default=10m,max=2h", "internal.example.org": "min=10s"},
+	)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		title    string
+		endpoint *endpoint.Endpoint
+		expected endpoint.TTL
+	}{
+		{
+			title:    "the global default applies to endpoints without a TTL",
+			endpoint: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+			expected: 300,
+		},
+		{
+			title:    "the record type default is preferred to the global default",
+			endpoint: endpoint.NewEndpoint("example.com", endpoint.RecordTypeNS, "ns1.example.com"),
+			expected: 3600,
+		},
+		{
+			title:    "the domain default is preferred to the record type default",
+			endpoint: endpoint.NewEndpoint("example.org", endpoint.RecordTypeNS, "ns1.example.com"),
+			expected: 600,
+		},
+		{
+			title:    "configured TTLs are kept within the bounds",
+			endpoint: endpoint.NewEndpointWithTTL("foo.example.com", endpoint.RecordTypeA, 120, "1.2.3.4"),
+			expected: 120,
+		},
+		{
+			title:    "configured TTLs are raised to the minimum",
+			endpoint: endpoint.NewEndpointWithTTL("foo.example.com", endpoint.RecordTypeA, 5, "1.2.3.4"),
+			expected: 60,
+		},
+		{
+			title:    "configured TTLs are lowered to the maximum",
+			endpoint: endpoint.NewEndpointWithTTL("foo.example.com", endpoint.RecordTypeA, 86400, "1.2.3.4"),
+			expected: 3600,
+		},
+		{
+			title:    "the domain maximum replaces the global maximum",
+			endpoint: endpoint.NewEndpointWithTTL("foo.example.org", endpoint.RecordTypeA, 86400, "1.2.3.4"),
+			expected: 7200,
+		},
+		{
+			title:    "the most specific domain applies",
+			endpoint: endpoint.NewEndpointWithTTL("foo.internal.example.org", endpoint.RecordTypeA, 30, "1.2.3.4"),
+			expected: 30,
+		},
+		{
+			title:    "domains only match whole labels",
+			endpoint: endpoint.NewEndpointWithTTL("fooexample.org", endpoint.RecordTypeA, 86400, "1.2.3.4"),
+			expected: 3600,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			original := tc.endpoint.DeepCopy()
+			result := policy.Apply([]*endpoint.Endpoint{tc.endpoint})
+			require.Len(t, result, 1)
+			assert.Equal(t, tc.expected, result[0].RecordTTL)
+			assert.Equal(t, original, tc.endpoint, "the endpoint of the source must not be modified")
+		})
+	}
+}
+
+func TestTTLPolicyApplyNil(t *testing.T) {
+	var policy *TTLPolicy
+	endpoints := []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "1.2.3.4")}
+	assert.Equal(t, endpoints, policy.Apply(endpoints))
+}
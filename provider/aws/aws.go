@@ -24,12 +24,14 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/route53"
 	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
 
 	"sigs.k8s.io/external-dns/endpoint"
 	"sigs.k8s.io/external-dns/plan"
@@ -245,8 +247,9 @@ type AWSProvider struct {
 	batchChangeSize       int
 	batchChangeSizeBytes  int
 	batchChangeSizeValues int
-	batchChangeInterval   time.Duration
-	evaluateTargetHealth  bool
+	// Interval between batch updates, also between the batches of zones applied concurrently.
+	batchChangeLimiter   *rate.Limiter
+	evaluateTargetHealth bool
 	// only consider hosted zones managing domains ending in this suffix
 	domainFilter endpoint.DomainFilter
 	// filter hosted zones by id
@@ -259,8 +262,10 @@ type AWSProvider struct {
 	zoneMatchParent bool
 	preferCNAME     bool
 	zonesCache      *zonesListCache
-	// queue for collecting changes to submit them in the next iteration, but after all other changes
+	// queue for collecting changes to submit them in the next iteration, but after all other changes,
+	// protected by failedChangesMutex as the changes of zones may be applied concurrently
 	failedChangesQueue map[string]Route53Changes
+	failedChangesMutex sync.Mutex
 }
 
 // AWSConfig contains configuration to create a new AWS provider.
@@ -292,7 +297,7 @@ func NewAWSProvider(awsConfig AWSConfig, clients map[string]Route53API) (*AWSPro
 		batchChangeSize:       awsConfig.BatchChangeSize,
 		batchChangeSizeBytes:  awsConfig.BatchChangeSizeBytes,
 		batchChangeSizeValues: awsConfig.BatchChangeSizeValues,
-		batchChangeInterval:   awsConfig.BatchChangeInterval,
+		batchChangeLimiter:    rate.NewLimiter(rate.Every(awsConfig.BatchChangeInterval), 1),
 		evaluateTargetHealth:  awsConfig.EvaluateTargetHealth,
 		preferCNAME:           awsConfig.PreferCNAME,
 		dryRun:                awsConfig.DryRun,
@@ -317,8 +322,28 @@ func (p *AWSProvider) Zones(ctx context.Context) (map[string]*route53types.Hoste
 	return result, nil
 }
 
-// zones returns the list of zones per AWS profile
+// ZoneIDNames returns the names of the hosted zones. Their changes are submitted independently,
+// so the changes of distinct zones may be applied concurrently.
+func (p *AWSProvider) ZoneIDNames(ctx context.Context) (provider.ZoneIDName, error) {
+	zones, err := p.zones(ctx)
+	if err != nil {
+		return nil, provider.NewSoftError(fmt.Errorf("failed to list zones: %w", err))
+	}
+
+	result := provider.ZoneIDName{}
+	for id, zone := range zones {
+		result.Add(id, strings.TrimSuffix(*zone.zone.Name, "."))
+	}
+	return result, nil
+}
+
+// zones returns the list of zones per AWS profile. They are listed once per ApplyChanges when the
+// changes of the zones are applied concurrently.
 func (p *AWSProvider) zones(ctx context.Context) (map[string]*profiledZone, error) {
+	return provider.CachedZones(ctx, p.listZones)
+}
+
+func (p *AWSProvider) listZones(ctx context.Context) (map[string]*profiledZone, error) {
 	if p.zonesCache.zones != nil && time.Since(p.zonesCache.age) < p.zonesCache.duration {
 		log.Debug("Using cached zones list")
 		return p.zonesCache.zones, nil
@@ -646,12 +671,14 @@ func (p *AWSProvider) submitChanges(ctx context.Context, changes Route53Changes,
 		var failedUpdate bool
 
 		// group changes into new changes and into changes that failed in a previous iteration and are retried
+		p.failedChangesMutex.Lock()
 		retriedChanges, newChanges := findChangesInQueue(cs, p.failedChangesQueue[z])
 		p.failedChangesQueue[z] = nil
+		p.failedChangesMutex.Unlock()
 
 		batchCs := append(batchChangeSet(newChanges, p.batchChangeSize, p.batchChangeSizeBytes, p.batchChangeSizeValues),
 			batchChangeSet(retriedChanges, p.batchChangeSize, p.batchChangeSizeBytes, p.batchChangeSizeValues)...)
-		for i, b := range batchCs {
+		for _, b := range batchCs {
 			if len(b) == 0 {
 				continue
 			}
@@ -661,6 +688,12 @@ func (p *AWSProvider) submitChanges(ctx context.Context, changes Route53Changes,
 			}
 
 			if !p.dryRun {
+				if p.batchChangeLimiter != nil {
+					if err := p.batchChangeLimiter.Wait(ctx); err != nil {
+						return err
+					}
+				}
+
 				params := &route53.ChangeResourceRecordSetsInput{
 					HostedZoneId: aws.String(z),
 					ChangeBatch: &route53types.ChangeBatch{
@@ -689,7 +722,9 @@ func (p *AWSProvider) submitChanges(ctx context.Context, changes Route53Changes,
 							if _, err := client.ChangeResourceRecordSets(ctx, params); err != nil {
 								failedUpdate = true
 								log.Errorf("Failed submitting change (error: %v), it will be retried in a separate change batch in the next iteration", err)
+								p.failedChangesMutex.Lock()
 								p.failedChangesQueue[z] = append(p.failedChangesQueue[z], changes...)
+								p.failedChangesMutex.Unlock()
 							} else {
 								successfulChanges = successfulChanges + len(changes)
 							}
@@ -706,9 +741,6 @@ func (p *AWSProvider) submitChanges(ctx context.Context, changes Route53Changes,
 					log.Infof("%d record(s) were successfully updated", successfulChanges)
 				}
 
-				if i != len(batchCs)-1 {
-					time.Sleep(p.batchChangeInterval)
-				}
 			}
 		}
 
@@ -795,6 +827,16 @@ func (p *AWSProvider) AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]*endpoi
 	return endpoints, nil
 }
 
+// PropertyComparisons compares the boolean and numeric provider-specific properties by their value,
+// so that e.g. a weight of "010" doesn't update a record of weight 10 in every synchronization.
+func (p *AWSProvider) PropertyComparisons() map[string]plan.PropertyComparison {
+	return map[string]plan.PropertyComparison{
+		providerSpecificAlias:                {Equal: plan.EqualBool},
+		providerSpecificEvaluateTargetHealth: {Equal: plan.EqualBool},
+		providerSpecificWeight:               {Equal: plan.EqualNumber},
+	}
+}
+
 // newChange returns a route53 Change and a boolean indicating if there should also be a change to a AAAA record
 // returned Change is based on the given record by the given action, e.g.
 // action=ChangeActionCreate returns a change for creation of the record and
@@ -23,6 +23,7 @@ import (
 	"net"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -32,6 +33,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
 
 	"sigs.k8s.io/external-dns/endpoint"
 	"sigs.k8s.io/external-dns/internal/testutils"
@@ -1110,6 +1112,60 @@ func TestAWSApplyChangesDryRun(t *testing.T) {
 		originalRecords)
 }
 
+// lockingRoute53API serializes the calls to a Route53API, counts the listings of the zones and
+// records the times of the change batches.
+type lockingRoute53API struct {
+	Route53API
+	mutex   sync.Mutex
+	lists   int
+	changes []time.Time
+}
+
+func (r *lockingRoute53API) ListHostedZones(ctx context.Context, input *route53.ListHostedZonesInput, optFns ...func(options *route53.Options)) (*route53.ListHostedZonesOutput, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.lists++
+	return r.Route53API.ListHostedZones(ctx, input, optFns...)
+}
+
+func (r *lockingRoute53API) ChangeResourceRecordSets(ctx context.Context, input *route53.ChangeResourceRecordSetsInput, optFns ...func(options *route53.Options)) (*route53.ChangeResourceRecordSetsOutput, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.changes = append(r.changes, time.Now())
+	return r.Route53API.ChangeResourceRecordSets(ctx, input, optFns...)
+}
+
+func TestAWSApplyChangesOfZonesConcurrently(t *testing.T) {
+	p, _ := newAWSProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.teapot.zalan.do."}), provider.NewZoneIDFilter([]string{}), provider.NewZoneTypeFilter(""), defaultEvaluateTargetHealth, false, nil)
+	client := &lockingRoute53API{Route53API: p.clients[defaultAWSProfile]}
+	p.clients = map[string]Route53API{defaultAWSProfile: client}
+	p.zonesCache = &zonesListCache{}
+	p.batchChangeLimiter = rate.NewLimiter(rate.Every(50*time.Millisecond), 1)
+
+	parallel, err := provider.NewZoneParallelProvider(p, 2)
+	require.NoError(t, err)
+	require.NoError(t, parallel.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("concurrent-test.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "8.8.8.8"),
+			endpoint.NewEndpoint("concurrent-test.zone-2.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "8.8.4.4"),
+		},
+	}))
+
+	assert.Equal(t, 1, client.lists, "the zones are listed once per apply")
+	require.Len(t, client.changes, 2)
+	sort.Slice(client.changes, func(i, j int) bool { return client.changes[i].Before(client.changes[j]) })
+	assert.GreaterOrEqual(t, client.changes[1].Sub(client.changes[0]), 40*time.Millisecond, "the batch change interval applies across the zones")
+
+	validateRecords(t, listAWSRecords(t, client, "/hostedzone/zone-1.ext-dns-test-2.teapot.zalan.do."), []route53types.ResourceRecordSet{
+		{
+			Name:            aws.String("concurrent-test.zone-1.ext-dns-test-2.teapot.zalan.do."),
+			Type:            route53types.RRTypeA,
+			TTL:             aws.Int64(recordTTL),
+			ResourceRecords: []route53types.ResourceRecord{{Value: aws.String("8.8.8.8")}},
+		},
+	})
+}
+
 func TestAWSChangesByZones(t *testing.T) {
 	changes := Route53Changes{
 		{
@@ -1989,7 +2045,7 @@ func newAWSProviderWithTagFilter(t *testing.T, domainFilter endpoint.DomainFilte
 		batchChangeSize:       defaultBatchChangeSize,
 		batchChangeSizeBytes:  defaultBatchChangeSizeBytes,
 		batchChangeSizeValues: defaultBatchChangeSizeValues,
-		batchChangeInterval:   defaultBatchChangeInterval,
+		batchChangeLimiter:    rate.NewLimiter(rate.Every(defaultBatchChangeInterval), 1),
 		evaluateTargetHealth:  evaluateTargetHealth,
 		domainFilter:          domainFilter,
 		zoneIDFilter:          zoneIDFilter,
@@ -2139,3 +2195,12 @@ func TestConvertOctalToAscii(t *testing.T) {
 		})
 	}
 }
+
+func TestAWSPropertyComparisons(t *testing.T) {
+	provider, _ := newAWSProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.teapot.zalan.do."}), provider.NewZoneIDFilter([]string{}), provider.NewZoneTypeFilter(""), defaultEvaluateTargetHealth, false, nil)
+	comparisons := provider.PropertyComparisons()
+
+	assert.True(t, comparisons[providerSpecificWeight].Equal(providerSpecificWeight, "10", "010"))
+	assert.False(t, comparisons[providerSpecificWeight].Equal(providerSpecificWeight, "10", "20"))
+	assert.True(t, comparisons[providerSpecificEvaluateTargetHealth].Equal(providerSpecificEvaluateTargetHealth, "true", "True"))
+}
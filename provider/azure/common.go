@@ -19,29 +19,23 @@ package azure
 
 import (
 	"fmt"
-	"strconv"
-	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	dns "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
 	privatedns "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/privatedns/armprivatedns"
+
+	"sigs.k8s.io/external-dns/endpoint"
 )
 
 // Helper function (shared with test code)
 func parseMxTarget[T dns.MxRecord | privatedns.MxRecord](mxTarget string) (T, error) {
-	targetParts := strings.SplitN(mxTarget, " ", 2)
-	if len(targetParts) != 2 {
-		return T{}, fmt.Errorf("mx target needs to be of form '10 example.com'")
-	}
-
-	preferenceRaw, exchange := targetParts[0], targetParts[1]
-	preference, err := strconv.ParseInt(preferenceRaw, 10, 32)
+	mx, err := endpoint.ParseMXTarget(mxTarget)
 	if err != nil {
-		return T{}, fmt.Errorf("invalid preference specified")
+		return T{}, fmt.Errorf("mx target needs to be of form '10 example.com': %w", err)
 	}
 
 	return T{
-		Preference: to.Ptr(int32(preference)),
-		Exchange:   to.Ptr(exchange),
+		Preference: to.Ptr(int32(mx.Preference)),
+		Exchange:   to.Ptr(mx.Exchange),
 	}, nil
 }
@@ -429,6 +429,13 @@ func (p *CloudFlareProvider) AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]
 	return adjustedEndpoints, nil
 }
 
+// PropertyComparisons compares proxied as a boolean, records without it are proxied by default or not
+func (p *CloudFlareProvider) PropertyComparisons() map[string]plan.PropertyComparison {
+	return map[string]plan.PropertyComparison{
+		source.CloudflareProxiedKey: {Equal: plan.EqualBool, Default: strconv.FormatBool(p.proxiedByDefault)},
+	}
+}
+
 // changesByZone separates a multi-zone change into a single change per zone.
 func (p *CloudFlareProvider) changesByZone(zones []cloudflare.Zone, changeSet []*cloudFlareChange) map[string][]*cloudFlareChange {
 	changes := make(map[string][]*cloudFlareChange)
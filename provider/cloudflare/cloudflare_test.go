@@ -1430,3 +1430,10 @@ func TestCloudFlareProvider_newCloudFlareChange(t *testing.T) {
 		t.Errorf("expected region key to be 'us', but got '%s'", change.RegionalHostname.RegionKey)
 	}
 }
+
+func TestCloudflarePropertyComparisons(t *testing.T) {
+	comparison := (&CloudFlareProvider{proxiedByDefault: true}).PropertyComparisons()["external-dns.alpha.kubernetes.io/cloudflare-proxied"]
+	assert.Equal(t, "true", comparison.Default)
+	assert.True(t, comparison.Equal("external-dns.alpha.kubernetes.io/cloudflare-proxied", "true", "TRUE"))
+	assert.False(t, comparison.Equal("external-dns.alpha.kubernetes.io/cloudflare-proxied", "true", "false"))
+}
@@ -20,7 +20,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
 
 	"github.com/digitalocean/godo"
@@ -176,7 +175,7 @@ func (p *DigitalOceanProvider) Records(ctx context.Context) ([]*endpoint.Endpoin
 				}
 
 				if r.Type == endpoint.RecordTypeMX {
-					data = fmt.Sprintf("%d %s", r.Priority, r.Data)
+					data = endpoint.MXTarget{Preference: uint16(r.Priority), Exchange: r.Data}.String()
 				}
 
 				ep := endpoint.NewEndpointWithTTL(name, r.Type, endpoint.TTL(r.TTL), data)
@@ -302,10 +301,10 @@ func makeDomainEditRequest(domain, name, recordType, data string, ttl int) *godo
 	}
 
 	if recordType == endpoint.RecordTypeMX {
-		priority, domain, err := parseMxTarget(data)
+		mx, err := endpoint.ParseMXTarget(data)
 		if err == nil {
-			request.Priority = int(priority)
-			request.Data = provider.EnsureTrailingDot(domain)
+			request.Priority = int(mx.Preference)
+			request.Data = provider.EnsureTrailingDot(mx.Exchange)
 		} else {
 			log.WithFields(log.Fields{
 				"domain":     domain,
@@ -661,18 +660,3 @@ func (p *DigitalOceanProvider) ApplyChanges(ctx context.Context, planChanges *pl
 
 	return p.submitChanges(ctx, &changes)
 }
-
-func parseMxTarget(mxTarget string) (priority int64, exchange string, err error) {
-	targetParts := strings.SplitN(mxTarget, " ", 2)
-	if len(targetParts) != 2 {
-		return priority, exchange, fmt.Errorf("mx target needs to be of form '10 example.com'")
-	}
-
-	priorityRaw, exchange := targetParts[0], targetParts[1]
-	priority, err = strconv.ParseInt(priorityRaw, 10, 32)
-	if err != nil {
-		return priority, exchange, fmt.Errorf("invalid priority specified")
-	}
-
-	return priority, exchange, nil
-}
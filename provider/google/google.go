@@ -27,11 +27,13 @@ import (
 	"github.com/linki/instrumented_http"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/oauth2/google"
+	"golang.org/x/time/rate"
 	dns "google.golang.org/api/dns/v1"
 	googleapi "google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
 
 	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/tracing"
 	"sigs.k8s.io/external-dns/plan"
 	"sigs.k8s.io/external-dns/provider"
 )
@@ -106,8 +108,8 @@ type GoogleProvider struct {
 	dryRun bool
 	// Max batch size to submit to Google Cloud DNS per transaction.
 	batchChangeSize int
-	// Interval between batch updates.
-	batchChangeInterval time.Duration
+	// Interval between batch updates, also between the batches of zones applied concurrently.
+	batchChangeLimiter *rate.Limiter
 	// only consider hosted zones managing domains ending in this suffix
 	domainFilter endpoint.DomainFilter
 	// filter for zones based on visibility
@@ -131,7 +133,7 @@ func NewGoogleProvider(ctx context.Context, project string, domainFilter endpoin
 		return nil, err
 	}
 
-	gcloud = instrumented_http.NewClient(gcloud, &instrumented_http.Callbacks{
+	gcloud = instrumented_http.NewClient(tracing.NewClient(gcloud), &instrumented_http.Callbacks{
 		PathProcessor: func(path string) string {
 			parts := strings.Split(path, "/")
 			return parts[len(parts)-1]
@@ -158,7 +160,7 @@ func NewGoogleProvider(ctx context.Context, project string, domainFilter endpoin
 		project:                  project,
 		dryRun:                   dryRun,
 		batchChangeSize:          batchChangeSize,
-		batchChangeInterval:      batchChangeInterval,
+		batchChangeLimiter:       rate.NewLimiter(rate.Every(batchChangeInterval), 1),
 		domainFilter:             domainFilter,
 		zoneTypeFilter:           zoneTypeFilter,
 		zoneIDFilter:             zoneIDFilter,
@@ -171,8 +173,13 @@ func NewGoogleProvider(ctx context.Context, project string, domainFilter endpoin
 	return provider, nil
 }
 
-// Zones returns the list of hosted zones.
+// Zones returns the list of hosted zones. They are listed once per ApplyChanges when the changes
+// of the zones are applied concurrently.
 func (p *GoogleProvider) Zones(ctx context.Context) (map[string]*dns.ManagedZone, error) {
+	return provider.CachedZones(ctx, p.listZones)
+}
+
+func (p *GoogleProvider) listZones(ctx context.Context) (map[string]*dns.ManagedZone, error) {
 	zones := make(map[string]*dns.ManagedZone)
 
 	f := func(resp *dns.ManagedZonesListResponse) error {
@@ -208,6 +215,21 @@ func (p *GoogleProvider) Zones(ctx context.Context) (map[string]*dns.ManagedZone
 	return zones, nil
 }
 
+// ZoneIDNames returns the names of the hosted zones. Their changes are submitted independently,
+// so the changes of distinct zones may be applied concurrently.
+func (p *GoogleProvider) ZoneIDNames(ctx context.Context) (provider.ZoneIDName, error) {
+	zones, err := p.Zones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := provider.ZoneIDName{}
+	for id, zone := range zones {
+		result.Add(id, strings.TrimSuffix(zone.DnsName, "."))
+	}
+	return result, nil
+}
+
 // Records returns the list of records in all relevant zones.
 func (p *GoogleProvider) Records(ctx context.Context) (endpoints []*endpoint.Endpoint, _ error) {
 	zones, err := p.Zones(ctx)
@@ -301,11 +323,14 @@ func (p *GoogleProvider) submitChange(ctx context.Context, change *dns.Change) e
 				continue
 			}
 
+			if p.batchChangeLimiter != nil {
+				if err := p.batchChangeLimiter.Wait(ctx); err != nil {
+					return err
+				}
+			}
 			if _, err := p.changesClient.Create(p.project, zone, c).Do(); err != nil {
 				return provider.NewSoftError(fmt.Errorf("failed to create changes: %w", err))
 			}
-
-			time.Sleep(p.batchChangeInterval)
 		}
 	}
 
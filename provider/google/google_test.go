@@ -21,11 +21,15 @@ import (
 	"net/http"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/net/context"
+	"golang.org/x/time/rate"
 	dns "google.golang.org/api/dns/v1"
 	"google.golang.org/api/googleapi"
 
@@ -170,6 +174,42 @@ func (m *mockChangesClient) Create(project string, managedZone string, change *d
 	return &mockChangesCreateCall{project: project, managedZone: managedZone, change: change}
 }
 
+// countingManagedZonesClient counts the listings of the zones.
+type countingManagedZonesClient struct {
+	mockManagedZonesClient
+	lists atomic.Int32
+}
+
+func (m *countingManagedZonesClient) List(project string) managedZonesListCallInterface {
+	m.lists.Add(1)
+	return m.mockManagedZonesClient.List(project)
+}
+
+// recordingChangesClient records the time of every change and applies the concurrent changes one at a time.
+type recordingChangesClient struct {
+	mockChangesClient
+	mutex   sync.Mutex
+	created []time.Time
+}
+
+func (m *recordingChangesClient) Create(project string, managedZone string, change *dns.Change) changesCreateCallInterface {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.created = append(m.created, time.Now())
+	return &lockedChangesCreateCall{call: m.mockChangesClient.Create(project, managedZone, change), mutex: &m.mutex}
+}
+
+type lockedChangesCreateCall struct {
+	call  changesCreateCallInterface
+	mutex *sync.Mutex
+}
+
+func (c *lockedChangesCreateCall) Do(opts ...googleapi.CallOption) (*dns.Change, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.call.Do(opts...)
+}
+
 func zoneKey(project, zoneName string) string {
 	return project + "/" + zoneName
 }
@@ -669,6 +709,29 @@ func validateChangeRecords(t *testing.T, records []*dns.ResourceRecordSet, expec
 	}
 }
 
+func TestGoogleApplyChangesOfZonesConcurrently(t *testing.T) {
+	p := newGoogleProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.gcp.zalan.do"}), provider.NewZoneIDFilter([]string{""}), false, nil, nil, nil)
+	zonesClient := &countingManagedZonesClient{}
+	changesClient := &recordingChangesClient{}
+	p.managedZonesClient = zonesClient
+	p.changesClient = changesClient
+	p.batchChangeLimiter = rate.NewLimiter(rate.Every(50*time.Millisecond), 1)
+
+	parallel, err := provider.NewZoneParallelProvider(p, 2)
+	require.NoError(t, err)
+	require.NoError(t, parallel.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("concurrent-test.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, "8.8.8.8"),
+			endpoint.NewEndpoint("concurrent-test.zone-2.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, "8.8.4.4"),
+		},
+	}))
+
+	assert.Equal(t, int32(1), zonesClient.lists.Load(), "the zones are listed once per apply")
+	require.Len(t, changesClient.created, 2)
+	sort.Slice(changesClient.created, func(i, j int) bool { return changesClient.created[i].Before(changesClient.created[j]) })
+	assert.GreaterOrEqual(t, changesClient.created[1].Sub(changesClient.created[0]), 40*time.Millisecond, "the batch change interval applies across the zones")
+}
+
 func validateChangeRecord(t *testing.T, record *dns.ResourceRecordSet, expected *dns.ResourceRecordSet) {
 	assert.Equal(t, expected.Name, record.Name)
 	assert.Equal(t, expected.Rrdatas, record.Rrdatas)
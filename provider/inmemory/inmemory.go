@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"sync"
 
 	log "github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -40,6 +41,8 @@ var (
 	ErrRecordNotFound = errors.New("record not found")
 	// ErrDuplicateRecordFound when record is repeated in create/update/delete
 	ErrDuplicateRecordFound = errors.New("invalid batch request")
+	// ErrInvalidRecordData when a created or updated record has targets which aren't valid data of its type
+	ErrInvalidRecordData = errors.New("invalid record data")
 )
 
 // InMemoryProvider - dns provider only used for testing purposes
@@ -121,6 +124,11 @@ func (im *InMemoryProvider) Zones() map[string]string {
 	return im.filter.Zones(im.client.Zones())
 }
 
+// ZoneIDNames returns the zones as specified by domain. The changes of distinct zones may be applied concurrently.
+func (im *InMemoryProvider) ZoneIDNames(ctx context.Context) (provider.ZoneIDName, error) {
+	return im.Zones(), nil
+}
+
 // Records returns the list of endpoints
 func (im *InMemoryProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	defer im.OnRecords()
@@ -244,14 +252,17 @@ func (f *filter) EndpointZoneID(endpoint *endpoint.Endpoint, zones map[string]st
 type zone map[endpoint.EndpointKey]*endpoint.Endpoint
 
 type inMemoryClient struct {
+	mutex sync.RWMutex
 	zones map[string]zone
 }
 
 func newInMemoryClient() *inMemoryClient {
-	return &inMemoryClient{map[string]zone{}}
+	return &inMemoryClient{zones: map[string]zone{}}
 }
 
 func (c *inMemoryClient) Records(zone string) ([]*endpoint.Endpoint, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
 	if _, ok := c.zones[zone]; !ok {
 		return nil, ErrZoneNotFound
 	}
@@ -264,6 +275,8 @@ func (c *inMemoryClient) Records(zone string) ([]*endpoint.Endpoint, error) {
 }
 
 func (c *inMemoryClient) Zones() map[string]string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
 	zones := map[string]string{}
 	for zone := range c.zones {
 		zones[zone] = zone
@@ -272,6 +285,8 @@ func (c *inMemoryClient) Zones() map[string]string {
 }
 
 func (c *inMemoryClient) CreateZone(zone string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 	if _, ok := c.zones[zone]; ok {
 		return ErrZoneAlreadyExists
 	}
@@ -281,6 +296,8 @@ func (c *inMemoryClient) CreateZone(zone string) error {
 }
 
 func (c *inMemoryClient) ApplyChanges(ctx context.Context, zoneID string, changes *plan.Changes) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 	if err := c.validateChangeBatch(zoneID, changes); err != nil {
 		return err
 	}
@@ -304,6 +321,20 @@ func (c *inMemoryClient) updateMesh(mesh sets.Set[endpoint.EndpointKey], record
 	return nil
 }
 
+// validateRecordData validates the targets of the record types with structured data
+func validateRecordData(record *endpoint.Endpoint) error {
+	if !endpoint.IsValidatedRecordType(record.RecordType) {
+		return nil
+	}
+	for _, target := range record.Targets {
+		if _, err := endpoint.ValidateTarget(record.RecordType, target); err != nil {
+			log.Warnf("Invalid target %q of the %s record %s: %v", target, record.RecordType, record.DNSName, err)
+			return ErrInvalidRecordData
+		}
+	}
+	return nil
+}
+
 // validateChangeBatch validates that the changes passed to InMemory DNS provider is valid
 func (c *inMemoryClient) validateChangeBatch(zone string, changes *plan.Changes) error {
 	curZone, ok := c.zones[zone]
@@ -315,6 +346,9 @@ func (c *inMemoryClient) validateChangeBatch(zone string, changes *plan.Changes)
 		if _, exists := curZone[newEndpoint.Key()]; exists {
 			return ErrRecordAlreadyExists
 		}
+		if err := validateRecordData(newEndpoint); err != nil {
+			return err
+		}
 		if err := c.updateMesh(mesh, newEndpoint); err != nil {
 			return err
 		}
@@ -323,6 +357,9 @@ func (c *inMemoryClient) validateChangeBatch(zone string, changes *plan.Changes)
 		if _, exists := curZone[updateEndpoint.Key()]; !exists {
 			return ErrRecordNotFound
 		}
+		if err := validateRecordData(updateEndpoint); err != nil {
+			return err
+		}
 		if err := c.updateMesh(mesh, updateEndpoint); err != nil {
 			return err
 		}
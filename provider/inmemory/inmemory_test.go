@@ -329,6 +329,25 @@ func testInMemoryValidateChangeBatch(t *testing.T) {
 			},
 			errorType: ErrRecordNotFound,
 		},
+		{
+			title:       "zones, update, right zone, invalid batch - invalid record data",
+			expectError: true,
+			zone:        "org",
+			init:        init,
+			changes: &plan.Changes{
+				Create: []*endpoint.Endpoint{
+					{
+						DNSName:    "caa.org",
+						Targets:    endpoint.Targets{"0 issue"},
+						RecordType: endpoint.RecordTypeCAA,
+					},
+				},
+				UpdateNew: []*endpoint.Endpoint{},
+				UpdateOld: []*endpoint.Endpoint{},
+				Delete:    []*endpoint.Endpoint{},
+			},
+			errorType: ErrInvalidRecordData,
+		},
 		{
 			title:       "zones, update, right zone, invalid batch - wrong delete",
 			expectError: true,
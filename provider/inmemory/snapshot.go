@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/zonefile"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// Snapshot holds the zones and records of an InMemoryProvider.
+type Snapshot struct {
+	Zones   []string             `json:"zones"`
+	Records []*endpoint.Endpoint `json:"records,omitempty"`
+	// ZoneFiles are RFC 1035 zone files whose records are added to Records by ReadSnapshot,
+	// relative paths are relative to the directory of the snapshot.
+	ZoneFiles []string `json:"zoneFiles,omitempty"`
+}
+
+// ReadSnapshot reads a YAML or JSON snapshot from the file at path, with the records of its zone files.
+func ReadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read zone snapshot: %w", err)
+	}
+	snapshot := &Snapshot{}
+	if err := yaml.UnmarshalStrict(data, snapshot); err != nil {
+		return nil, fmt.Errorf("invalid zone snapshot %s: %w", path, err)
+	}
+	for _, zoneFile := range snapshot.ZoneFiles {
+		if !filepath.IsAbs(zoneFile) {
+			zoneFile = filepath.Join(filepath.Dir(path), zoneFile)
+		}
+		records, err := readZoneFile(zoneFile)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Records = append(snapshot.Records, records...)
+	}
+	return snapshot, nil
+}
+
+func readZoneFile(path string) ([]*endpoint.Endpoint, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read zone file: %w", err)
+	}
+	defer f.Close()
+
+	records, err := zonefile.Read(f, "")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return records, nil
+}
+
+// LoadSnapshot creates the zones of snapshot with its records.
+func (im *InMemoryProvider) LoadSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	for _, z := range snapshot.Zones {
+		if err := im.CreateZone(z); err != nil {
+			return fmt.Errorf("zone %s: %w", z, err)
+		}
+	}
+	zones := im.Zones()
+	for _, ep := range snapshot.Records {
+		if im.filter.EndpointZoneID(ep, zones) == "" {
+			return fmt.Errorf("record %s is not in any zone of the snapshot", ep.DNSName)
+		}
+		if ep.Labels == nil {
+			ep.Labels = endpoint.NewLabels()
+		}
+	}
+	return im.ApplyChanges(ctx, &plan.Changes{Create: snapshot.Records})
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func writeSnapshot(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "zones.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadSnapshot(t *testing.T) {
+	snapshot, err := ReadSnapshot(writeSnapshot(t, `
+zones: [example.com, example.org]
+records:
+- dnsName: www.example.com
+  recordType: A
+  targets: [1.2.3.4]
+  recordTTL: 300
+- dnsName: api.example.org
+  recordType: CNAME
+  targets: [lb.example.net]
+`))
+	require.NoError(t, err)
+
+	p := NewInMemoryProvider()
+	require.NoError(t, p.LoadSnapshot(context.Background(), snapshot))
+	assert.Len(t, p.Zones(), 2)
+
+	records, err := p.Records(context.Background())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("www.example.com", endpoint.RecordTypeA, 300, "1.2.3.4"),
+		endpoint.NewEndpoint("api.example.org", endpoint.RecordTypeCNAME, "lb.example.net"),
+	}, records)
+}
+
+func TestReadSnapshotZoneFiles(t *testing.T) {
+	path := writeSnapshot(t, "zones: [example.com]\nzoneFiles: [example.com.zone]\n")
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(path), "example.com.zone"),
+		[]byte("$ORIGIN example.com.\nwww 60 IN A 1.2.3.4\n"), 0o600))
+
+	snapshot, err := ReadSnapshot(path)
+	require.NoError(t, err)
+	assert.Equal(t, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("www.example.com", endpoint.RecordTypeA, 60, "1.2.3.4"),
+	}, snapshot.Records)
+
+	_, err = ReadSnapshot(writeSnapshot(t, "zones: [example.com]\nzoneFiles: [missing.zone]\n"))
+	assert.ErrorContains(t, err, "failed to read zone file")
+}
+
+func TestLoadSnapshotErrors(t *testing.T) {
+	_, err := ReadSnapshot(writeSnapshot(t, "zones: [example.com]\nrecord: []\n"))
+	assert.ErrorContains(t, err, "invalid zone snapshot")
+
+	snapshot, err := ReadSnapshot(writeSnapshot(t, "zones: [example.com]\nrecords:\n- dnsName: www.example.org\n  recordType: A\n  targets: [1.2.3.4]\n"))
+	require.NoError(t, err)
+	assert.ErrorContains(t, NewInMemoryProvider().LoadSnapshot(context.Background(), snapshot), "record www.example.org is not in any zone")
+}
@@ -260,7 +260,12 @@ func (p *PDNSProvider) convertRRSetToEndpoints(rr pgo.RrSet) (endpoints []*endpo
 	for _, record := range rr.Records {
 		// If a record is "Disabled", it's not supposed to be "visible"
 		if !record.Disabled {
-			targets = append(targets, record.Content)
+			content := record.Content
+			// PowerDNS presents structured record data differently than the canonical targets of the sources
+			if canonical, err := endpoint.ValidateTarget(rr.Type_, content); err == nil {
+				content = canonical
+			}
+			targets = append(targets, content)
 		}
 	}
 	if rr.Type_ == "ALIAS" {
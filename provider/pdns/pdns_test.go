@@ -878,6 +878,18 @@ func (suite *NewPDNSProviderTestSuite) TestPDNSRRSetToEndpoints() {
 	eps, err = p.convertRRSetToEndpoints(RRSetDisabledRecord)
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), endpointsDisabledRecord, eps)
+
+	/* Given an RRSet of HTTPS records, we test:
+	   - We present the records the way the sources do
+	*/
+	eps, err = p.convertRRSetToEndpoints(pgo.RrSet{
+		Name:    "example.com.",
+		Type_:   "HTTPS",
+		Ttl:     300,
+		Records: []pgo.Record{{Content: "1 . alpn=h2,h3"}},
+	})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), []*endpoint.Endpoint{endpoint.NewEndpointWithTTL("example.com", endpoint.RecordTypeHTTPS, 300, `1 . alpn="h2,h3"`)}, eps)
 }
 
 func (suite *NewPDNSProviderTestSuite) TestPDNSRecords() {
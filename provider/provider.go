@@ -51,6 +51,13 @@ type Provider interface {
 	GetDomainFilter() endpoint.DomainFilterInterface
 }
 
+// PropertyComparisonProvider is implemented by providers normalizing the values of their provider specific
+// properties. The plan compares the properties with the returned comparisons by their name, so that records
+// whose properties only differ by their representation are not updated in every synchronization.
+type PropertyComparisonProvider interface {
+	PropertyComparisons() map[string]plan.PropertyComparison
+}
+
 type BaseProvider struct{}
 
 func (b BaseProvider) AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]*endpoint.Endpoint, error) {
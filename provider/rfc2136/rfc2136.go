@@ -148,6 +148,16 @@ func NewRfc2136Provider(host string, port int, zoneNames []string, insecure bool
 	return r, nil
 }
 
+// ZoneIDNames returns the configured zones, identified by their names. Every message updates a single
+// zone over its own connection, so the changes of distinct zones may be applied concurrently.
+func (r rfc2136Provider) ZoneIDNames(ctx context.Context) (provider.ZoneIDName, error) {
+	result := provider.ZoneIDName{}
+	for _, zone := range r.zoneNames {
+		result.Add(zone, strings.TrimSuffix(zone, "."))
+	}
+	return result, nil
+}
+
 // KeyName will return TKEY name and TSIG handle to use for followon actions with a secure connection
 func (r rfc2136Provider) KeyData() (keyName string, handle *gss.Client, err error) {
 	handle, err = gss.NewClient(new(dns.Client))
@@ -192,14 +202,41 @@ OuterLoop:
 			rrValues = []string{rr.(*dns.AAAA).AAAA.String()}
 			rrType = "AAAA"
 		case dns.TypeTXT:
-			rrValues = (rr.(*dns.TXT).Txt)
+			rrValues = []string{endpoint.TXTTarget(rr.(*dns.TXT).Txt).Text()}
 			rrType = "TXT"
+		case dns.TypeSRV:
+			srv := rr.(*dns.SRV)
+			rrValues = []string{endpoint.SRVTarget{Priority: srv.Priority, Weight: srv.Weight, Port: srv.Port, Target: srv.Target}.String()}
+			rrType = "SRV"
+		case dns.TypeMX:
+			mx := rr.(*dns.MX)
+			rrValues = []string{endpoint.MXTarget{Preference: mx.Preference, Exchange: mx.Mx}.String()}
+			rrType = "MX"
+		case dns.TypeNAPTR:
+			naptr := rr.(*dns.NAPTR)
+			rrValues = []string{endpoint.NAPTRTarget{
+				Order:       naptr.Order,
+				Preference:  naptr.Preference,
+				Flags:       naptr.Flags,
+				Service:     naptr.Service,
+				Regexp:      naptr.Regexp,
+				Replacement: naptr.Replacement,
+			}.String()}
+			rrType = "NAPTR"
 		case dns.TypeNS:
 			rrValues = []string{rr.(*dns.NS).Ns}
 			rrType = "NS"
 		case dns.TypePTR:
 			rrValues = []string{rr.(*dns.PTR).Ptr}
 			rrType = "PTR"
+		case dns.TypeCAA, dns.TypeTLSA, dns.TypeSSHFP, dns.TypeHTTPS, dns.TypeSVCB:
+			rrType = dns.TypeToString[rr.Header().Rrtype]
+			target, err := endpoint.ValidateTarget(rrType, endpoint.RData(rr))
+			if err != nil {
+				log.Warnf("Skipping record %s: %v", rr, err)
+				continue
+			}
+			rrValues = []string{target}
 		default:
 			continue // Unhandled record type
 		}
@@ -453,7 +490,7 @@ func (r rfc2136Provider) AddRecord(m *dns.Msg, ep *endpoint.Endpoint) error {
 	}
 
 	for _, target := range ep.Targets {
-		newRR := fmt.Sprintf("%s %d %s %s", ep.DNSName, ttl, ep.RecordType, target)
+		newRR := fmt.Sprintf("%s %d %s %s", ep.DNSName, ttl, ep.RecordType, rdata(ep.RecordType, target))
 		log.Infof("Adding RR: %s", newRR)
 
 		rr, err := dns.NewRR(newRR)
@@ -470,7 +507,7 @@ func (r rfc2136Provider) AddRecord(m *dns.Msg, ep *endpoint.Endpoint) error {
 func (r rfc2136Provider) RemoveRecord(m *dns.Msg, ep *endpoint.Endpoint) error {
 	log.Debugf("RemoveRecord.ep=%s", ep)
 	for _, target := range ep.Targets {
-		newRR := fmt.Sprintf("%s %d %s %s", ep.DNSName, ep.RecordTTL, ep.RecordType, target)
+		newRR := fmt.Sprintf("%s %d %s %s", ep.DNSName, ep.RecordTTL, ep.RecordType, rdata(ep.RecordType, target))
 		log.Infof("Removing RR: %s", newRR)
 
 		rr, err := dns.NewRR(newRR)
@@ -484,6 +521,17 @@ func (r rfc2136Provider) RemoveRecord(m *dns.Msg, ep *endpoint.Endpoint) error {
 	return nil
 }
 
+// rdata returns the target of a record in the presentation format of zone files. The text of TXT
+// targets is quoted, so that it is kept in one record whatever spaces it contains.
+func rdata(recordType, target string) string {
+	if recordType == endpoint.RecordTypeTXT {
+		if txt, err := endpoint.ParseTXTTarget(target); err == nil {
+			return txt.String()
+		}
+	}
+	return target
+}
+
 func (r rfc2136Provider) SendMessage(msg *dns.Msg) error {
 	if r.dryRun {
 		log.Debugf("SendMessage.skipped")
@@ -24,6 +24,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -37,6 +38,7 @@ import (
 )
 
 type rfc2136Stub struct {
+	mutex      sync.Mutex
 	output     []*dns.Envelope
 	updateMsgs []*dns.Msg
 	createMsgs []*dns.Msg
@@ -61,6 +63,9 @@ func getSortedChanges(msgs []*dns.Msg) []string {
 }
 
 func (r *rfc2136Stub) SendMessage(msg *dns.Msg) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
 	zone := extractZoneFromMessage(msg.String())
 	// Make sure the zone starts with . to make sure HasSuffix does not match forbar.com for zone bar.com
 	if !strings.HasPrefix(zone, ".") {
@@ -405,6 +410,73 @@ func TestRfc2136GetRecords(t *testing.T) {
 	assert.True(t, contains(recs, "v2.foo.com"))
 }
 
+func TestRfc2136GetRecordsStructuredTypes(t *testing.T) {
+	stub := newStub()
+	err := stub.setOutput([]string{
+		`foo.com 3600 IN CAA 0 issue "letsencrypt.org"`,
+		"foo.com 3600 IN HTTPS 1 . alpn=h2,h3",
+		"_443._tcp.foo.com 3600 IN TLSA 3 1 1 0C72AC70B745AC19998811B131D662C9AC69DBDBE7CB23E5B514B56664C5D3D6",
+		"host.foo.com 3600 IN SSHFP 4 2 123456789abcdef67890123456789abcdef67890123456789abcdef123456789",
+		"_svc.foo.com 3600 IN SVCB 0 svc.foo.com.",
+		"_sip._tcp.foo.com 3600 IN SRV 0 50 5060 sip.foo.com.",
+		"foo.com 3600 IN MX 10 mail.foo.com.",
+		`foo.com 3600 IN NAPTR 100 10 "S" "SIP+D2U" "" _sip._udp.foo.com.`,
+		`foo.com 3600 IN TXT "v=spf1 include:foo.com" " -all"`,
+	})
+	assert.NoError(t, err)
+
+	provider, err := createRfc2136StubProvider(stub)
+	assert.NoError(t, err)
+
+	recs, err := provider.Records(context.Background())
+	assert.NoError(t, err)
+
+	targets := map[string]string{}
+	for _, rec := range recs {
+		assert.Len(t, rec.Targets, 1)
+		targets[rec.RecordType] = rec.Targets[0]
+		// the targets compare equal to the canonical targets of the sources
+		canonical, err := endpoint.ValidateTarget(rec.RecordType, rec.Targets[0])
+		assert.NoError(t, err)
+		assert.True(t, endpoint.Targets{canonical}.SameAs(rec.RecordType, rec.Targets), canonical)
+	}
+	assert.Equal(t, map[string]string{
+		endpoint.RecordTypeCAA:   `0 issue "letsencrypt.org"`,
+		endpoint.RecordTypeHTTPS: `1 . alpn="h2,h3"`,
+		endpoint.RecordTypeTLSA:  "3 1 1 0c72ac70b745ac19998811b131d662c9ac69dbdbe7cb23e5b514b56664c5d3d6",
+		endpoint.RecordTypeSSHFP: "4 2 123456789ABCDEF67890123456789ABCDEF67890123456789ABCDEF123456789",
+		endpoint.RecordTypeSVCB:  "0 svc.foo.com.",
+		endpoint.RecordTypeSRV:   "0 50 5060 sip.foo.com",
+		endpoint.RecordTypeMX:    "10 mail.foo.com",
+		endpoint.RecordTypeNAPTR: `100 10 "S" "SIP+D2U" "" _sip._udp.foo.com`,
+		endpoint.RecordTypeTXT:   "v=spf1 include:foo.com -all",
+	}, targets)
+}
+
+func TestRfc2136ApplyChangesStructuredTypes(t *testing.T) {
+	stub := newStub()
+	provider, err := createRfc2136StubProvider(stub)
+	assert.NoError(t, err)
+
+	err = provider.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("foo.com", endpoint.RecordTypeCAA, `0 issue "letsencrypt.org"`),
+			endpoint.NewEndpoint("foo.com", endpoint.RecordTypeHTTPS, `1 . alpn="h2,h3"`),
+			endpoint.NewEndpoint("foo.com", endpoint.RecordTypeMX, "10 mail.foo.com"),
+			endpoint.NewEndpoint("txt.foo.com", endpoint.RecordTypeTXT, "v=spf1 include:foo.com -all"),
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 4, len(stub.createMsgs))
+	createMsgs := strings.Join(strings.Fields(strings.Join(getSortedChanges(stub.createMsgs), " ")), " ")
+	assert.Contains(t, createMsgs, `foo.com. 300 IN CAA 0 issue "letsencrypt.org"`)
+	assert.Contains(t, createMsgs, `foo.com. 300 IN HTTPS 1 . alpn="h2,h3"`)
+	assert.Contains(t, createMsgs, `foo.com. 300 IN MX 10 mail.foo.com.`)
+	// the text is kept in one character string whatever spaces it contains
+	assert.Contains(t, createMsgs, `txt.foo.com. 300 IN TXT "v=spf1 include:foo.com -all"`)
+}
+
 // Make sure the test version of SendMessage raises an error
 // if a zone update ever contains records outside of it's zone
 // as the TestRfc2136ApplyChanges tests all assume this
@@ -551,6 +623,37 @@ func TestRfc2136ApplyChangesWithZones(t *testing.T) {
 	assert.True(t, strings.Contains(updateMsgs[1], "v2.foobar.com"))
 }
 
+func TestRfc2136ApplyChangesOfZonesConcurrently(t *testing.T) {
+	stub := newStub()
+	p, err := createRfc2136StubProviderWithZones(stub)
+	assert.NoError(t, err)
+
+	zones, err := p.(provider.ZoneLister).ZoneIDNames(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, provider.ZoneIDName{"foo.com": "foo.com", "foobar.com": "foobar.com"}, zones)
+
+	parallel, err := provider.NewZoneParallelProvider(p, 2)
+	assert.NoError(t, err)
+	err = parallel.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("v1.foo.com", endpoint.RecordTypeA, "1.2.3.4"),
+			endpoint.NewEndpoint("v1.foobar.com", endpoint.RecordTypeTXT, "boom"),
+		},
+		Delete: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("v2.foobar.com", endpoint.RecordTypeTXT, "boom2"),
+		},
+	})
+	assert.NoError(t, err)
+
+	createMsgs := getSortedChanges(stub.createMsgs)
+	assert.Len(t, createMsgs, 2)
+	assert.Contains(t, createMsgs[0], "v1.foo.com")
+	assert.Contains(t, createMsgs[1], "v1.foobar.com")
+	updateMsgs := getSortedChanges(stub.updateMsgs)
+	assert.Len(t, updateMsgs, 1)
+	assert.Contains(t, updateMsgs[0], "v2.foobar.com")
+}
+
 // These tests use the foo.com and foobar.com zones and with filters set to both zones
 // createMsgs and updateMsgs need sorted when are are used
 func TestRfc2136ApplyChangesWithZonesFilters(t *testing.T) {
@@ -17,13 +17,13 @@ limitations under the License.
 package api
 
 import (
-	"context"
 	"encoding/json"
 	"net"
 	"net/http"
 	"time"
 
 	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/tracing"
 	"sigs.k8s.io/external-dns/plan"
 	"sigs.k8s.io/external-dns/provider"
 
@@ -42,7 +42,7 @@ type WebhookServer struct {
 func (p *WebhookServer) RecordsHandler(w http.ResponseWriter, req *http.Request) {
 	switch req.Method {
 	case http.MethodGet:
-		records, err := p.Provider.Records(context.Background())
+		records, err := p.Provider.Records(req.Context())
 		if err != nil {
 			log.Errorf("Failed to get Records: %v", err)
 			w.WriteHeader(http.StatusInternalServerError)
@@ -61,7 +61,7 @@ func (p *WebhookServer) RecordsHandler(w http.ResponseWriter, req *http.Request)
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
-		err := p.Provider.ApplyChanges(context.Background(), &changes)
+		err := p.Provider.ApplyChanges(req.Context(), &changes)
 		if err != nil {
 			log.Errorf("Failed to apply changes: %v", err)
 			w.WriteHeader(http.StatusInternalServerError)
@@ -126,7 +126,7 @@ func StartHTTPApi(provider provider.Provider, startedChan chan struct{}, readTim
 
 	s := &http.Server{
 		Addr:         providerPort,
-		Handler:      m,
+		Handler:      tracing.NewHandler(m, "webhook"),
 		ReadTimeout:  readTimeout,
 		WriteTimeout: writeTimeout,
 	}
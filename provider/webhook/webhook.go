@@ -25,6 +25,7 @@ import (
 	"net/url"
 
 	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/tracing"
 	"sigs.k8s.io/external-dns/plan"
 	"sigs.k8s.io/external-dns/provider"
 	webhookapi "sigs.k8s.io/external-dns/provider/webhook/api"
@@ -118,7 +119,7 @@ func NewWebhookProvider(u string) (*WebhookProvider, error) {
 	}
 	req.Header.Set(acceptHeader, webhookapi.MediaTypeFormatAndVersion)
 
-	client := &http.Client{}
+	client := tracing.NewClient(&http.Client{})
 	var resp *http.Response
 	err = backoff.Retry(func() error {
 		resp, err = client.Do(req)
@@ -163,7 +164,7 @@ func (p WebhookProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, err
 	recordsRequestsGauge.Inc()
 	u := p.remoteServerURL.JoinPath("records").String()
 
-	req, err := http.NewRequest("GET", u, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
 		recordsErrorsGauge.Inc()
 		log.Debugf("Failed to create request: %s", err.Error())
@@ -209,7 +210,7 @@ func (p WebhookProvider) ApplyChanges(ctx context.Context, changes *plan.Changes
 		return err
 	}
 
-	req, err := http.NewRequest("POST", u, b)
+	req, err := http.NewRequestWithContext(ctx, "POST", u, b)
 	if err != nil {
 		applyChangesErrorsGauge.Inc()
 		log.Debugf("Failed to create request: %s", err.Error())
@@ -0,0 +1,230 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+var (
+	zoneApplyDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "external_dns",
+			Subsystem: "provider",
+			Name:      "zone_apply_duration_seconds",
+			Help:      "Duration of applying the changes of a single zone.",
+		},
+		[]string{"zone"},
+	)
+	zoneApplyErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "external_dns",
+			Subsystem: "provider",
+			Name:      "zone_apply_errors_total",
+			Help:      "Number of errors applying the changes of a single zone.",
+		},
+		[]string{"zone"},
+	)
+
+	registerZoneParallelProviderMetrics = sync.Once{}
+)
+
+// ZoneLister is implemented by providers whose ApplyChanges may be called concurrently
+// with the changes of distinct zones. ZoneIDNames returns the zones managed by the provider.
+// The provider keeps pacing its requests, e.g. the interval between batches, across the
+// concurrent calls.
+type ZoneLister interface {
+	ZoneIDNames(ctx context.Context) (ZoneIDName, error)
+}
+
+type zoneCacheKey struct{}
+
+// zoneCache holds the zones listed during a single ApplyChanges call of a ZoneParallelProvider.
+type zoneCache struct {
+	mutex sync.Mutex
+	zones interface{}
+}
+
+// WithZoneCache returns a context caching the zones listed with CachedZones, so that the concurrent
+// ApplyChanges calls of a ZoneParallelProvider share a single listing of the zones.
+func WithZoneCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, zoneCacheKey{}, &zoneCache{})
+}
+
+// CachedZones returns the zones cached in ctx by WithZoneCache, listing them with list at the first call.
+// Without a cache in ctx, the zones are listed at every call. Errors are not cached.
+func CachedZones[T any](ctx context.Context, list func(context.Context) (T, error)) (T, error) {
+	cache, ok := ctx.Value(zoneCacheKey{}).(*zoneCache)
+	if !ok {
+		return list(ctx)
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if zones, ok := cache.zones.(T); ok {
+		return zones, nil
+	}
+	zones, err := list(ctx)
+	if err != nil {
+		return zones, err
+	}
+	cache.zones = zones
+	return zones, nil
+}
+
+// ZoneParallelProvider splits the changes by zone and applies the changes of up to
+// Concurrency zones at once, so that a slow zone doesn't delay the other ones.
+type ZoneParallelProvider struct {
+	Provider
+	Concurrency int
+	zones       ZoneLister
+}
+
+// NewZoneParallelProvider wraps provider, which must implement ZoneLister.
+func NewZoneParallelProvider(provider Provider, concurrency int) (*ZoneParallelProvider, error) {
+	zones, ok := provider.(ZoneLister)
+	if !ok {
+		return nil, fmt.Errorf("provider %T does not support applying the changes of zones concurrently", provider)
+	}
+	registerZoneParallelProviderMetrics.Do(func() {
+		prometheus.MustRegister(zoneApplyDuration)
+		prometheus.MustRegister(zoneApplyErrorsTotal)
+	})
+	return &ZoneParallelProvider{
+		Provider:    provider,
+		Concurrency: concurrency,
+		zones:       zones,
+	}, nil
+}
+
+// ApplyChanges applies the changes of every zone concurrently. The errors of the zones are
+// aggregated, the result is a SoftError only if the changes of all failing zones failed softly.
+// The zones are listed once, providers use CachedZones to share the listing between the zones.
+func (p *ZoneParallelProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	ctx = WithZoneCache(ctx)
+	zones, err := p.zones.ZoneIDNames(ctx)
+	if err != nil {
+		return err
+	}
+
+	perZoneChanges := SplitChangesByZone(zones, changes)
+	if len(perZoneChanges) <= 1 {
+		return p.Provider.ApplyChanges(ctx, changes)
+	}
+
+	zoneIDs := make([]string, 0, len(perZoneChanges))
+	for zoneID := range perZoneChanges {
+		zoneIDs = append(zoneIDs, zoneID)
+	}
+	sort.Strings(zoneIDs)
+
+	concurrency := p.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+
+	var (
+		wg                 sync.WaitGroup
+		mutex              sync.Mutex
+		hardErrs, softErrs []error
+	)
+	for _, zoneID := range zoneIDs {
+		wg.Add(1)
+		go func(zoneID string, changes *plan.Changes) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			zoneName := zones[zoneID]
+			if zoneName == "" {
+				zoneName = "unknown"
+			}
+			start := time.Now()
+			err := p.Provider.ApplyChanges(ctx, changes)
+			zoneApplyDuration.WithLabelValues(zoneName).Observe(time.Since(start).Seconds())
+			if err == nil {
+				return
+			}
+
+			zoneApplyErrorsTotal.WithLabelValues(zoneName).Inc()
+			err = fmt.Errorf("zone %s: %w", zoneName, err)
+			mutex.Lock()
+			defer mutex.Unlock()
+			if errors.Is(err, SoftError) {
+				softErrs = append(softErrs, err)
+			} else {
+				hardErrs = append(hardErrs, err)
+			}
+		}(zoneID, perZoneChanges[zoneID])
+	}
+	wg.Wait()
+
+	if len(hardErrs) > 0 {
+		for _, err := range softErrs {
+			log.Error(err)
+		}
+		return errors.Join(hardErrs...)
+	}
+	return errors.Join(softErrs...)
+}
+
+// SplitChangesByZone returns the changes by the ID of the zone of their DNS name. The changes
+// of DNS names outside of the zones are returned with an empty zone ID.
+func SplitChangesByZone(zones ZoneIDName, changes *plan.Changes) map[string]*plan.Changes {
+	normalized := ZoneIDName{}
+	for zoneID, zoneName := range zones {
+		normalized.Add(zoneID, strings.ToLower(strings.TrimSuffix(zoneName, ".")))
+	}
+
+	result := map[string]*plan.Changes{}
+	forZone := func(ep *endpoint.Endpoint) *plan.Changes {
+		zoneID, _ := normalized.FindZone(strings.ToLower(strings.TrimSuffix(ep.DNSName, ".")))
+		if _, ok := result[zoneID]; !ok {
+			result[zoneID] = &plan.Changes{}
+		}
+		return result[zoneID]
+	}
+	for _, ep := range changes.Create {
+		c := forZone(ep)
+		c.Create = append(c.Create, ep)
+	}
+	for _, ep := range changes.UpdateOld {
+		c := forZone(ep)
+		c.UpdateOld = append(c.UpdateOld, ep)
+	}
+	for _, ep := range changes.UpdateNew {
+		c := forZone(ep)
+		c.UpdateNew = append(c.UpdateNew, ep)
+	}
+	for _, ep := range changes.Delete {
+		c := forZone(ep)
+		c.Delete = append(c.Delete, ep)
+	}
+	return result
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+type testZoneListerProvider struct {
+	testProviderFunc
+	zones ZoneIDName
+}
+
+func (p *testZoneListerProvider) ZoneIDNames(ctx context.Context) (ZoneIDName, error) {
+	return p.zones, nil
+}
+
+func TestSplitChangesByZone(t *testing.T) {
+	zones := ZoneIDName{
+		"z1": "example.com.",
+		"z2": "sub.example.com",
+	}
+	changes := &plan.Changes{
+		Create:    []*endpoint.Endpoint{endpoint.NewEndpoint("www.example.com", endpoint.RecordTypeA, "1.2.3.4")},
+		UpdateOld: []*endpoint.Endpoint{endpoint.NewEndpoint("api.sub.example.com", endpoint.RecordTypeA, "1.2.3.4")},
+		UpdateNew: []*endpoint.Endpoint{endpoint.NewEndpoint("api.sub.example.com", endpoint.RecordTypeA, "5.6.7.8")},
+		Delete:    []*endpoint.Endpoint{endpoint.NewEndpoint("www.example.org", endpoint.RecordTypeA, "1.2.3.4")},
+	}
+
+	result := SplitChangesByZone(zones, changes)
+	require.Len(t, result, 3)
+	assert.Equal(t, &plan.Changes{Create: changes.Create}, result["z1"])
+	assert.Equal(t, &plan.Changes{UpdateOld: changes.UpdateOld, UpdateNew: changes.UpdateNew}, result["z2"])
+	assert.Equal(t, &plan.Changes{Delete: changes.Delete}, result[""])
+}
+
+func TestNewZoneParallelProvider(t *testing.T) {
+	_, err := NewZoneParallelProvider(&testProviderFunc{}, 2)
+	assert.Error(t, err)
+
+	_, err = NewZoneParallelProvider(&testZoneListerProvider{}, 2)
+	assert.NoError(t, err)
+}
+
+func TestZoneParallelProviderApplyChanges(t *testing.T) {
+	zones := ZoneIDName{"z1": "a.com", "z2": "b.com", "z3": "c.com"}
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("www.a.com", endpoint.RecordTypeA, "1.2.3.4"),
+			endpoint.NewEndpoint("www.b.com", endpoint.RecordTypeA, "1.2.3.4"),
+			endpoint.NewEndpoint("www.c.com", endpoint.RecordTypeA, "1.2.3.4"),
+		},
+	}
+
+	var running, maxRunning atomic.Int32
+	var mutex sync.Mutex
+	var applied []string
+	inner := &testZoneListerProvider{
+		zones: zones,
+		testProviderFunc: testProviderFunc{
+			applyChanges: func(ctx context.Context, changes *plan.Changes) error {
+				n := running.Add(1)
+				defer running.Add(-1)
+				for {
+					m := maxRunning.Load()
+					if n <= m || maxRunning.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+
+				require.Len(t, changes.Create, 1)
+				mutex.Lock()
+				defer mutex.Unlock()
+				applied = append(applied, changes.Create[0].DNSName)
+				return nil
+			},
+		},
+	}
+
+	p, err := NewZoneParallelProvider(inner, 2)
+	require.NoError(t, err)
+	require.NoError(t, p.ApplyChanges(context.Background(), changes))
+	assert.ElementsMatch(t, []string{"www.a.com", "www.b.com", "www.c.com"}, applied)
+	assert.Equal(t, int32(2), maxRunning.Load())
+}
+
+func TestZoneParallelProviderErrors(t *testing.T) {
+	zones := ZoneIDName{"z1": "a.com", "z2": "b.com", "z3": "c.com"}
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("www.a.com", endpoint.RecordTypeA, "1.2.3.4"),
+			endpoint.NewEndpoint("www.b.com", endpoint.RecordTypeA, "1.2.3.4"),
+			endpoint.NewEndpoint("www.c.com", endpoint.RecordTypeA, "1.2.3.4"),
+		},
+	}
+	newProvider := func(failures map[string]error) *ZoneParallelProvider {
+		p, err := NewZoneParallelProvider(&testZoneListerProvider{
+			zones: zones,
+			testProviderFunc: testProviderFunc{
+				applyChanges: func(ctx context.Context, changes *plan.Changes) error {
+					return failures[changes.Create[0].DNSName]
+				},
+			},
+		}, 3)
+		require.NoError(t, err)
+		return p
+	}
+
+	// Soft errors of all failing zones result in a soft error.
+	err := newProvider(map[string]error{
+		"www.a.com": NewSoftError(errors.New("throttled")),
+		"www.c.com": NewSoftError(errors.New("throttled")),
+	}).ApplyChanges(context.Background(), changes)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, SoftError)
+	assert.Contains(t, err.Error(), "zone a.com")
+	assert.Contains(t, err.Error(), "zone c.com")
+
+	// A hard error of any zone results in a hard error.
+	err = newProvider(map[string]error{
+		"www.a.com": NewSoftError(errors.New("throttled")),
+		"www.b.com": errors.New("invalid credentials"),
+	}).ApplyChanges(context.Background(), changes)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, SoftError)
+	assert.Contains(t, err.Error(), "zone b.com")
+}
+
+func TestCachedZones(t *testing.T) {
+	lists := 0
+	list := func(ctx context.Context) (ZoneIDName, error) {
+		lists++
+		return ZoneIDName{"z1": "a.com"}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		zones, err := CachedZones(context.Background(), list)
+		require.NoError(t, err)
+		assert.Equal(t, ZoneIDName{"z1": "a.com"}, zones)
+	}
+	assert.Equal(t, 2, lists, "the zones are listed at every call without a cache")
+
+	ctx := WithZoneCache(context.Background())
+	_, err := CachedZones(ctx, func(ctx context.Context) (ZoneIDName, error) {
+		return nil, errors.New("throttled")
+	})
+	require.Error(t, err)
+	for i := 0; i < 2; i++ {
+		zones, err := CachedZones(ctx, list)
+		require.NoError(t, err)
+		assert.Equal(t, ZoneIDName{"z1": "a.com"}, zones)
+	}
+	assert.Equal(t, 3, lists, "the zones are listed once with a cache, errors are not cached")
+}
@@ -106,6 +106,13 @@ func NewDynamoDBRegistry(provider provider.Provider, ownerID string, dynamodbAPI
 	}, nil
 }
 
+// UpdateRecordTypes changes the managed and excluded record types, and drops the cached records.
+func (im *DynamoDBRegistry) UpdateRecordTypes(managed, exclude []string) {
+	im.managedRecordTypes = managed
+	im.excludeRecordTypes = exclude
+	im.recordsCache = nil
+}
+
 func (im *DynamoDBRegistry) GetDomainFilter() endpoint.DomainFilterInterface {
 	return im.provider.GetDomainFilter()
 }
@@ -564,7 +571,7 @@ func (im *DynamoDBRegistry) removeFromCache(ep *endpoint.Endpoint) {
 	}
 
 	for i, e := range im.recordsCache {
-		if e.DNSName == ep.DNSName && e.RecordType == ep.RecordType && e.SetIdentifier == ep.SetIdentifier && e.Targets.Same(ep.Targets) {
+		if e.DNSName == ep.DNSName && e.RecordType == ep.RecordType && e.SetIdentifier == ep.SetIdentifier && e.Targets.SameAs(e.RecordType, ep.Targets) {
 			// We found a match; delete the endpoint from the cache.
 			im.recordsCache = append(im.recordsCache[:i], im.recordsCache[i+1:]...)
 			return
@@ -19,9 +19,12 @@ package registry
 import (
 	"context"
 	"errors"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 
 	"sigs.k8s.io/external-dns/endpoint"
@@ -34,6 +37,37 @@ const (
 	providerSpecificForceUpdate = "txt/force-update"
 )
 
+var (
+	txtOrphanedRecords = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "external_dns",
+			Subsystem: "registry",
+			Name:      "orphaned_txt_records",
+			Help:      "Number of TXT registry records owned by this instance without a matching managed record.",
+		},
+	)
+	txtLegacyRecords = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "external_dns",
+			Subsystem: "registry",
+			Name:      "legacy_txt_records",
+			Help:      "Number of TXT registry records owned by this instance which are named after a legacy naming scheme.",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(txtOrphanedRecords)
+	prometheus.MustRegister(txtLegacyRecords)
+}
+
+// TXTNameScheme is a prefix or suffix used to name registry TXT records.
+// Both are mutually exclusive, an empty scheme names records without any affix.
+type TXTNameScheme struct {
+	Prefix string
+	Suffix string
+}
+
 // TXTRegistry implements registry interface with ownership implemented via associated TXT records
 type TXTRegistry struct {
 	provider provider.Provider
@@ -56,10 +90,23 @@ type TXTRegistry struct {
 	// encrypt text records
 	txtEncryptEnabled bool
 	txtEncryptAESKey  []byte
+
+	// delete ownership records owned by this instance whose managed record no longer exists
+	garbageCollect bool
+	// orphaned ownership records found by the last call to Records, deleted through the plan
+	orphanedRecords []*endpoint.Endpoint
+
+	// mappers of the naming schemes previously used for ownership records, these records
+	// are read in addition to the current scheme and rewritten into the current scheme
+	legacyMappers []nameMapper
+	// only report the ownership records which would be migrated
+	migrateDryRun bool
+	// migration of ownership records found by the last call to Records, applied on the next ApplyChanges
+	pendingMigration *plan.Changes
 }
 
 // NewTXTRegistry returns new TXTRegistry object
-func NewTXTRegistry(provider provider.Provider, txtPrefix, txtSuffix, ownerID string, cacheInterval time.Duration, txtWildcardReplacement string, managedRecordTypes, excludeRecordTypes []string, txtEncryptEnabled bool, txtEncryptAESKey []byte) (*TXTRegistry, error) {
+func NewTXTRegistry(provider provider.Provider, txtPrefix, txtSuffix, ownerID string, cacheInterval time.Duration, txtWildcardReplacement string, managedRecordTypes, excludeRecordTypes []string, txtEncryptEnabled bool, txtEncryptAESKey []byte, garbageCollect bool, legacySchemes []TXTNameScheme, migrateDryRun bool) (*TXTRegistry, error) {
 	if ownerID == "" {
 		return nil, errors.New("owner id cannot be empty")
 	}
@@ -78,6 +125,17 @@ func NewTXTRegistry(provider provider.Provider, txtPrefix, txtSuffix, ownerID st
 
 	mapper := newaffixNameMapper(txtPrefix, txtSuffix, txtWildcardReplacement)
 
+	legacyMappers := make([]nameMapper, 0, len(legacySchemes))
+	for _, scheme := range legacySchemes {
+		if len(scheme.Prefix) > 0 && len(scheme.Suffix) > 0 {
+			return nil, errors.New("prefix and suffix of a legacy txt naming scheme are mutual exclusive")
+		}
+		if strings.EqualFold(scheme.Prefix, txtPrefix) && strings.EqualFold(scheme.Suffix, txtSuffix) {
+			continue
+		}
+		legacyMappers = append(legacyMappers, newaffixNameMapper(scheme.Prefix, scheme.Suffix, txtWildcardReplacement))
+	}
+
 	return &TXTRegistry{
 		provider:            provider,
 		ownerID:             ownerID,
@@ -88,6 +146,9 @@ func NewTXTRegistry(provider provider.Provider, txtPrefix, txtSuffix, ownerID st
 		excludeRecordTypes:  excludeRecordTypes,
 		txtEncryptEnabled:   txtEncryptEnabled,
 		txtEncryptAESKey:    txtEncryptAESKey,
+		garbageCollect:      garbageCollect,
+		legacyMappers:       legacyMappers,
+		migrateDryRun:       migrateDryRun,
 	}, nil
 }
 
@@ -99,6 +160,13 @@ func (im *TXTRegistry) GetDomainFilter() endpoint.DomainFilterInterface {
 	return im.provider.GetDomainFilter()
 }
 
+// UpdateRecordTypes changes the managed and excluded record types, and drops the cached records.
+func (im *TXTRegistry) UpdateRecordTypes(managed, exclude []string) {
+	im.managedRecordTypes = managed
+	im.excludeRecordTypes = exclude
+	im.recordsCache = nil
+}
+
 func (im *TXTRegistry) OwnerID() string {
 	return im.ownerID
 }
@@ -123,6 +191,8 @@ func (im *TXTRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error
 
 	labelMap := map[endpoint.EndpointKey]endpoint.Labels{}
 	txtRecordsMap := map[string]struct{}{}
+	ownedTXTRecords := map[*endpoint.Endpoint]endpoint.Labels{}
+	legacyTXTRecordsMap := map[endpoint.EndpointKey][]legacyTXTRecord{}
 
 	for _, record := range records {
 		if record.RecordType != endpoint.RecordTypeTXT {
@@ -150,8 +220,32 @@ func (im *TXTRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error
 		}
 		labelMap[key] = labels
 		txtRecordsMap[record.DNSName] = struct{}{}
+		if labels[endpoint.OwnerLabelKey] == im.ownerID {
+			ownedTXTRecords[record] = labels
+		}
+
+		for _, legacyMapper := range im.legacyMappers {
+			legacyName, legacyRecordType := legacyMapper.toEndpointName(record.DNSName)
+			if legacyName == "" {
+				continue
+			}
+			legacyKey := endpoint.EndpointKey{
+				DNSName:       legacyName,
+				RecordType:    legacyRecordType,
+				SetIdentifier: record.SetIdentifier,
+			}
+			legacyTXTRecordsMap[legacyKey] = appendLegacyTXTRecord(legacyTXTRecordsMap[legacyKey], legacyTXTRecord{record: record, labels: labels})
+		}
 	}
 
+	// legacyManagedRecords holds the TXT records of a legacy naming scheme which have a companion record
+	legacyManagedRecords := map[*endpoint.Endpoint]struct{}{}
+	migration := &plan.Changes{}
+
+	// managedTXTNames holds the names of the ownership records of all records, whatever their type,
+	// TXT records owned by this instance which are not in there are orphaned.
+	managedTXTNames := map[txtRecordKey]struct{}{}
+
 	for _, ep := range endpoints {
 		if ep.Labels == nil {
 			ep.Labels = endpoint.NewLabels()
@@ -173,18 +267,49 @@ func (im *TXTRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error
 			key.RecordType = endpoint.RecordTypeCNAME
 		}
 
+		managedTXTNames[txtRecordKey{dnsName: strings.ToLower(im.mapper.toNewTXTName(ep.DNSName, key.RecordType)), setIdentifier: ep.SetIdentifier}] = struct{}{}
+		if ep.RecordType != endpoint.RecordTypeAAAA {
+			managedTXTNames[txtRecordKey{dnsName: strings.ToLower(im.mapper.toTXTName(ep.DNSName)), setIdentifier: ep.SetIdentifier}] = struct{}{}
+		}
+
+		// Lookup ownership records of legacy naming schemes
+		var legacyRecords []legacyTXTRecord
+		if len(legacyTXTRecordsMap) > 0 {
+			legacyRecords = legacyTXTRecordsMap[key]
+			if ep.RecordType != endpoint.RecordTypeAAAA {
+				untypedKey := key
+				untypedKey.RecordType = ""
+				for _, legacy := range legacyTXTRecordsMap[untypedKey] {
+					legacyRecords = appendLegacyTXTRecord(legacyRecords, legacy)
+				}
+			}
+		}
+
 		// Handle both new and old registry format with the preference for the new one
 		labels, labelsExist := labelMap[key]
 		if !labelsExist && ep.RecordType != endpoint.RecordTypeAAAA {
 			key.RecordType = ""
 			labels, labelsExist = labelMap[key]
 		}
+		if !labelsExist && len(legacyRecords) > 0 {
+			labels, labelsExist = legacyRecords[0].labels, true
+		}
 		if labelsExist {
 			for k, v := range labels {
 				ep.Labels[k] = v
 			}
 		}
 
+		for _, legacy := range legacyRecords {
+			legacyManagedRecords[legacy.record] = struct{}{}
+		}
+
+		// Handle the migration of TXT records named after a legacy naming scheme.
+		// The migration is done for the TXT records owned by this instance only.
+		if im.migrateLegacyRecords(ep, legacyRecords, txtRecordsMap, migration) {
+			continue
+		}
+
 		// Handle the migration of TXT records created before the new format (introduced in v0.12.0).
 		// The migration is done for the TXT records owned by this instance only.
 		if len(txtRecordsMap) > 0 && ep.Labels[endpoint.OwnerLabelKey] == im.ownerID {
@@ -200,6 +325,42 @@ func (im *TXTRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error
 		}
 	}
 
+	txtLegacyRecords.Set(float64(len(migration.Delete)))
+	if migration.HasChanges() {
+		for _, r := range migration.Delete {
+			if im.migrateDryRun {
+				log.Infof("Would migrate TXT registry record %s to the current naming scheme", r.DNSName)
+			} else {
+				log.Debugf("Found TXT registry record %s named after a legacy naming scheme", r.DNSName)
+			}
+		}
+		im.pendingMigration = migration
+	} else {
+		im.pendingMigration = nil
+	}
+
+	orphanedRecords := []*endpoint.Endpoint{}
+	for owned, labels := range ownedTXTRecords {
+		if _, found := legacyManagedRecords[owned]; found {
+			continue
+		}
+		if _, found := managedTXTNames[txtRecordKey{dnsName: strings.ToLower(owned.DNSName), setIdentifier: owned.SetIdentifier}]; !found {
+			log.Debugf("Found orphaned TXT registry record %s", owned)
+			// the labels of the record let the plan apply the ownership and the policies to its deletion
+			orphan := owned.DeepCopy()
+			orphan.Labels = endpoint.NewLabels()
+			for k, v := range labels {
+				orphan.Labels[k] = v
+			}
+			orphanedRecords = append(orphanedRecords, orphan)
+		}
+	}
+	sort.Slice(orphanedRecords, func(i, j int) bool { return orphanedRecords[i].DNSName < orphanedRecords[j].DNSName })
+	txtOrphanedRecords.Set(float64(len(orphanedRecords)))
+	if im.garbageCollect {
+		im.orphanedRecords = orphanedRecords
+	}
+
 	// Update the cache.
 	if im.cacheInterval > 0 {
 		im.recordsCache = endpoints
@@ -250,6 +411,19 @@ func (im *TXTRegistry) ApplyChanges(ctx context.Context, changes *plan.Changes)
 		UpdateOld: endpoint.FilterEndpointsByOwnerID(im.ownerID, changes.UpdateOld),
 		Delete:    endpoint.FilterEndpointsByOwnerID(im.ownerID, changes.Delete),
 	}
+	// the orphaned ownership records planned for deletion are deleted as they are
+	var orphans []*endpoint.Endpoint
+	if len(im.orphanedRecords) > 0 {
+		deletes := []*endpoint.Endpoint{}
+		for _, r := range filteredChanges.Delete {
+			if im.isOrphanedRecord(r) {
+				orphans = append(orphans, r)
+			} else {
+				deletes = append(deletes, r)
+			}
+		}
+		filteredChanges.Delete = deletes
+	}
 	for _, r := range filteredChanges.Create {
 		if r.Labels == nil {
 			r.Labels = make(map[string]string)
@@ -294,6 +468,14 @@ func (im *TXTRegistry) ApplyChanges(ctx context.Context, changes *plan.Changes)
 		}
 	}
 
+	if im.pendingMigration != nil && !im.migrateDryRun {
+		im.applyMigration(filteredChanges)
+	}
+
+	if len(orphans) > 0 {
+		filteredChanges.Delete = append(filteredChanges.Delete, im.collectOrphanedRecords(orphans, filteredChanges.Create)...)
+	}
+
 	// when caching is enabled, disable the provider from using the cache
 	if im.cacheInterval > 0 {
 		ctx = context.WithValue(ctx, provider.RecordsContextKey, nil)
@@ -301,11 +483,173 @@ func (im *TXTRegistry) ApplyChanges(ctx context.Context, changes *plan.Changes)
 	return im.provider.ApplyChanges(ctx, filteredChanges)
 }
 
+// HasPendingChanges returns true if the registry has TXT records to migrate to the current naming
+// scheme, even when the plan itself has no changes.
+func (im *TXTRegistry) HasPendingChanges() bool {
+	return im.pendingMigration != nil && !im.migrateDryRun
+}
+
+// OrphanedRecords returns the orphaned TXT records found by the last call to Records when the garbage
+// collection is enabled, with the labels they hold. They are deleted like the other records, through
+// the changes of the plan and under its policies.
+func (im *TXTRegistry) OrphanedRecords() []*endpoint.Endpoint {
+	return im.orphanedRecords
+}
+
+// PendingChanges returns the changes of the ownership records the registry adds to the next changes
+// on its own, the migration of the records named after a legacy naming scheme, as found by the last
+// call to Records.
+func (im *TXTRegistry) PendingChanges() *plan.Changes {
+	changes := &plan.Changes{}
+	if im.pendingMigration != nil && !im.migrateDryRun {
+		changes.Create = append(changes.Create, im.pendingMigration.Create...)
+		changes.Delete = append(changes.Delete, im.pendingMigration.Delete...)
+	}
+	return changes
+}
+
+// migrateLegacyRecords adds the changes needed to rewrite the legacy ownership records of
+// an endpoint owned by this instance into the current naming scheme to the migration.
+// It returns true if the endpoint is migrated.
+func (im *TXTRegistry) migrateLegacyRecords(ep *endpoint.Endpoint, legacyRecords []legacyTXTRecord, txtRecordsMap map[string]struct{}, migration *plan.Changes) bool {
+	if len(legacyRecords) == 0 || ep.Labels[endpoint.OwnerLabelKey] != im.ownerID {
+		return false
+	}
+	if !plan.IsManagedRecord(ep.RecordType, im.managedRecordTypes, im.excludeRecordTypes) {
+		return false
+	}
+
+	desiredTXTs := im.generateTXTRecord(ep)
+	desiredNames := map[string]struct{}{}
+	for _, desiredTXT := range desiredTXTs {
+		desiredNames[strings.ToLower(desiredTXT.DNSName)] = struct{}{}
+	}
+
+	migrated := false
+	for _, legacy := range legacyRecords {
+		if legacy.labels[endpoint.OwnerLabelKey] != im.ownerID {
+			continue
+		}
+		// the record is named after the current naming scheme as well
+		if _, found := desiredNames[strings.ToLower(legacy.record.DNSName)]; found {
+			continue
+		}
+		migration.Delete = appendUniqueEndpoint(migration.Delete, legacy.record)
+		migrated = true
+	}
+	if !migrated {
+		return false
+	}
+
+	for _, desiredTXT := range desiredTXTs {
+		if _, exists := txtRecordsMap[desiredTXT.DNSName]; !exists {
+			migration.Create = append(migration.Create, desiredTXT)
+		}
+	}
+	return true
+}
+
+// applyMigration adds the pending migration of legacy ownership records to the changes and resets it.
+// Ownership records which are already part of the changes are left alone.
+func (im *TXTRegistry) applyMigration(changes *plan.Changes) {
+	planned := map[endpoint.EndpointKey]struct{}{}
+	for _, eps := range [][]*endpoint.Endpoint{changes.Create, changes.UpdateOld, changes.UpdateNew, changes.Delete} {
+		for _, r := range eps {
+			if r.RecordType == endpoint.RecordTypeTXT {
+				planned[endpoint.EndpointKey{DNSName: strings.ToLower(r.DNSName), SetIdentifier: r.SetIdentifier}] = struct{}{}
+			}
+		}
+	}
+
+	for _, r := range im.pendingMigration.Create {
+		if _, found := planned[endpoint.EndpointKey{DNSName: strings.ToLower(r.DNSName), SetIdentifier: r.SetIdentifier}]; !found {
+			log.Infof("Migrating TXT registry record %s to the current naming scheme", r.Labels[endpoint.OwnedRecordLabelKey])
+			changes.Create = append(changes.Create, r)
+		}
+	}
+	for _, r := range im.pendingMigration.Delete {
+		if _, found := planned[endpoint.EndpointKey{DNSName: strings.ToLower(r.DNSName), SetIdentifier: r.SetIdentifier}]; !found {
+			log.Infof("Deleting TXT registry record %s named after a legacy naming scheme", r.DNSName)
+			changes.Delete = append(changes.Delete, r)
+		}
+	}
+	im.pendingMigration = nil
+}
+
+// isOrphanedRecord returns whether r is one of the orphaned TXT records found by the last call to Records.
+func (im *TXTRegistry) isOrphanedRecord(r *endpoint.Endpoint) bool {
+	if r.RecordType != endpoint.RecordTypeTXT {
+		return false
+	}
+	for _, orphan := range im.orphanedRecords {
+		if strings.EqualFold(orphan.DNSName, r.DNSName) && orphan.SetIdentifier == r.SetIdentifier {
+			return true
+		}
+	}
+	return false
+}
+
+// collectOrphanedRecords returns the orphaned TXT records to delete, skipping the ones which are about
+// to be created again, and forgets them.
+func (im *TXTRegistry) collectOrphanedRecords(orphans, creates []*endpoint.Endpoint) []*endpoint.Endpoint {
+	created := map[endpoint.EndpointKey]struct{}{}
+	for _, r := range creates {
+		if r.RecordType == endpoint.RecordTypeTXT {
+			created[endpoint.EndpointKey{DNSName: strings.ToLower(r.DNSName), SetIdentifier: r.SetIdentifier}] = struct{}{}
+		}
+	}
+
+	deletes := []*endpoint.Endpoint{}
+	for _, r := range orphans {
+		if _, found := created[endpoint.EndpointKey{DNSName: strings.ToLower(r.DNSName), SetIdentifier: r.SetIdentifier}]; !found {
+			log.Infof("Deleting orphaned TXT registry record %s", r)
+			deletes = append(deletes, r)
+		}
+	}
+	im.orphanedRecords = slices.DeleteFunc(im.orphanedRecords, func(orphan *endpoint.Endpoint) bool {
+		return slices.ContainsFunc(orphans, func(r *endpoint.Endpoint) bool {
+			return strings.EqualFold(orphan.DNSName, r.DNSName) && orphan.SetIdentifier == r.SetIdentifier
+		})
+	})
+
+	return deletes
+}
+
 // AdjustEndpoints modifies the endpoints as needed by the specific provider
 func (im *TXTRegistry) AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]*endpoint.Endpoint, error) {
 	return im.provider.AdjustEndpoints(endpoints)
 }
 
+// txtRecordKey identifies a TXT registry record by its name and set identifier.
+type txtRecordKey struct {
+	dnsName       string
+	setIdentifier string
+}
+
+// legacyTXTRecord is a TXT registry record named after a legacy naming scheme along with its labels.
+type legacyTXTRecord struct {
+	record *endpoint.Endpoint
+	labels endpoint.Labels
+}
+
+func appendLegacyTXTRecord(records []legacyTXTRecord, r legacyTXTRecord) []legacyTXTRecord {
+	for _, existing := range records {
+		if existing.record == r.record {
+			return records
+		}
+	}
+	return append(records, r)
+}
+
+func appendUniqueEndpoint(eps []*endpoint.Endpoint, ep *endpoint.Endpoint) []*endpoint.Endpoint {
+	for _, existing := range eps {
+		if existing == ep {
+			return eps
+		}
+	}
+	return append(eps, ep)
+}
+
 /**
   nameMapper is the interface for mapping between the endpoint for the source
   and the endpoint for the TXT record.
@@ -483,7 +827,7 @@ func (im *TXTRegistry) removeFromCache(ep *endpoint.Endpoint) {
 	}
 
 	for i, e := range im.recordsCache {
-		if e.DNSName == ep.DNSName && e.RecordType == ep.RecordType && e.SetIdentifier == ep.SetIdentifier && e.Targets.Same(ep.Targets) {
+		if e.DNSName == ep.DNSName && e.RecordType == ep.RecordType && e.SetIdentifier == ep.SetIdentifier && e.Targets.SameAs(e.RecordType, ep.Targets) {
 			// We found a match delete the endpoint from the cache.
 			im.recordsCache = append(im.recordsCache[:i], im.recordsCache[i+1:]...)
 			return
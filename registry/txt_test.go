@@ -42,42 +42,51 @@ func TestTXTRegistry(t *testing.T) {
 	t.Run("TestRecords", testTXTRegistryRecords)
 	t.Run("TestApplyChanges", testTXTRegistryApplyChanges)
 	t.Run("TestMissingRecords", testTXTRegistryMissingRecords)
+	t.Run("TestGarbageCollect", testTXTRegistryGarbageCollect)
+	t.Run("TestMigrateLegacySchemes", testTXTRegistryMigrateLegacySchemes)
 }
 
 func testTXTRegistryNew(t *testing.T) {
 	p := inmemory.NewInMemoryProvider()
-	_, err := NewTXTRegistry(p, "txt", "", "", time.Hour, "", []string{}, []string{}, false, nil)
+	_, err := NewTXTRegistry(p, "txt", "", "", time.Hour, "", []string{}, []string{}, false, nil, false, nil, false)
 	require.Error(t, err)
 
-	_, err = NewTXTRegistry(p, "", "txt", "", time.Hour, "", []string{}, []string{}, false, nil)
+	_, err = NewTXTRegistry(p, "", "txt", "", time.Hour, "", []string{}, []string{}, false, nil, false, nil, false)
 	require.Error(t, err)
 
-	r, err := NewTXTRegistry(p, "txt", "", "owner", time.Hour, "", []string{}, []string{}, false, nil)
+	r, err := NewTXTRegistry(p, "txt", "", "owner", time.Hour, "", []string{}, []string{}, false, nil, false, nil, false)
 	require.NoError(t, err)
 	assert.Equal(t, p, r.provider)
 
-	r, err = NewTXTRegistry(p, "", "txt", "owner", time.Hour, "", []string{}, []string{}, false, nil)
+	r, err = NewTXTRegistry(p, "", "txt", "owner", time.Hour, "", []string{}, []string{}, false, nil, false, nil, false)
 	require.NoError(t, err)
 
-	_, err = NewTXTRegistry(p, "txt", "txt", "owner", time.Hour, "", []string{}, []string{}, false, nil)
+	_, err = NewTXTRegistry(p, "txt", "txt", "owner", time.Hour, "", []string{}, []string{}, false, nil, false, nil, false)
 	require.Error(t, err)
 
+	_, err = NewTXTRegistry(p, "txt", "", "owner", time.Hour, "", []string{}, []string{}, false, nil, false, []TXTNameScheme{{Prefix: "txt", Suffix: "txt"}}, false)
+	require.Error(t, err)
+
+	r, err = NewTXTRegistry(p, "txt", "", "owner", time.Hour, "", []string{}, []string{}, false, nil, false, []TXTNameScheme{{Prefix: "TXT"}, {}, {Suffix: "-txt"}}, false)
+	require.NoError(t, err)
+	assert.Len(t, r.legacyMappers, 2)
+
 	_, ok := r.mapper.(affixNameMapper)
 	require.True(t, ok)
 	assert.Equal(t, "owner", r.ownerID)
 	assert.Equal(t, p, r.provider)
 
 	aesKey := []byte(";k&l)nUC/33:{?d{3)54+,AD?]SX%yh^")
-	_, err = NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{}, []string{}, false, nil)
+	_, err = NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{}, []string{}, false, nil, false, nil, false)
 	require.NoError(t, err)
 
-	_, err = NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{}, []string{}, false, aesKey)
+	_, err = NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{}, []string{}, false, aesKey, false, nil, false)
 	require.NoError(t, err)
 
-	_, err = NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{}, []string{}, true, nil)
+	_, err = NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{}, []string{}, true, nil, false, nil, false)
 	require.Error(t, err)
 
-	r, err = NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{}, []string{}, true, aesKey)
+	r, err = NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{}, []string{}, true, aesKey, false, nil, false)
 	require.NoError(t, err)
 
 	_, ok = r.mapper.(affixNameMapper)
@@ -215,13 +224,13 @@ func testTXTRegistryRecordsPrefixed(t *testing.T) {
 		},
 	}
 
-	r, _ := NewTXTRegistry(p, "txt.", "", "owner", time.Hour, "wc", []string{}, []string{}, false, nil)
+	r, _ := NewTXTRegistry(p, "txt.", "", "owner", time.Hour, "wc", []string{}, []string{}, false, nil, false, nil, false)
 	records, _ := r.Records(ctx)
 
 	assert.True(t, testutils.SameEndpoints(records, expectedRecords))
 
 	// Ensure prefix is case-insensitive
-	r, _ = NewTXTRegistry(p, "TxT.", "", "owner", time.Hour, "wc", []string{}, []string{}, false, nil)
+	r, _ = NewTXTRegistry(p, "TxT.", "", "owner", time.Hour, "wc", []string{}, []string{}, false, nil, false, nil, false)
 	records, _ = r.Records(ctx)
 
 	assert.True(t, testutils.SameEndpoints(records, expectedRecords))
@@ -340,13 +349,13 @@ func testTXTRegistryRecordsSuffixed(t *testing.T) {
 		},
 	}
 
-	r, _ := NewTXTRegistry(p, "", "-txt", "owner", time.Hour, "", []string{}, []string{}, false, nil)
+	r, _ := NewTXTRegistry(p, "", "-txt", "owner", time.Hour, "", []string{}, []string{}, false, nil, false, nil, false)
 	records, _ := r.Records(ctx)
 
 	assert.True(t, testutils.SameEndpoints(records, expectedRecords))
 
 	// Ensure prefix is case-insensitive
-	r, _ = NewTXTRegistry(p, "", "-TxT", "owner", time.Hour, "", []string{}, []string{}, false, nil)
+	r, _ = NewTXTRegistry(p, "", "-TxT", "owner", time.Hour, "", []string{}, []string{}, false, nil, false, nil, false)
 	records, _ = r.Records(ctx)
 
 	assert.True(t, testutils.SameEndpointLabels(records, expectedRecords))
@@ -457,7 +466,7 @@ func testTXTRegistryRecordsNoPrefix(t *testing.T) {
 		},
 	}
 
-	r, _ := NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{}, []string{}, false, nil)
+	r, _ := NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{}, []string{}, false, nil, false, nil, false)
 	records, _ := r.Records(ctx)
 
 	assert.True(t, testutils.SameEndpoints(records, expectedRecords))
@@ -484,12 +493,12 @@ func testTXTRegistryRecordsPrefixedTemplated(t *testing.T) {
 		},
 	}
 
-	r, _ := NewTXTRegistry(p, "txt-%{record_type}.", "", "owner", time.Hour, "wc", []string{}, []string{}, false, nil)
+	r, _ := NewTXTRegistry(p, "txt-%{record_type}.", "", "owner", time.Hour, "wc", []string{}, []string{}, false, nil, false, nil, false)
 	records, _ := r.Records(ctx)
 
 	assert.True(t, testutils.SameEndpoints(records, expectedRecords))
 
-	r, _ = NewTXTRegistry(p, "TxT-%{record_type}.", "", "owner", time.Hour, "wc", []string{}, []string{}, false, nil)
+	r, _ = NewTXTRegistry(p, "TxT-%{record_type}.", "", "owner", time.Hour, "wc", []string{}, []string{}, false, nil, false, nil, false)
 	records, _ = r.Records(ctx)
 
 	assert.True(t, testutils.SameEndpoints(records, expectedRecords))
@@ -516,12 +525,12 @@ func testTXTRegistryRecordsSuffixedTemplated(t *testing.T) {
 		},
 	}
 
-	r, _ := NewTXTRegistry(p, "", "txt%{record_type}", "owner", time.Hour, "wc", []string{}, []string{}, false, nil)
+	r, _ := NewTXTRegistry(p, "", "txt%{record_type}", "owner", time.Hour, "wc", []string{}, []string{}, false, nil, false, nil, false)
 	records, _ := r.Records(ctx)
 
 	assert.True(t, testutils.SameEndpoints(records, expectedRecords))
 
-	r, _ = NewTXTRegistry(p, "", "TxT%{record_type}", "owner", time.Hour, "wc", []string{}, []string{}, false, nil)
+	r, _ = NewTXTRegistry(p, "", "TxT%{record_type}", "owner", time.Hour, "wc", []string{}, []string{}, false, nil, false, nil, false)
 	records, _ = r.Records(ctx)
 
 	assert.True(t, testutils.SameEndpoints(records, expectedRecords))
@@ -564,7 +573,7 @@ func testTXTRegistryApplyChangesWithPrefix(t *testing.T) {
 			newEndpointWithOwner("txt.cname-multiple.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, "").WithSetIdentifier("test-set-2"),
 		},
 	})
-	r, _ := NewTXTRegistry(p, "txt.", "", "owner", time.Hour, "", []string{}, []string{}, false, nil)
+	r, _ := NewTXTRegistry(p, "txt.", "", "owner", time.Hour, "", []string{}, []string{}, false, nil, false, nil, false)
 
 	changes := &plan.Changes{
 		Create: []*endpoint.Endpoint{
@@ -653,7 +662,7 @@ func testTXTRegistryApplyChangesWithTemplatedPrefix(t *testing.T) {
 	p.ApplyChanges(ctx, &plan.Changes{
 		Create: []*endpoint.Endpoint{},
 	})
-	r, _ := NewTXTRegistry(p, "prefix%{record_type}.", "", "owner", time.Hour, "", []string{}, []string{}, false, nil)
+	r, _ := NewTXTRegistry(p, "prefix%{record_type}.", "", "owner", time.Hour, "", []string{}, []string{}, false, nil, false, nil, false)
 	changes := &plan.Changes{
 		Create: []*endpoint.Endpoint{
 			newEndpointWithOwnerResource("new-record-1.test-zone.example.org", "new-loadbalancer-1.lb.com", endpoint.RecordTypeCNAME, "", "ingress/default/my-ingress"),
@@ -696,7 +705,7 @@ func testTXTRegistryApplyChangesWithTemplatedSuffix(t *testing.T) {
 	p.OnApplyChanges = func(ctx context.Context, got *plan.Changes) {
 		assert.Equal(t, ctxEndpoints, ctx.Value(provider.RecordsContextKey))
 	}
-	r, _ := NewTXTRegistry(p, "", "-%{record_type}suffix", "owner", time.Hour, "", []string{}, []string{}, false, nil)
+	r, _ := NewTXTRegistry(p, "", "-%{record_type}suffix", "owner", time.Hour, "", []string{}, []string{}, false, nil, false, nil, false)
 	changes := &plan.Changes{
 		Create: []*endpoint.Endpoint{
 			newEndpointWithOwnerResource("new-record-1.test-zone.example.org", "new-loadbalancer-1.lb.com", endpoint.RecordTypeCNAME, "", "ingress/default/my-ingress"),
@@ -761,7 +770,7 @@ func testTXTRegistryApplyChangesWithSuffix(t *testing.T) {
 			newEndpointWithOwner("cname-multiple-txt.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, "").WithSetIdentifier("test-set-2"),
 		},
 	})
-	r, _ := NewTXTRegistry(p, "", "-txt", "owner", time.Hour, "wildcard", []string{}, []string{}, false, nil)
+	r, _ := NewTXTRegistry(p, "", "-txt", "owner", time.Hour, "wildcard", []string{}, []string{}, false, nil, false, nil, false)
 
 	changes := &plan.Changes{
 		Create: []*endpoint.Endpoint{
@@ -865,7 +874,7 @@ func testTXTRegistryApplyChangesNoPrefix(t *testing.T) {
 			newEndpointWithOwner("cname-foobar.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
 		},
 	})
-	r, _ := NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{}, []string{}, false, nil)
+	r, _ := NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{}, []string{}, false, nil, false, nil, false)
 
 	changes := &plan.Changes{
 		Create: []*endpoint.Endpoint{
@@ -1028,7 +1037,7 @@ func testTXTRegistryMissingRecordsNoPrefix(t *testing.T) {
 		},
 	}
 
-	r, _ := NewTXTRegistry(p, "", "", "owner", time.Hour, "wc", []string{endpoint.RecordTypeCNAME, endpoint.RecordTypeA, endpoint.RecordTypeNS}, []string{}, false, nil)
+	r, _ := NewTXTRegistry(p, "", "", "owner", time.Hour, "wc", []string{endpoint.RecordTypeCNAME, endpoint.RecordTypeA, endpoint.RecordTypeNS}, []string{}, false, nil, false, nil, false)
 	records, _ := r.Records(ctx)
 
 	assert.True(t, testutils.SameEndpoints(records, expectedRecords))
@@ -1138,7 +1147,7 @@ func testTXTRegistryMissingRecordsWithPrefix(t *testing.T) {
 		},
 	}
 
-	r, _ := NewTXTRegistry(p, "txt.", "", "owner", time.Hour, "wc", []string{endpoint.RecordTypeCNAME, endpoint.RecordTypeA, endpoint.RecordTypeNS, endpoint.RecordTypeTXT}, []string{}, false, nil)
+	r, _ := NewTXTRegistry(p, "txt.", "", "owner", time.Hour, "wc", []string{endpoint.RecordTypeCNAME, endpoint.RecordTypeA, endpoint.RecordTypeNS, endpoint.RecordTypeTXT}, []string{}, false, nil, false, nil, false)
 	records, _ := r.Records(ctx)
 
 	assert.True(t, testutils.SameEndpoints(records, expectedRecords))
@@ -1433,7 +1442,7 @@ func TestNewTXTScheme(t *testing.T) {
 			newEndpointWithOwner("cname-foobar.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
 		},
 	})
-	r, _ := NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{}, []string{}, false, nil)
+	r, _ := NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{}, []string{}, false, nil, false, nil, false)
 
 	changes := &plan.Changes{
 		Create: []*endpoint.Endpoint{
@@ -1509,7 +1518,7 @@ func TestGenerateTXT(t *testing.T) {
 	}
 	p := inmemory.NewInMemoryProvider()
 	p.CreateZone(testZone)
-	r, _ := NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{}, []string{}, false, nil)
+	r, _ := NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{}, []string{}, false, nil, false, nil, false)
 	gotTXT := r.generateTXTRecord(record)
 	assert.Equal(t, expectedTXT, gotTXT)
 }
@@ -1528,11 +1537,24 @@ func TestGenerateTXTForAAAA(t *testing.T) {
 	}
 	p := inmemory.NewInMemoryProvider()
 	p.CreateZone(testZone)
-	r, _ := NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{}, []string{}, false, nil)
+	r, _ := NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{}, []string{}, false, nil, false, nil, false)
 	gotTXT := r.generateTXTRecord(record)
 	assert.Equal(t, expectedTXT, gotTXT)
 }
 
+func TestGenerateTXTWithoutAccessLabel(t *testing.T) {
+	record := newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner")
+	record.Labels[endpoint.AccessLabelKey] = endpoint.AccessPrivate
+	p := inmemory.NewInMemoryProvider()
+	p.CreateZone(testZone)
+	r, _ := NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{}, []string{}, false, nil, false, nil, false)
+	gotTXT := r.generateTXTRecord(record)
+	require.Len(t, gotTXT, 2)
+	for _, txt := range gotTXT {
+		assert.Equal(t, endpoint.Targets{"\"heritage=external-dns,external-dns/owner=owner\""}, txt.Targets, "the access label must not be stored")
+	}
+}
+
 func TestFailGenerateTXT(t *testing.T) {
 
 	cnameRecord := &endpoint.Endpoint{
@@ -1545,7 +1567,7 @@ func TestFailGenerateTXT(t *testing.T) {
 	expectedTXT := []*endpoint.Endpoint{}
 	p := inmemory.NewInMemoryProvider()
 	p.CreateZone(testZone)
-	r, _ := NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{}, []string{}, false, nil)
+	r, _ := NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{}, []string{}, false, nil, false, nil, false)
 	gotTXT := r.generateTXTRecord(cnameRecord)
 	assert.Equal(t, expectedTXT, gotTXT)
 }
@@ -1563,7 +1585,7 @@ func TestTXTRegistryApplyChangesEncrypt(t *testing.T) {
 		},
 	})
 
-	r, _ := NewTXTRegistry(p, "txt.", "", "owner", time.Hour, "", []string{}, []string{}, true, []byte("12345678901234567890123456789012"))
+	r, _ := NewTXTRegistry(p, "txt.", "", "owner", time.Hour, "", []string{}, []string{}, true, []byte("12345678901234567890123456789012"), false, nil, false)
 	records, _ := r.Records(ctx)
 	changes := &plan.Changes{
 		Delete: records,
@@ -1609,7 +1631,7 @@ func TestMultiClusterDifferentRecordTypeOwnership(t *testing.T) {
 		},
 	})
 
-	r, _ := NewTXTRegistry(p, "_owner.", "", "bar", time.Hour, "", []string{}, []string{}, false, nil)
+	r, _ := NewTXTRegistry(p, "_owner.", "", "bar", time.Hour, "", []string{}, []string{}, false, nil, false, nil, false)
 	records, _ := r.Records(ctx)
 
 	// new cluster has same ingress host as other cluster and uses CNAME ingress address
@@ -1653,12 +1675,262 @@ func TestMultiClusterDifferentRecordTypeOwnership(t *testing.T) {
 	}
 }
 
+func testTXTRegistryGarbageCollect(t *testing.T) {
+	managedTXTRecords := []string{
+		"txt.foo.test-zone.example.org",
+		"txt.a-foo.test-zone.example.org",
+		"txt.cname-wc.wildcard.test-zone.example.org",
+		"txt.aaaa-dualstack.test-zone.example.org",
+		"txt.mail.test-zone.example.org",
+		"txt.mx-mail.test-zone.example.org",
+		"txt.srv-_sip._tcp.test-zone.example.org",
+		"txt.cname-other.test-zone.example.org",
+	}
+	orphanedTXTRecords := []string{
+		"txt.gone.test-zone.example.org",
+		"txt.cname-gone.test-zone.example.org",
+		"txt.mx-gone.test-zone.example.org",
+		"txt.dualstack.test-zone.example.org",
+		"cname-stale.test-zone.example.org",
+	}
+
+	tests := []struct {
+		name               string
+		garbageCollect     bool
+		policy             plan.Policy
+		expectedOrphans    int
+		expectedTXTRecords []string
+	}{
+		{
+			name:               "orphaned records are deleted",
+			garbageCollect:     true,
+			policy:             &plan.SyncPolicy{},
+			expectedOrphans:    len(orphanedTXTRecords),
+			expectedTXTRecords: managedTXTRecords,
+		},
+		{
+			name:               "orphaned records are kept without garbage collection",
+			garbageCollect:     false,
+			policy:             &plan.SyncPolicy{},
+			expectedOrphans:    0,
+			expectedTXTRecords: append(append([]string{}, managedTXTRecords...), orphanedTXTRecords...),
+		},
+		{
+			name:               "orphaned records are kept under the upsert-only policy",
+			garbageCollect:     true,
+			policy:             &plan.UpsertOnlyPolicy{},
+			expectedOrphans:    len(orphanedTXTRecords),
+			expectedTXTRecords: append(append([]string{}, managedTXTRecords...), orphanedTXTRecords...),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			p := inmemory.NewInMemoryProvider()
+			p.CreateZone(testZone)
+			p.ApplyChanges(ctx, &plan.Changes{
+				Create: []*endpoint.Endpoint{
+					newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+					newEndpointWithOwner("txt.foo.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+					newEndpointWithOwner("txt.a-foo.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+					newEndpointWithOwner("*.wildcard.test-zone.example.org", "foo.loadbalancer.com", endpoint.RecordTypeCNAME, ""),
+					newEndpointWithOwner("txt.cname-wc.wildcard.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+					newEndpointWithOwner("dualstack.test-zone.example.org", "2001:DB8::1", endpoint.RecordTypeAAAA, ""),
+					newEndpointWithOwner("txt.aaaa-dualstack.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+					newEndpointWithOwner("mail.test-zone.example.org", "10 mx.example.com", endpoint.RecordTypeMX, ""),
+					newEndpointWithOwner("txt.mail.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+					newEndpointWithOwner("txt.mx-mail.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+					newEndpointWithOwner("_sip._tcp.test-zone.example.org", "0 50 5060 sip.example.com", endpoint.RecordTypeSRV, ""),
+					newEndpointWithOwner("txt.srv-_sip._tcp.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+					// the managed record was deleted out-of-band
+					newEndpointWithOwner("txt.gone.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+					newEndpointWithOwner("txt.cname-gone.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+					newEndpointWithOwner("txt.mx-gone.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+					// an old-format record is not valid for AAAA records
+					newEndpointWithOwner("txt.dualstack.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+					// left over from a previous prefix
+					newEndpointWithOwner("cname-stale.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+					// owned by another instance
+					newEndpointWithOwner("txt.cname-other.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=other\"", endpoint.RecordTypeTXT, ""),
+				},
+			})
+
+			r, err := NewTXTRegistry(p, "txt.", "", "owner", 0, "wc", []string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME, endpoint.RecordTypeMX, endpoint.RecordTypeSRV}, []string{}, false, nil, tc.garbageCollect, nil, false)
+			require.NoError(t, err)
+
+			_, err = r.Records(ctx)
+			require.NoError(t, err)
+			assert.Len(t, r.OrphanedRecords(), tc.expectedOrphans)
+			assert.False(t, r.HasPendingChanges())
+
+			require.NoError(t, r.ApplyChanges(ctx, planOrphanedRecords(r, tc.policy)))
+
+			records, err := p.Records(ctx)
+			require.NoError(t, err)
+
+			txtRecords := []string{}
+			for _, record := range records {
+				if record.RecordType == endpoint.RecordTypeTXT {
+					txtRecords = append(txtRecords, record.DNSName)
+				}
+			}
+			assert.ElementsMatch(t, tc.expectedTXTRecords, txtRecords)
+		})
+	}
+}
+
+func TestTXTRegistryGarbageCollectSkipsRecreatedRecords(t *testing.T) {
+	ctx := context.Background()
+	p := inmemory.NewInMemoryProvider()
+	p.CreateZone(testZone)
+	p.ApplyChanges(ctx, &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("txt.gone.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("txt.cname-gone.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+		},
+	})
+
+	r, err := NewTXTRegistry(p, "txt.", "", "owner", 0, "", []string{}, []string{}, false, nil, true, nil, false)
+	require.NoError(t, err)
+
+	_, err = r.Records(ctx)
+	require.NoError(t, err)
+
+	orphans := r.collectOrphanedRecords(r.OrphanedRecords(), []*endpoint.Endpoint{
+		newEndpointWithOwner("txt.cname-gone.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+	})
+	require.Len(t, orphans, 1)
+	assert.Equal(t, "txt.gone.test-zone.example.org", orphans[0].DNSName)
+	assert.Empty(t, r.OrphanedRecords())
+}
+
+func testTXTRegistryMigrateLegacySchemes(t *testing.T) {
+	tests := []struct {
+		name               string
+		migrateDryRun      bool
+		expectedTXTRecords []string
+	}{
+		{
+			name:          "records are renamed after the current naming scheme",
+			migrateDryRun: false,
+			expectedTXTRecords: []string{
+				"new.foo.test-zone.example.org",
+				"new.a-foo.test-zone.example.org",
+				"txt.cname-bar.test-zone.example.org",
+				"new.a-baz.test-zone.example.org",
+				"new.baz.test-zone.example.org",
+			},
+		},
+		{
+			name:          "only the orphaned record is deleted in dry run",
+			migrateDryRun: true,
+			expectedTXTRecords: []string{
+				"txt.foo.test-zone.example.org",
+				"txt.a-foo.test-zone.example.org",
+				"txt.cname-bar.test-zone.example.org",
+				"txt.a-baz.test-zone.example.org",
+				"new.a-baz.test-zone.example.org",
+				"new.baz.test-zone.example.org",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			p := inmemory.NewInMemoryProvider()
+			p.CreateZone(testZone)
+			p.ApplyChanges(ctx, &plan.Changes{
+				Create: []*endpoint.Endpoint{
+					newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+					newEndpointWithOwner("txt.foo.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner,external-dns/resource=ingress/default/foo\"", endpoint.RecordTypeTXT, ""),
+					newEndpointWithOwner("txt.a-foo.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner,external-dns/resource=ingress/default/foo\"", endpoint.RecordTypeTXT, ""),
+					newEndpointWithOwner("bar.test-zone.example.org", "bar.loadbalancer.com", endpoint.RecordTypeCNAME, ""),
+					newEndpointWithOwner("txt.cname-bar.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=other\"", endpoint.RecordTypeTXT, ""),
+					newEndpointWithOwner("baz.test-zone.example.org", "5.6.7.8", endpoint.RecordTypeA, ""),
+					newEndpointWithOwner("txt.a-baz.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+					newEndpointWithOwner("new.a-baz.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+					newEndpointWithOwner("new.baz.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+					newEndpointWithOwner("txt.a-gone.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+				},
+			})
+
+			r, err := NewTXTRegistry(p, "new.", "", "owner", 0, "", []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME}, []string{}, false, nil, true, []TXTNameScheme{{Prefix: "txt."}}, tc.migrateDryRun)
+			require.NoError(t, err)
+
+			records, err := r.Records(ctx)
+			require.NoError(t, err)
+
+			expectedRecords := []*endpoint.Endpoint{
+				{
+					DNSName:    "foo.test-zone.example.org",
+					Targets:    endpoint.Targets{"1.2.3.4"},
+					RecordType: endpoint.RecordTypeA,
+					Labels: map[string]string{
+						endpoint.OwnerLabelKey:    "owner",
+						endpoint.ResourceLabelKey: "ingress/default/foo",
+					},
+				},
+				{
+					DNSName:    "bar.test-zone.example.org",
+					Targets:    endpoint.Targets{"bar.loadbalancer.com"},
+					RecordType: endpoint.RecordTypeCNAME,
+					Labels: map[string]string{
+						endpoint.OwnerLabelKey: "other",
+					},
+				},
+				{
+					DNSName:    "baz.test-zone.example.org",
+					Targets:    endpoint.Targets{"5.6.7.8"},
+					RecordType: endpoint.RecordTypeA,
+					Labels: map[string]string{
+						endpoint.OwnerLabelKey: "owner",
+					},
+				},
+			}
+			assert.True(t, testutils.SameEndpoints(records, expectedRecords))
+
+			require.NoError(t, r.ApplyChanges(ctx, planOrphanedRecords(r, &plan.SyncPolicy{})))
+			assert.False(t, r.HasPendingChanges())
+
+			records, err = p.Records(ctx)
+			require.NoError(t, err)
+
+			txtRecords := []string{}
+			for _, record := range records {
+				if record.RecordType == endpoint.RecordTypeTXT {
+					txtRecords = append(txtRecords, record.DNSName)
+				}
+			}
+
+			assert.ElementsMatch(t, tc.expectedTXTRecords, txtRecords)
+
+			// the labels are kept when rewriting the ownership records
+			records, err = r.Records(ctx)
+			require.NoError(t, err)
+			assert.True(t, testutils.SameEndpoints(records, expectedRecords))
+			assert.False(t, r.HasPendingChanges())
+		})
+	}
+}
+
 /**
 
 helper methods
 
 */
 
+// planOrphanedRecords returns the changes of the plan deleting the orphaned records of r under policy.
+func planOrphanedRecords(r *TXTRegistry, policy plan.Policy) *plan.Changes {
+	p := &plan.Plan{
+		Policies:        []plan.Policy{policy},
+		OrphanedRecords: r.OrphanedRecords(),
+		OwnerID:         r.OwnerID(),
+	}
+	return p.Calculate().Changes
+}
+
 func newEndpointWithOwner(dnsName, target, recordType, ownerID string) *endpoint.Endpoint {
 	return newEndpointWithOwnerAndLabels(dnsName, target, recordType, ownerID, nil)
 }
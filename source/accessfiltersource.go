@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// accessFilterSource is a Source that only returns the endpoints of its wrapped source published with a given access.
+type accessFilterSource struct {
+	source Source
+	access string
+}
+
+// NewAccessFilterSource creates a new accessFilterSource wrapping the provided Source.
+// Endpoints without access label are considered public.
+func NewAccessFilterSource(source Source, access string) Source {
+	return &accessFilterSource{source: source, access: access}
+}
+
+// Endpoints collects endpoints from its wrapped source and returns the ones published
+// with the access of the source, without the access label.
+func (as *accessFilterSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	endpoints, err := as.source.Endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return as.filter(endpoints), nil
+}
+
+// ResourceEndpoints collects the endpoints of the resources from its wrapped source and returns the
+// ones published with the access of the source, without the access label.
+func (as *accessFilterSource) ResourceEndpoints(ctx context.Context, resources map[string]struct{}) ([]*endpoint.Endpoint, error) {
+	endpoints, err := ResourceEndpoints(ctx, as.source, resources)
+	if err != nil {
+		return nil, err
+	}
+	return as.filter(endpoints), nil
+}
+
+func (as *accessFilterSource) filter(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	result := []*endpoint.Endpoint{}
+
+	for _, ep := range endpoints {
+		access, ok := ep.Labels[endpoint.AccessLabelKey]
+		if !ok {
+			access = endpoint.AccessPublic
+		}
+		if access != as.access {
+			continue
+		}
+
+		if ok {
+			ep = ep.DeepCopy()
+			delete(ep.Labels, endpoint.AccessLabelKey)
+		}
+		result = append(result, ep)
+	}
+
+	return result
+}
+
+func (as *accessFilterSource) AddEventHandler(ctx context.Context, handler func()) {
+	as.source.AddEventHandler(ctx, handler)
+}
+
+func (as *accessFilterSource) AddResourceEventHandler(ctx context.Context, handler func(resource string)) {
+	AddResourceEventHandler(ctx, as.source, handler)
+}
+
+func (as *accessFilterSource) UpdateFilters(annotationFilter string, labelSelector labels.Selector) error {
+	return UpdateFilters(as.source, annotationFilter, labelSelector)
+}
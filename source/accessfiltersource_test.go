@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestAccessFilterSource(t *testing.T) {
+	newEndpoint := func(name, target, access string) *endpoint.Endpoint {
+		ep := endpoint.NewEndpoint(name, endpoint.RecordTypeA, target)
+		if access != "" {
+			ep.Labels[endpoint.AccessLabelKey] = access
+		}
+		return ep
+	}
+	endpoints := []*endpoint.Endpoint{
+		newEndpoint("app.example.org", "1.2.3.4", endpoint.AccessPublic),
+		newEndpoint("app.example.org", "10.0.0.1", endpoint.AccessPrivate),
+		newEndpoint("www.example.org", "5.6.7.8", ""),
+	}
+
+	for _, tc := range []struct {
+		access  string
+		targets []string
+	}{
+		{endpoint.AccessPublic, []string{"1.2.3.4", "5.6.7.8"}},
+		{endpoint.AccessPrivate, []string{"10.0.0.1"}},
+	} {
+		t.Run(tc.access, func(t *testing.T) {
+			result, err := NewAccessFilterSource(NewEchoSource(endpoints), tc.access).Endpoints(context.Background())
+			require.NoError(t, err)
+
+			var targets []string
+			for _, ep := range result {
+				assert.NotContains(t, ep.Labels, endpoint.AccessLabelKey)
+				targets = append(targets, ep.Targets...)
+			}
+			assert.Equal(t, tc.targets, targets)
+		})
+	}
+
+	// The endpoints of the wrapped source keep their label.
+	assert.Equal(t, endpoint.AccessPrivate, endpoints[1].Labels[endpoint.AccessLabelKey])
+}
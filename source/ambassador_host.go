@@ -172,6 +172,7 @@ func (sc *ambassadorHostSource) Endpoints(ctx context.Context) ([]*endpoint.Endp
 		}
 
 		log.Debugf("Endpoints generated from Host: %s: %v", fullname, hostEndpoints)
+		setMetadataLabels(hostEndpoints, host.ObjectMeta)
 		endpoints = append(endpoints, hostEndpoints...)
 	}
 
@@ -167,6 +167,7 @@ func (sc *httpProxySource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint,
 		}
 
 		log.Debugf("Endpoints generated from HTTPProxy: %s/%s: %v", hp.Namespace, hp.Name, hpEndpoints)
+		setMetadataLabels(hpEndpoints, hp.ObjectMeta)
 		endpoints = append(endpoints, hpEndpoints...)
 	}
 
@@ -188,8 +188,24 @@ func (cs *crdSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error
 				continue
 			}
 
+			dnsName, err := endpoint.ToASCII(ep.DNSName)
+			if err != nil {
+				log.Warnf("Endpoint %s has an illegal DNSName: %v", dnsEndpoint.ObjectMeta.Name, err)
+				continue
+			}
+			ep.DNSName = dnsName
+
+			if err := ep.ValidateTargets(); err != nil {
+				log.Warnf("Endpoint %s with DNSName %s has an illegal target: %v", dnsEndpoint.ObjectMeta.Name, ep.DNSName, err)
+				continue
+			}
+
 			illegalTarget := false
 			for _, target := range ep.Targets {
+				// the target names of HTTPS and SVCB records are absolute, "." is the name of the record itself
+				if ep.RecordType == endpoint.RecordTypeHTTPS || ep.RecordType == endpoint.RecordTypeSVCB {
+					break
+				}
 				if ep.RecordType != "NAPTR" && strings.HasSuffix(target, ".") {
 					illegalTarget = true
 					break
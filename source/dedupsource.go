@@ -20,6 +20,7 @@ import (
 	"context"
 
 	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"sigs.k8s.io/external-dns/endpoint"
 )
@@ -36,13 +37,25 @@ func NewDedupSource(source Source) Source {
 
 // Endpoints collects endpoints from its wrapped source and returns them without duplicates.
 func (ms *dedupSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
-	result := []*endpoint.Endpoint{}
-	collected := map[string]bool{}
-
 	endpoints, err := ms.source.Endpoints(ctx)
 	if err != nil {
 		return nil, err
 	}
+	return dedupEndpoints(endpoints), nil
+}
+
+// ResourceEndpoints collects the endpoints of the resources from its wrapped source and returns them without duplicates.
+func (ms *dedupSource) ResourceEndpoints(ctx context.Context, resources map[string]struct{}) ([]*endpoint.Endpoint, error) {
+	endpoints, err := ResourceEndpoints(ctx, ms.source, resources)
+	if err != nil {
+		return nil, err
+	}
+	return dedupEndpoints(endpoints), nil
+}
+
+func dedupEndpoints(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	result := []*endpoint.Endpoint{}
+	collected := map[string]bool{}
 
 	for _, ep := range endpoints {
 		identifier := ep.DNSName + " / " + ep.SetIdentifier + " / " + ep.Targets.String()
@@ -56,9 +69,17 @@ func (ms *dedupSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, err
 		result = append(result, ep)
 	}
 
-	return result, nil
+	return result
 }
 
 func (ms *dedupSource) AddEventHandler(ctx context.Context, handler func()) {
 	ms.source.AddEventHandler(ctx, handler)
 }
+
+func (ms *dedupSource) AddResourceEventHandler(ctx context.Context, handler func(resource string)) {
+	AddResourceEventHandler(ctx, ms.source, handler)
+}
+
+func (ms *dedupSource) UpdateFilters(annotationFilter string, labelSelector labels.Selector) error {
+	return UpdateFilters(ms.source, annotationFilter, labelSelector)
+}
@@ -159,7 +159,9 @@ func (vs *f5VirtualServerSource) endpointsFromVirtualServers(virtualServers []*f
 			targets = append(targets, virtualServer.Status.VSAddress)
 		}
 
-		endpoints = append(endpoints, endpointsForHostname(virtualServer.Spec.Host, targets, ttl, nil, "", resource)...)
+		vsEndpoints := endpointsForHostname(virtualServer.Spec.Host, targets, ttl, nil, "", resource)
+		setMetadataLabels(vsEndpoints, virtualServer.ObjectMeta)
+		endpoints = append(endpoints, vsEndpoints...)
 	}
 
 	return endpoints, nil
@@ -0,0 +1,200 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/provider"
+)
+
+// Resolver looks up the IP addresses of a host name.
+type Resolver interface {
+	LookupIP(ctx context.Context, network, host string) ([]net.IP, error)
+}
+
+// NewResolver returns a resolver querying the DNS server at address, in host:port format,
+// or the system resolver if address is empty.
+func NewResolver(address string) Resolver {
+	if address == "" {
+		return net.DefaultResolver
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, address)
+		},
+	}
+}
+
+// flattenSource is a Source that replaces CNAME endpoints at the apex of a zone, or of resources
+// requesting it with the flatten annotation, by A and AAAA endpoints of the addresses of their targets.
+type flattenSource struct {
+	source   Source
+	zones    map[string]bool
+	resolver Resolver
+}
+
+// NewFlattenSource creates a new flattenSource wrapping the provided Source. The CNAME endpoints named
+// like one of the zones are flattened, as well as the ones labeled by their resource.
+func NewFlattenSource(source Source, zones []string, resolver Resolver) Source {
+	apexes := make(map[string]bool, len(zones))
+	for _, zone := range zones {
+		if zone = normalizeZone(zone); zone != "" {
+			apexes[zone] = true
+		}
+	}
+	return &flattenSource{source: source, zones: apexes, resolver: resolver}
+}
+
+// Endpoints collects endpoints from its wrapped source and flattens its CNAME endpoints. The addresses
+// are resolved again in every call, so that the flattened endpoints follow the changes of the targets.
+// A CNAME endpoint whose targets can't be resolved is replaced by its current A and AAAA records, the
+// registry records passed in ctx with provider.RecordsContextKey, without failing the other endpoints.
+func (s *flattenSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	endpoints, err := s.source.Endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.flattenEndpoints(ctx, endpoints), nil
+}
+
+// ResourceEndpoints collects the endpoints of the resources from its wrapped source and flattens their CNAME endpoints.
+func (s *flattenSource) ResourceEndpoints(ctx context.Context, resources map[string]struct{}) ([]*endpoint.Endpoint, error) {
+	endpoints, err := ResourceEndpoints(ctx, s.source, resources)
+	if err != nil {
+		return nil, err
+	}
+	return s.flattenEndpoints(ctx, endpoints), nil
+}
+
+func (s *flattenSource) flattenEndpoints(ctx context.Context, endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	result := make([]*endpoint.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		if ep.RecordType != endpoint.RecordTypeCNAME || !s.shouldFlatten(ep) {
+			result = append(result, ep)
+			continue
+		}
+		flattened, err := s.flatten(ctx, ep)
+		if err != nil {
+			flattened = currentAddressRecords(ctx, ep)
+			log.Warnf("Keeping the %d current address records of %s: %v", len(flattened), ep.DNSName, err)
+		}
+		result = append(result, flattened...)
+	}
+	return result
+}
+
+func (s *flattenSource) shouldFlatten(ep *endpoint.Endpoint) bool {
+	return ep.Labels[endpoint.FlattenLabelKey] == "true" || s.zones[normalizeZone(ep.DNSName)]
+}
+
+// flatten returns the A and AAAA endpoints of the addresses of the targets of ep. Targets which don't
+// exist are skipped, other resolution errors are returned, as well as an error when no target has addresses,
+// so that a temporary outage of the targets doesn't delete the flattened records.
+func (s *flattenSource) flatten(ctx context.Context, ep *endpoint.Endpoint) ([]*endpoint.Endpoint, error) {
+	var v4, v6 endpoint.Targets
+	seen := map[string]bool{}
+	for _, target := range ep.Targets {
+		ips, err := s.resolver.LookupIP(ctx, "ip", strings.TrimSuffix(target, ".")+".")
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			log.Warnf("Skipping the target %s of %s, it has no addresses", target, ep.DNSName)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("resolving the target %s of %s: %w", target, ep.DNSName, err)
+		}
+		for _, ip := range ips {
+			address := ip.String()
+			if seen[address] {
+				continue
+			}
+			seen[address] = true
+			if ip.To4() != nil {
+				v4 = append(v4, address)
+			} else {
+				v6 = append(v6, address)
+			}
+		}
+	}
+
+	var flattened []*endpoint.Endpoint
+	for recordType, targets := range map[string]endpoint.Targets{endpoint.RecordTypeA: v4, endpoint.RecordTypeAAAA: v6} {
+		if len(targets) == 0 {
+			continue
+		}
+		sort.Strings(targets)
+		flat := ep.DeepCopy()
+		flat.RecordType = recordType
+		flat.Targets = targets
+		delete(flat.Labels, endpoint.FlattenLabelKey)
+		flattened = append(flattened, flat)
+	}
+	if len(flattened) == 0 {
+		return nil, fmt.Errorf("none of the targets %s of %s has addresses", ep.Targets, ep.DNSName)
+	}
+	sort.Slice(flattened, func(i, j int) bool { return flattened[i].RecordType < flattened[j].RecordType })
+	log.Debugf("Flattened the CNAME %s to %s", ep.DNSName, flattened)
+	return flattened, nil
+}
+
+// currentAddressRecords returns the A and AAAA endpoints of ep with the targets of its current records.
+func currentAddressRecords(ctx context.Context, ep *endpoint.Endpoint) []*endpoint.Endpoint {
+	records, _ := ctx.Value(provider.RecordsContextKey).([]*endpoint.Endpoint)
+	var current []*endpoint.Endpoint
+	for _, record := range records {
+		if record.RecordType != endpoint.RecordTypeA && record.RecordType != endpoint.RecordTypeAAAA {
+			continue
+		}
+		if record.DNSName != ep.DNSName || record.SetIdentifier != ep.SetIdentifier {
+			continue
+		}
+		flat := ep.DeepCopy()
+		flat.RecordType = record.RecordType
+		flat.Targets = append(endpoint.Targets(nil), record.Targets...)
+		delete(flat.Labels, endpoint.FlattenLabelKey)
+		current = append(current, flat)
+	}
+	return current
+}
+
+func normalizeZone(name string) string {
+	return strings.ToLower(strings.Trim(strings.TrimSpace(name), "."))
+}
+
+func (s *flattenSource) AddEventHandler(ctx context.Context, handler func()) {
+	s.source.AddEventHandler(ctx, handler)
+}
+
+func (s *flattenSource) AddResourceEventHandler(ctx context.Context, handler func(resource string)) {
+	AddResourceEventHandler(ctx, s.source, handler)
+}
+
+func (s *flattenSource) UpdateFilters(annotationFilter string, labelSelector labels.Selector) error {
+	return UpdateFilters(s.source, annotationFilter, labelSelector)
+}
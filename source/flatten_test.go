@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/internal/testutils"
+	"sigs.k8s.io/external-dns/provider"
+)
+
+// Validates that flattenSource is a Source
+var _ Source = &flattenSource{}
+
+// dnsStub is a local DNS server answering the A and AAAA queries of its records.
+type dnsStub struct {
+	mu      sync.Mutex
+	records map[string][]string
+}
+
+func (s *dnsStub) set(name string, addresses ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[dns.Fqdn(name)] = addresses
+}
+
+func (s *dnsStub) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := new(dns.Msg)
+	m.SetReply(req)
+	m.Authoritative = true
+	question := req.Question[0]
+	addresses, ok := s.records[question.Name]
+	if !ok {
+		m.Rcode = dns.RcodeNameError
+	}
+	for _, address := range addresses {
+		ip := net.ParseIP(address)
+		switch {
+		case ip.To4() != nil && question.Qtype == dns.TypeA:
+			m.Answer = append(m.Answer, &dns.A{Hdr: dns.RR_Header{Name: question.Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60}, A: ip})
+		case ip.To4() == nil && question.Qtype == dns.TypeAAAA:
+			m.Answer = append(m.Answer, &dns.AAAA{Hdr: dns.RR_Header{Name: question.Name, Rrtype: dns.TypeAAAA, Class: dns.ClassINET, Ttl: 60}, AAAA: ip})
+		}
+	}
+	if len(m.Answer) == 0 {
+		m.Ns = append(m.Ns, &dns.SOA{Hdr: dns.RR_Header{Name: question.Name, Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 60}, Ns: "ns.example.net.", Mbox: "hostmaster.example.net.", Minttl: 60})
+	}
+	_ = w.WriteMsg(m)
+}
+
+func startDNSStub(t *testing.T) (*dnsStub, string) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	stub := &dnsStub{records: map[string][]string{}}
+	server := &dns.Server{PacketConn: conn, Handler: stub}
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+	return stub, conn.LocalAddr().String()
+}
+
+func TestFlattenSource(t *testing.T) {
+	stub, address := startDNSStub(t)
+	stub.set("lb.example.net", "192.0.2.2", "192.0.2.1", "2001:db8::1")
+	stub.set("v4.example.net", "192.0.2.3")
+
+	flattened := endpoint.NewEndpoint("marked.example.org", endpoint.RecordTypeCNAME, "v4.example.net")
+	flattened.Labels[endpoint.FlattenLabelKey] = "true"
+	mockSource := new(testutils.MockSource)
+	mockSource.On("Endpoints").Return([]*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("example.org", endpoint.RecordTypeCNAME, 300, "lb.example.net"),
+		endpoint.NewEndpoint("www.example.org", endpoint.RecordTypeCNAME, "lb.example.net"),
+		endpoint.NewEndpoint("example.org", endpoint.RecordTypeTXT, "text"),
+		flattened,
+	}, nil)
+
+	src := NewFlattenSource(mockSource, []string{"example.org."}, NewResolver(address))
+	endpoints, err := src.Endpoints(context.Background())
+	require.NoError(t, err)
+
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("example.org", endpoint.RecordTypeA, 300, "192.0.2.1", "192.0.2.2"),
+		endpoint.NewEndpointWithTTL("example.org", endpoint.RecordTypeAAAA, 300, "2001:db8::1"),
+		endpoint.NewEndpoint("www.example.org", endpoint.RecordTypeCNAME, "lb.example.net"),
+		endpoint.NewEndpoint("example.org", endpoint.RecordTypeTXT, "text"),
+		endpoint.NewEndpoint("marked.example.org", endpoint.RecordTypeA, "192.0.2.3"),
+	})
+
+	// the addresses are resolved again in every synchronization
+	stub.set("lb.example.net", "192.0.2.4")
+	stub.set("v4.example.net")
+	endpoints, err = src.Endpoints(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("example.org", endpoint.RecordTypeA, 300, "192.0.2.4"),
+		endpoint.NewEndpoint("www.example.org", endpoint.RecordTypeCNAME, "lb.example.net"),
+		endpoint.NewEndpoint("example.org", endpoint.RecordTypeTXT, "text"),
+	})
+}
+
+// failingResolver fails to resolve the hosts of failures, and resolves the other hosts with resolver.
+type failingResolver struct {
+	resolver Resolver
+	failures map[string]bool
+}
+
+func (r failingResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	if r.failures[host] {
+		return nil, &net.DNSError{Err: "i/o timeout", Name: host, IsTimeout: true}
+	}
+	return r.resolver.LookupIP(ctx, network, host)
+}
+
+func TestFlattenSourceResolutionError(t *testing.T) {
+	stub, address := startDNSStub(t)
+	stub.set("lb.example.net", "192.0.2.1")
+
+	mockSource := new(testutils.MockSource)
+	mockSource.On("Endpoints").Return([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("example.org", endpoint.RecordTypeCNAME, "lb.example.net"),
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeCNAME, "down.example.net"),
+		endpoint.NewEndpoint("example.info", endpoint.RecordTypeCNAME, "down.example.net"),
+		endpoint.NewEndpoint("www.example.org", endpoint.RecordTypeA, "192.0.2.9"),
+	}, nil)
+	resolver := failingResolver{resolver: NewResolver(address), failures: map[string]bool{"down.example.net.": true}}
+	src := NewFlattenSource(mockSource, []string{"example.org", "example.com", "example.info"}, resolver)
+
+	// the current records of example.com are kept, example.info has none
+	ctx := context.WithValue(context.Background(), provider.RecordsContextKey, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeA, "192.0.2.5"),
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeAAAA, "2001:db8::5"),
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeTXT, "text"),
+		endpoint.NewEndpoint("www.example.com", endpoint.RecordTypeA, "192.0.2.6"),
+	})
+	endpoints, err := src.Endpoints(ctx)
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("example.org", endpoint.RecordTypeA, "192.0.2.1"),
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeA, "192.0.2.5"),
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeAAAA, "2001:db8::5"),
+		endpoint.NewEndpoint("www.example.org", endpoint.RecordTypeA, "192.0.2.9"),
+	})
+}
+
+func TestFlattenSourceTargetsNotFound(t *testing.T) {
+	stub, address := startDNSStub(t)
+	stub.set("lb.example.net", "192.0.2.1")
+
+	mockSource := new(testutils.MockSource)
+	mockSource.On("Endpoints").Return([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("example.org", endpoint.RecordTypeCNAME, "lb.example.net", "gone.example.net"),
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeCNAME, "gone.example.net", "missing.example.net"),
+		endpoint.NewEndpoint("example.info", endpoint.RecordTypeCNAME, "gone.example.net"),
+	}, nil)
+	src := NewFlattenSource(mockSource, []string{"example.org", "example.com", "example.info"}, NewResolver(address))
+
+	// the current records of example.com are kept while none of its targets exists, example.info has none
+	ctx := context.WithValue(context.Background(), provider.RecordsContextKey, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("example.org", endpoint.RecordTypeA, "192.0.2.4"),
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeA, "192.0.2.5"),
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeAAAA, "2001:db8::5"),
+	})
+	endpoints, err := src.Endpoints(ctx)
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("example.org", endpoint.RecordTypeA, "192.0.2.1"),
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeA, "192.0.2.5"),
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeAAAA, "2001:db8::5"),
+	})
+}
@@ -22,10 +22,12 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"text/template"
 
 	log "github.com/sirupsen/logrus"
 	networkv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
 	kubeinformers "k8s.io/client-go/informers"
@@ -65,6 +67,8 @@ type ingressSource struct {
 	ignoreIngressTLSSpec     bool
 	ignoreIngressRulesSpec   bool
 	labelSelector            labels.Selector
+	// filterMutex protects annotationFilter and labelSelector, which UpdateFilters changes
+	filterMutex sync.RWMutex
 }
 
 // NewIngressSource creates a new ingressSource with the given config.
@@ -74,20 +78,8 @@ func NewIngressSource(ctx context.Context, kubeClient kubernetes.Interface, name
 		return nil, err
 	}
 
-	// ensure that ingress class is only set in either the ingressClassNames or
-	// annotationFilter but not both
-	if ingressClassNames != nil && annotationFilter != "" {
-		selector, err := getLabelSelector(annotationFilter)
-		if err != nil {
-			return nil, err
-		}
-
-		requirements, _ := selector.Requirements()
-		for _, requirement := range requirements {
-			if requirement.Key() == "kubernetes.io/ingress.class" {
-				return nil, errors.New("--ingress-class is mutually exclusive with the kubernetes.io/ingress.class annotation filter")
-			}
-		}
+	if err := validateIngressClassFilter(annotationFilter, ingressClassNames); err != nil {
+		return nil, err
 	}
 	// Use shared informer to listen for add/update/delete of ingresses in the specified namespace.
 	// Set resync period to 0, to prevent processing when nothing has changed.
@@ -125,14 +117,83 @@ func NewIngressSource(ctx context.Context, kubeClient kubernetes.Interface, name
 	return sc, nil
 }
 
+// validateIngressClassFilter ensures that ingress class is only set in either the ingressClassNames or
+// annotationFilter but not both
+func validateIngressClassFilter(annotationFilter string, ingressClassNames []string) error {
+	if ingressClassNames == nil || annotationFilter == "" {
+		return nil
+	}
+	selector, err := getLabelSelector(annotationFilter)
+	if err != nil {
+		return err
+	}
+
+	requirements, _ := selector.Requirements()
+	for _, requirement := range requirements {
+		if requirement.Key() == "kubernetes.io/ingress.class" {
+			return errors.New("--ingress-class is mutually exclusive with the kubernetes.io/ingress.class annotation filter")
+		}
+	}
+	return nil
+}
+
+// UpdateFilters changes the annotation filter and the label selector of the ingresses.
+func (sc *ingressSource) UpdateFilters(annotationFilter string, labelSelector labels.Selector) error {
+	if err := validateIngressClassFilter(annotationFilter, sc.ingressClassNames); err != nil {
+		return err
+	}
+	if _, err := getLabelSelector(annotationFilter); err != nil {
+		return err
+	}
+	sc.filterMutex.Lock()
+	defer sc.filterMutex.Unlock()
+	sc.annotationFilter = annotationFilter
+	sc.labelSelector = labelSelector
+	return nil
+}
+
 // Endpoints returns endpoint objects for each host-target combination that should be processed.
 // Retrieves all ingress resources on all namespaces
 func (sc *ingressSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
-	ingresses, err := sc.ingressInformer.Lister().Ingresses(sc.namespace).List(sc.labelSelector)
+	sc.filterMutex.RLock()
+	labelSelector := sc.labelSelector
+	sc.filterMutex.RUnlock()
+
+	ingresses, err := sc.ingressInformer.Lister().Ingresses(sc.namespace).List(labelSelector)
 	if err != nil {
 		return nil, err
 	}
-	ingresses, err = sc.filterByAnnotations(ingresses)
+	return sc.endpointsFromIngresses(ingresses)
+}
+
+// ResourceEndpoints returns the endpoints of the given ingresses only.
+func (sc *ingressSource) ResourceEndpoints(ctx context.Context, resources map[string]struct{}) ([]*endpoint.Endpoint, error) {
+	sc.filterMutex.RLock()
+	labelSelector := sc.labelSelector
+	sc.filterMutex.RUnlock()
+
+	ingresses := []*networkv1.Ingress{}
+	for _, key := range resourceKeys("ingress", resources) {
+		if sc.namespace != "" && key.Namespace != sc.namespace {
+			continue
+		}
+		ing, err := sc.ingressInformer.Lister().Ingresses(key.Namespace).Get(key.Name)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if labelSelector.Matches(labels.Set(ing.Labels)) {
+			ingresses = append(ingresses, ing)
+		}
+	}
+	return sc.endpointsFromIngresses(ingresses)
+}
+
+// endpointsFromIngresses returns the endpoints of the ingresses matching the filters.
+func (sc *ingressSource) endpointsFromIngresses(ingresses []*networkv1.Ingress) ([]*endpoint.Endpoint, error) {
+	ingresses, err := sc.filterByAnnotations(ingresses)
 	if err != nil {
 		return nil, err
 	}
@@ -172,6 +233,7 @@ func (sc *ingressSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, e
 
 		log.Debugf("Endpoints generated from ingress: %s/%s: %v", ing.Namespace, ing.Name, ingEndpoints)
 		sc.setDualstackLabel(ing, ingEndpoints)
+		setMetadataLabels(ingEndpoints, ing.ObjectMeta)
 		endpoints = append(endpoints, ingEndpoints...)
 	}
 
@@ -208,7 +270,11 @@ func (sc *ingressSource) endpointsFromTemplate(ing *networkv1.Ingress) ([]*endpo
 
 // filterByAnnotations filters a list of ingresses by a given annotation selector.
 func (sc *ingressSource) filterByAnnotations(ingresses []*networkv1.Ingress) ([]*networkv1.Ingress, error) {
-	selector, err := getLabelSelector(sc.annotationFilter)
+	sc.filterMutex.RLock()
+	annotationFilter := sc.annotationFilter
+	sc.filterMutex.RUnlock()
+
+	selector, err := getLabelSelector(annotationFilter)
 	if err != nil {
 		return nil, err
 	}
@@ -369,3 +435,9 @@ func (sc *ingressSource) AddEventHandler(ctx context.Context, handler func()) {
 	// https://github.com/kubernetes/kubernetes/issues/79610
 	sc.ingressInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
 }
+
+func (sc *ingressSource) AddResourceEventHandler(ctx context.Context, handler func(resource string)) {
+	log.Debug("Adding resource event handler for ingress")
+
+	sc.ingressInformer.Informer().AddEventHandler(resourceEventHandler{kind: "ingress", handler: handler})
+}
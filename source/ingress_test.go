@@ -19,6 +19,7 @@ package source
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -1482,3 +1483,101 @@ func (ing fakeIngress) Ingress() *networkv1.Ingress {
 	}
 	return ingress
 }
+
+func TestIngressSourceResourceEventHandler(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	src, err := NewIngressSource(ctx, fakeClient, "", "", "", false, false, false, false, labels.Everything(), []string{})
+	require.NoError(t, err)
+
+	resources := make(chan string, 10)
+	src.(ResourceEventNotifier).AddResourceEventHandler(ctx, func(resource string) { resources <- resource })
+
+	ingress := (fakeIngress{name: "web", namespace: "default", dnsnames: []string{"web.example.com"}}).Ingress()
+	_, err = fakeClient.NetworkingV1().Ingresses("default").Create(ctx, ingress, metav1.CreateOptions{})
+	require.NoError(t, err)
+	err = fakeClient.NetworkingV1().Ingresses("default").Delete(ctx, "web", metav1.DeleteOptions{})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case resource := <-resources:
+			assert.Equal(t, "ingress/default/web", resource)
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected a resource event")
+		}
+	}
+}
+
+func TestIngressSourceUpdateFilters(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, ing := range []fakeIngress{
+		{name: "a", namespace: "default", dnsnames: []string{"a.example.com"}, ips: []string{"1.2.3.4"}, annotations: map[string]string{"team": "a"}, labels: map[string]string{"env": "prod"}},
+		{name: "b", namespace: "default", dnsnames: []string{"b.example.com"}, ips: []string{"1.2.3.4"}, annotations: map[string]string{"team": "b"}, labels: map[string]string{"env": "test"}},
+	} {
+		_, err := fakeClient.NetworkingV1().Ingresses("default").Create(ctx, ing.Ingress(), metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	src, err := NewIngressSource(ctx, fakeClient, "", "team=a", "", false, false, false, false, labels.Everything(), nil)
+	require.NoError(t, err)
+
+	dnsNames := func() []string {
+		endpoints, err := src.Endpoints(ctx)
+		require.NoError(t, err)
+		var names []string
+		for _, ep := range endpoints {
+			names = append(names, ep.DNSName)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"a.example.com"}, dnsNames())
+
+	require.NoError(t, UpdateFilters(src, "team=b", labels.Everything()))
+	assert.Equal(t, []string{"b.example.com"}, dnsNames())
+
+	require.NoError(t, UpdateFilters(src, "", labels.SelectorFromSet(labels.Set{"env": "prod"})))
+	assert.Equal(t, []string{"a.example.com"}, dnsNames())
+
+	assert.Error(t, UpdateFilters(src, "team in (a", labels.Everything()))
+	assert.Equal(t, []string{"a.example.com"}, dnsNames(), "an invalid filter is not applied")
+
+	classSrc, err := NewIngressSource(ctx, fakeClient, "", "", "", false, false, false, false, labels.Everything(), []string{"nginx"})
+	require.NoError(t, err)
+	assert.Error(t, UpdateFilters(classSrc, "kubernetes.io/ingress.class=nginx", labels.Everything()))
+}
+
+func TestIngressSourceResourceEndpoints(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, ing := range []fakeIngress{
+		{name: "a", namespace: "default", dnsnames: []string{"a.example.com"}, ips: []string{"1.2.3.4"}, annotations: map[string]string{"team": "a"}},
+		{name: "b", namespace: "default", dnsnames: []string{"b.example.com"}, ips: []string{"1.2.3.5"}, annotations: map[string]string{"team": "a"}},
+		{name: "c", namespace: "default", dnsnames: []string{"c.example.com"}, ips: []string{"1.2.3.6"}, annotations: map[string]string{"team": "c"}},
+	} {
+		_, err := fakeClient.NetworkingV1().Ingresses("default").Create(ctx, ing.Ingress(), metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	src, err := NewIngressSource(ctx, fakeClient, "", "team=a", "", false, false, false, false, labels.Everything(), nil)
+	require.NoError(t, err)
+
+	// Deleted and filtered ingresses and the resources of other kinds have no endpoints.
+	endpoints, err := src.(ResourceEventNotifier).ResourceEndpoints(ctx, map[string]struct{}{
+		"ingress/default/a":       {},
+		"ingress/default/c":       {},
+		"ingress/default/deleted": {},
+		"service/default/b":       {},
+	})
+	require.NoError(t, err)
+	expected := endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "1.2.3.4")
+	expected.Labels[endpoint.ResourceLabelKey] = "ingress/default/a"
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{expected})
+}
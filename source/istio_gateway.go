@@ -181,6 +181,7 @@ func (sc *gatewaySource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, e
 		}
 
 		log.Debugf("Endpoints generated from gateway: %s/%s: %v", gateway.Namespace, gateway.Name, gwEndpoints)
+		setMetadataLabels(gwEndpoints, gateway.ObjectMeta)
 		endpoints = append(endpoints, gwEndpoints...)
 	}
 
@@ -169,6 +169,7 @@ func (sc *virtualServiceSource) Endpoints(ctx context.Context) ([]*endpoint.Endp
 		}
 
 		log.Debugf("Endpoints generated from VirtualService: %s/%s: %v", virtualService.Namespace, virtualService.Name, gwEndpoints)
+		setMetadataLabels(gwEndpoints, virtualService.ObjectMeta)
 		endpoints = append(endpoints, gwEndpoints...)
 	}
 
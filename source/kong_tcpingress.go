@@ -151,6 +151,7 @@ func (sc *kongTCPIngressSource) Endpoints(ctx context.Context) ([]*endpoint.Endp
 
 		log.Debugf("Endpoints generated from TCPIngress: %s: %v", fullname, ingressEndpoints)
 		sc.setDualstackLabel(tcpIngress, ingressEndpoints)
+		setMetadataLabels(ingressEndpoints, tcpIngress.ObjectMeta)
 		endpoints = append(endpoints, ingressEndpoints...)
 	}
 
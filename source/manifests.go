@@ -0,0 +1,215 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry-community/go-cfclient"
+	openshift "github.com/openshift/client-go/route/clientset/versioned"
+	projectcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	istioclient "istio.io/client-go/pkg/clientset/versioned"
+	istiofake "istio.io/client-go/pkg/clientset/versioned/fake"
+	istioscheme "istio.io/client-go/pkg/clientset/versioned/scheme"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	kubescheme "k8s.io/client-go/kubernetes/scheme"
+	gateway "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
+	gatewayfake "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/fake"
+	gatewayscheme "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/scheme"
+)
+
+// dynamicListKinds are the list kinds of the resources listed by the sources using the dynamic client.
+var dynamicListKinds = map[schema.GroupVersionResource]string{
+	ambHostGVR:                  "HostList",
+	projectcontour.HTTPProxyGVR: "HTTPProxyList",
+	proxyGVR:                    "ProxyList",
+	virtualServiceGVR:           "VirtualServiceList",
+	ingressrouteGVR:             "IngressRouteList",
+	ingressrouteTCPGVR:          "IngressRouteTCPList",
+	ingressrouteUDPGVR:          "IngressRouteUDPList",
+	oldIngressrouteGVR:          "IngressRouteList",
+	oldIngressrouteTCPGVR:       "IngressRouteTCPList",
+	oldIngressrouteUDPGVR:       "IngressRouteUDPList",
+	kongGroupdVersionResource:   "TCPIngressList",
+	f5VirtualServerGVR:          "VirtualServerList",
+}
+
+// ManifestClientGenerator provides fake clients serving the objects of Kubernetes manifests,
+// so that the sources run without a cluster.
+type ManifestClientGenerator struct {
+	kubeClient    kubernetes.Interface
+	gatewayClient gateway.Interface
+	istioClient   istioclient.Interface
+	dynamicClient dynamic.Interface
+}
+
+// NewManifestClientGenerator returns a ManifestClientGenerator serving the objects of the YAML
+// and JSON manifests in the directory or file at path.
+func NewManifestClientGenerator(path string) (*ManifestClientGenerator, error) {
+	objects, err := readManifests(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var kubeObjects, gatewayObjects, istioObjects, dynamicObjects []runtime.Object
+	dynamicScheme := runtime.NewScheme()
+	for _, obj := range objects {
+		gvk := obj.GroupVersionKind()
+		switch {
+		case kubescheme.Scheme.Recognizes(gvk):
+			typed, err := toTyped(kubescheme.Scheme, obj)
+			if err != nil {
+				return nil, err
+			}
+			kubeObjects = append(kubeObjects, typed)
+		case gatewayscheme.Scheme.Recognizes(gvk):
+			typed, err := toTyped(gatewayscheme.Scheme, obj)
+			if err != nil {
+				return nil, err
+			}
+			gatewayObjects = append(gatewayObjects, typed)
+		case istioscheme.Scheme.Recognizes(gvk):
+			typed, err := toTyped(istioscheme.Scheme, obj)
+			if err != nil {
+				return nil, err
+			}
+			istioObjects = append(istioObjects, typed)
+		default:
+			if !dynamicScheme.Recognizes(gvk) {
+				dynamicScheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+			}
+			dynamicObjects = append(dynamicObjects, obj)
+		}
+	}
+
+	return &ManifestClientGenerator{
+		kubeClient:    fake.NewSimpleClientset(kubeObjects...),
+		gatewayClient: gatewayfake.NewSimpleClientset(gatewayObjects...),
+		istioClient:   istiofake.NewSimpleClientset(istioObjects...),
+		dynamicClient: fakedynamic.NewSimpleDynamicClientWithCustomListKinds(dynamicScheme, dynamicListKinds, dynamicObjects...),
+	}, nil
+}
+
+// KubeClient returns the fake Kubernetes client.
+func (p *ManifestClientGenerator) KubeClient() (kubernetes.Interface, error) {
+	return p.kubeClient, nil
+}
+
+// GatewayClient returns the fake Gateway API client.
+func (p *ManifestClientGenerator) GatewayClient() (gateway.Interface, error) {
+	return p.gatewayClient, nil
+}
+
+// IstioClient returns the fake Istio client.
+func (p *ManifestClientGenerator) IstioClient() (istioclient.Interface, error) {
+	return p.istioClient, nil
+}
+
+// CloudFoundryClient fails, Cloud Foundry applications can't be read from manifests.
+func (p *ManifestClientGenerator) CloudFoundryClient(string, string, string) (*cfclient.Client, error) {
+	return nil, errors.New("the cloudfoundry source is not supported with manifests")
+}
+
+// DynamicKubernetesClient returns the fake dynamic client serving the custom resources.
+func (p *ManifestClientGenerator) DynamicKubernetesClient() (dynamic.Interface, error) {
+	return p.dynamicClient, nil
+}
+
+// OpenShiftClient fails, OpenShift routes can't be read from manifests.
+func (p *ManifestClientGenerator) OpenShiftClient() (openshift.Interface, error) {
+	return nil, errors.New("the openshift-route source is not supported with manifests")
+}
+
+// readManifests returns the objects of the YAML and JSON manifests in the directory or file at path.
+// Lists of objects are expanded, and the objects of every file may be separated by "---".
+func readManifests(path string) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+	err := filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+		for {
+			obj := &unstructured.Unstructured{}
+			if err := decoder.Decode(&obj.Object); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return fmt.Errorf("%s: %w", file, err)
+			}
+			if len(obj.Object) == 0 {
+				continue
+			}
+			if obj.GetKind() == "" || obj.GetAPIVersion() == "" {
+				return fmt.Errorf("%s: object without apiVersion or kind", file)
+			}
+			if !obj.IsList() {
+				objects = append(objects, obj)
+				continue
+			}
+			if err := obj.EachListItem(func(item runtime.Object) error {
+				objects = append(objects, item.(*unstructured.Unstructured))
+				return nil
+			}); err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifests: %w", err)
+	}
+	return objects, nil
+}
+
+// toTyped converts obj to the type registered in scheme for its kind.
+func toTyped(scheme *runtime.Scheme, obj *unstructured.Unstructured) (runtime.Object, error) {
+	typed, err := scheme.New(obj.GroupVersionKind())
+	if err != nil {
+		return nil, err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, typed); err != nil {
+		return nil, fmt.Errorf("%s %s/%s: %w", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+	return typed, nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const serviceManifest = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: default
+  annotations:
+    external-dns.alpha.kubernetes.io/hostname: web.example.com
+spec:
+  type: LoadBalancer
+status:
+  loadBalancer:
+    ingress:
+    - ip: 1.2.3.4
+`
+
+const ingressManifests = `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: api
+  namespace: default
+spec:
+  rules:
+  - host: api.example.com
+status:
+  loadBalancer:
+    ingress:
+    - ip: 5.6.7.8
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: projectcontour.io/v1
+  kind: HTTPProxy
+  metadata:
+    name: shop
+    namespace: default
+  spec:
+    virtualhost:
+      fqdn: shop.example.com
+  status:
+    loadBalancer:
+      ingress:
+      - hostname: lb.example.com
+`
+
+func TestManifestClientGenerator(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "service.yaml"), []byte(serviceManifest), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "nested"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "nested", "ingress.yml"), []byte(ingressManifests), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a manifest"), 0o600))
+
+	generator, err := NewManifestClientGenerator(dir)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sources, err := ByNames(ctx, generator, []string{"service", "ingress", "contour-httpproxy"}, &Config{
+		LabelFilter: labels.Everything(),
+	})
+	require.NoError(t, err)
+
+	endpoints, err := NewMultiSource(sources, nil).Endpoints(ctx)
+	require.NoError(t, err)
+	var records []string
+	for _, ep := range endpoints {
+		records = append(records, ep.DNSName+" "+ep.RecordType+" "+ep.Targets.String())
+	}
+	sort.Strings(records)
+	assert.Equal(t, []string{
+		"api.example.com A 5.6.7.8",
+		"shop.example.com CNAME lb.example.com",
+		"web.example.com A 1.2.3.4",
+	}, records)
+
+	_, err = ByNames(ctx, generator, []string{"openshift-route"}, &Config{LabelFilter: labels.Everything()})
+	assert.ErrorContains(t, err, "not supported with manifests")
+}
+
+func TestManifestClientGeneratorInvalidManifest(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.yaml"), []byte("metadata:\n  name: web\n"), 0o600))
+
+	_, err := NewManifestClientGenerator(dir)
+	assert.ErrorContains(t, err, "object without apiVersion or kind")
+}
@@ -18,8 +18,14 @@ package source
 
 import (
 	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/tracing"
 )
 
 // multiSource is a Source that merges the endpoints of its nested Sources.
@@ -33,32 +39,103 @@ func (ms *multiSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, err
 	result := []*endpoint.Endpoint{}
 
 	for _, s := range ms.children {
-		endpoints, err := s.Endpoints(ctx)
+		endpoints, err := childEndpoints(ctx, s)
 		if err != nil {
 			return nil, err
 		}
-		if len(ms.defaultTargets) > 0 {
-			for i := range endpoints {
-				eps := endpointsForHostname(endpoints[i].DNSName, ms.defaultTargets, endpoints[i].RecordTTL, endpoints[i].ProviderSpecific, endpoints[i].SetIdentifier, "")
-				for _, ep := range eps {
-					ep.Labels = endpoints[i].Labels
-				}
-				result = append(result, eps...)
-			}
-		} else {
-			result = append(result, endpoints...)
+		result = append(result, ms.withDefaultTargets(endpoints)...)
+	}
+
+	return result, nil
+}
+
+// ResourceEndpoints collects the endpoints of the resources of the nested Sources reporting the
+// changes of their resources. The changes of the other Sources require full synchronizations, so
+// none of the resources belong to them.
+func (ms *multiSource) ResourceEndpoints(ctx context.Context, resources map[string]struct{}) ([]*endpoint.Endpoint, error) {
+	result := []*endpoint.Endpoint{}
+
+	for _, s := range ms.children {
+		if _, ok := s.(ResourceEventNotifier); !ok {
+			continue
+		}
+		endpoints, err := childResourceEndpoints(ctx, s, resources)
+		if err != nil {
+			return nil, err
 		}
+		result = append(result, ms.withDefaultTargets(endpoints)...)
 	}
 
 	return result, nil
 }
 
+// withDefaultTargets replaces the targets of the endpoints by the default targets, if any.
+func (ms *multiSource) withDefaultTargets(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	if len(ms.defaultTargets) == 0 {
+		return endpoints
+	}
+	var result []*endpoint.Endpoint
+	for i := range endpoints {
+		eps := endpointsForHostname(endpoints[i].DNSName, ms.defaultTargets, endpoints[i].RecordTTL, endpoints[i].ProviderSpecific, endpoints[i].SetIdentifier, "")
+		for _, ep := range eps {
+			ep.Labels = endpoints[i].Labels
+		}
+		result = append(result, eps...)
+	}
+	return result
+}
+
+// childEndpoints returns the endpoints of a nested Source within a span of its own.
+func childEndpoints(ctx context.Context, s Source) ([]*endpoint.Endpoint, error) {
+	ctx, span := tracing.Start(ctx, "Source.Endpoints", attribute.String("external_dns.source", fmt.Sprintf("%T", s)))
+	endpoints, err := s.Endpoints(ctx)
+	span.SetAttributes(attribute.Int("external_dns.endpoints", len(endpoints)))
+	tracing.End(span, err)
+	return endpoints, err
+}
+
+// childResourceEndpoints returns the endpoints of the resources of a nested Source within a span of its own.
+func childResourceEndpoints(ctx context.Context, s Source, resources map[string]struct{}) ([]*endpoint.Endpoint, error) {
+	ctx, span := tracing.Start(ctx, "Source.ResourceEndpoints", attribute.String("external_dns.source", fmt.Sprintf("%T", s)))
+	endpoints, err := ResourceEndpoints(ctx, s, resources)
+	span.SetAttributes(attribute.Int("external_dns.endpoints", len(endpoints)))
+	tracing.End(span, err)
+	return endpoints, err
+}
+
 func (ms *multiSource) AddEventHandler(ctx context.Context, handler func()) {
 	for _, s := range ms.children {
 		s.AddEventHandler(ctx, handler)
 	}
 }
 
+func (ms *multiSource) AddResourceEventHandler(ctx context.Context, handler func(resource string)) {
+	for _, s := range ms.children {
+		AddResourceEventHandler(ctx, s, handler)
+	}
+}
+
+// UpdateFilters changes the filters of every child, failing for the children which can't change them.
+func (ms *multiSource) UpdateFilters(annotationFilter string, labelSelector labels.Selector) error {
+	// The filters are changed for all sources or none of them.
+	var errs []error
+	for _, s := range ms.children {
+		if _, ok := s.(FilterUpdater); !ok {
+			errs = append(errs, fmt.Errorf("source %T does not support changing its filters", s))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	for _, s := range ms.children {
+		if err := UpdateFilters(s, annotationFilter, labelSelector); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // NewMultiSource creates a new multiSource.
 func NewMultiSource(children []Source, defaultTargets []string) Source {
 	return &multiSource{children: children, defaultTargets: defaultTargets}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"sigs.k8s.io/external-dns/endpoint"
 	"sigs.k8s.io/external-dns/internal/testutils"
@@ -35,6 +36,117 @@ func TestMultiSource(t *testing.T) {
 	t.Run("Endpoints", testMultiSourceEndpoints)
 	t.Run("EndpointsWithError", testMultiSourceEndpointsWithError)
 	t.Run("EndpointsDefaultTargets", testMultiSourceEndpointsDefaultTargets)
+	t.Run("ResourceEventHandler", testMultiSourceResourceEventHandler)
+	t.Run("ResourceEndpoints", testMultiSourceResourceEndpoints)
+	t.Run("UpdateFilters", testMultiSourceUpdateFilters)
+}
+
+type testResourceSource struct {
+	Source
+	resource  string
+	endpoints []*endpoint.Endpoint
+}
+
+func (s *testResourceSource) ResourceEndpoints(ctx context.Context, resources map[string]struct{}) ([]*endpoint.Endpoint, error) {
+	if _, ok := resources[s.resource]; !ok {
+		return nil, nil
+	}
+	return s.endpoints, nil
+}
+
+func (s *testResourceSource) AddEventHandler(ctx context.Context, handler func()) {
+	handler()
+}
+
+func (s *testResourceSource) AddResourceEventHandler(ctx context.Context, handler func(resource string)) {
+	handler(s.resource)
+}
+
+type testEventSource struct {
+	Source
+}
+
+func (s *testEventSource) AddEventHandler(ctx context.Context, handler func()) {
+	handler()
+}
+
+// testMultiSourceResourceEventHandler tests that children which can't report the changed
+// resources report changes of unknown resources.
+func testMultiSourceResourceEventHandler(t *testing.T) {
+	src := NewMultiSource([]Source{&testResourceSource{resource: "ingress/default/web"}, &testEventSource{}}, nil)
+
+	var resources []string
+	AddResourceEventHandler(context.Background(), NewDedupSource(src), func(resource string) {
+		resources = append(resources, resource)
+	})
+	assert.Equal(t, []string{"ingress/default/web", ""}, resources)
+}
+
+// testMultiSourceResourceEndpoints tests that only the children reporting the changes of their
+// resources are evaluated, and that the default targets apply.
+func testMultiSourceResourceEndpoints(t *testing.T) {
+	web := endpoint.NewEndpoint("web.example.org", endpoint.RecordTypeA, "8.8.8.8")
+	web.Labels[endpoint.ResourceLabelKey] = "ingress/default/web"
+	api := endpoint.NewEndpoint("api.example.org", endpoint.RecordTypeA, "8.8.4.4")
+	api.Labels[endpoint.ResourceLabelKey] = "ingress/default/api"
+	// The mocked source fails the test when it is evaluated.
+	src := NewMultiSource([]Source{
+		&testResourceSource{resource: "ingress/default/web", endpoints: []*endpoint.Endpoint{web}},
+		&testResourceSource{resource: "ingress/default/api", endpoints: []*endpoint.Endpoint{api}},
+		new(testutils.MockSource),
+	}, []string{"127.0.0.1"})
+
+	endpoints, err := src.(ResourceEventNotifier).ResourceEndpoints(context.Background(), map[string]struct{}{"ingress/default/web": {}})
+	require.NoError(t, err)
+	expected := endpoint.NewEndpoint("web.example.org", endpoint.RecordTypeA, "127.0.0.1")
+	expected.Labels = web.Labels
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{expected})
+}
+
+type testFilterSource struct {
+	Source
+	annotationFilter string
+}
+
+func (s *testFilterSource) UpdateFilters(annotationFilter string, labelSelector labels.Selector) error {
+	s.annotationFilter = annotationFilter
+	return nil
+}
+
+// testMultiSourceUpdateFilters tests that the filters of the children are changed when all of them
+// support it, and that none of them are changed otherwise.
+func testMultiSourceUpdateFilters(t *testing.T) {
+	for _, tc := range []struct {
+		title          string
+		other          Source
+		expectedErr    string
+		expectedFilter string
+	}{
+		{
+			title:          "all children support changing their filters",
+			other:          &testFilterSource{},
+			expectedFilter: "team=a",
+		},
+		{
+			title:          "a child does not support changing its filters",
+			other:          &testEventSource{},
+			expectedErr:    "*source.testEventSource does not support changing its filters",
+			expectedFilter: "",
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			child := &testFilterSource{}
+			src := NewMultiSource([]Source{child, tc.other}, nil)
+
+			err := UpdateFilters(NewDedupSource(src), "team=a", labels.Everything())
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedFilter, child.annotationFilter)
+		})
+	}
 }
 
 // testMultiSourceImplementsSource tests that multiSource is a valid Source.
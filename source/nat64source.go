@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"net/netip"
 
+	"k8s.io/apimachinery/pkg/labels"
+
 	"sigs.k8s.io/external-dns/endpoint"
 )
 
@@ -37,6 +39,24 @@ func NewNAT64Source(source Source, nat64Prefixes []string) Source {
 
 // Endpoints collects endpoints from its wrapped source and returns them without duplicates.
 func (s *nat64Source) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	endpoints, err := s.source.Endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.addNAT64Endpoints(endpoints)
+}
+
+// ResourceEndpoints collects the endpoints of the resources from its wrapped source and adds the A
+// endpoints of their NAT64 addresses.
+func (s *nat64Source) ResourceEndpoints(ctx context.Context, resources map[string]struct{}) ([]*endpoint.Endpoint, error) {
+	endpoints, err := ResourceEndpoints(ctx, s.source, resources)
+	if err != nil {
+		return nil, err
+	}
+	return s.addNAT64Endpoints(endpoints)
+}
+
+func (s *nat64Source) addNAT64Endpoints(endpoints []*endpoint.Endpoint) ([]*endpoint.Endpoint, error) {
 	parsedNAT64Prefixes := make([]netip.Prefix, 0)
 	for _, prefix := range s.nat64Prefixes {
 		pPrefix, err := netip.ParsePrefix(prefix)
@@ -52,11 +72,6 @@ func (s *nat64Source) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, erro
 
 	additionalEndpoints := []*endpoint.Endpoint{}
 
-	endpoints, err := s.source.Endpoints(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	for _, ep := range endpoints {
 		if ep.RecordType != endpoint.RecordTypeAAAA {
 			continue
@@ -110,3 +125,11 @@ func (s *nat64Source) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, erro
 func (s *nat64Source) AddEventHandler(ctx context.Context, handler func()) {
 	s.source.AddEventHandler(ctx, handler)
 }
+
+func (s *nat64Source) AddResourceEventHandler(ctx context.Context, handler func(resource string)) {
+	AddResourceEventHandler(ctx, s.source, handler)
+}
+
+func (s *nat64Source) UpdateFilters(annotationFilter string, labelSelector labels.Selector) error {
+	return UpdateFilters(s.source, annotationFilter, labelSelector)
+}
@@ -158,6 +158,7 @@ func (ors *ocpRouteSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint,
 		}
 
 		log.Debugf("Endpoints generated from OpenShift Route: %s/%s: %v", ocpRoute.Namespace, ocpRoute.Name, orEndpoints)
+		setMetadataLabels(orEndpoints, ocpRoute.ObjectMeta)
 		endpoints = append(endpoints, orEndpoints...)
 	}
 
@@ -86,6 +86,39 @@ func (suite *OCPRouteSuite) TestResourceLabelIsSet() {
 	}
 }
 
+func TestOcpRouteSourceMetadataLabels(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	_, err := fakeClient.RouteV1().Routes("default").Create(context.Background(), &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "apex",
+			Annotations: map[string]string{
+				priorityAnnotationKey: "10",
+				policyAnnotationKey:   "create-only",
+				flattenAnnotationKey:  "true",
+			},
+		},
+		Spec: routev1.RouteSpec{Host: "example.org"},
+		Status: routev1.RouteStatus{
+			Ingress: []routev1.RouteIngress{{
+				Host:                    "example.org",
+				RouterCanonicalHostname: "router.example.net",
+				Conditions:              []routev1.RouteIngressCondition{{Type: routev1.RouteAdmitted, Status: corev1.ConditionTrue}},
+			}},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	src, err := NewOcpRouteSource(context.Background(), fakeClient, "", "", "", false, false, labels.Everything(), "")
+	require.NoError(t, err)
+	endpoints, err := src.Endpoints(context.Background())
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, "10", endpoints[0].Labels[endpoint.PriorityLabelKey])
+	assert.Equal(t, "create-only", endpoints[0].Labels[endpoint.PolicyLabelKey])
+	assert.Equal(t, "true", endpoints[0].Labels[endpoint.FlattenLabelKey])
+}
+
 func TestOcpRouteSource(t *testing.T) {
 	t.Parallel()
 
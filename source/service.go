@@ -20,12 +20,15 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"reflect"
 	"sort"
 	"strings"
+	"sync"
 	"text/template"
 
 	log "github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	kubeinformers "k8s.io/client-go/informers"
@@ -61,6 +64,12 @@ type serviceSource struct {
 	nodeInformer                   coreinformers.NodeInformer
 	serviceTypeFilter              map[string]struct{}
 	labelSelector                  labels.Selector
+	// filterMutex protects annotationFilter and labelSelector, which UpdateFilters changes
+	filterMutex sync.RWMutex
+	// hostnames are the DNS names of the endpoints of every service before merging, by resource, from
+	// their last evaluation, protected by hostnamesMutex
+	hostnames      map[string][]string
+	hostnamesMutex sync.Mutex
 }
 
 // NewServiceSource creates a new serviceSource with the given config.
@@ -141,11 +150,111 @@ func NewServiceSource(ctx context.Context, kubeClient kubernetes.Interface, name
 
 // Endpoints returns endpoint objects for each service that should be processed.
 func (sc *serviceSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
-	services, err := sc.serviceInformer.Lister().Services(sc.namespace).List(sc.labelSelector)
+	sc.filterMutex.RLock()
+	labelSelector := sc.labelSelector
+	sc.filterMutex.RUnlock()
+
+	services, err := sc.serviceInformer.Lister().Services(sc.namespace).List(labelSelector)
 	if err != nil {
 		return nil, err
 	}
-	services, err = sc.filterByAnnotations(services)
+	endpoints, err := sc.endpointsFromServices(services)
+	if err != nil {
+		return nil, err
+	}
+
+	sc.hostnamesMutex.Lock()
+	sc.hostnames = map[string][]string{}
+	sc.addHostnames(endpoints)
+	sc.hostnamesMutex.Unlock()
+
+	return mergeServiceEndpoints(endpoints), nil
+}
+
+// ResourceEndpoints returns the endpoints of the given services. The endpoints of services sharing
+// a DNS name are merged, so the services sharing a DNS name with the given services, before or after
+// their change, are evaluated as well, up to the services sharing no DNS name with the evaluated ones.
+func (sc *serviceSource) ResourceEndpoints(ctx context.Context, resources map[string]struct{}) ([]*endpoint.Endpoint, error) {
+	sc.filterMutex.RLock()
+	labelSelector := sc.labelSelector
+	sc.filterMutex.RUnlock()
+
+	sc.hostnamesMutex.Lock()
+	defer sc.hostnamesMutex.Unlock()
+
+	endpoints := []*endpoint.Endpoint{}
+	evaluated := map[string]struct{}{}
+	pending := resources
+	for len(pending) > 0 {
+		services := []*v1.Service{}
+		for _, key := range resourceKeys("service", pending) {
+			if sc.namespace != "" && key.Namespace != sc.namespace {
+				continue
+			}
+			svc, err := sc.serviceInformer.Lister().Services(key.Namespace).Get(key.Name)
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if labelSelector.Matches(labels.Set(svc.Labels)) {
+				services = append(services, svc)
+			}
+		}
+		svcEndpoints, err := sc.endpointsFromServices(services)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, svcEndpoints...)
+
+		hostnames := map[string]struct{}{}
+		for resource := range pending {
+			evaluated[resource] = struct{}{}
+			for _, hostname := range sc.hostnames[resource] {
+				hostnames[hostname] = struct{}{}
+			}
+		}
+		for _, ep := range svcEndpoints {
+			hostnames[ep.DNSName] = struct{}{}
+		}
+
+		pending = map[string]struct{}{}
+		for resource, resourceHostnames := range sc.hostnames {
+			if _, ok := evaluated[resource]; ok {
+				continue
+			}
+			for _, hostname := range resourceHostnames {
+				if _, ok := hostnames[hostname]; ok {
+					pending[resource] = struct{}{}
+					break
+				}
+			}
+		}
+	}
+
+	for resource := range evaluated {
+		delete(sc.hostnames, resource)
+	}
+	sc.addHostnames(endpoints)
+
+	return mergeServiceEndpoints(endpoints), nil
+}
+
+// addHostnames adds the DNS names of the endpoints to the hostnames of their services.
+func (sc *serviceSource) addHostnames(endpoints []*endpoint.Endpoint) {
+	if sc.hostnames == nil {
+		sc.hostnames = map[string][]string{}
+	}
+	for _, ep := range endpoints {
+		resource := ep.Labels[endpoint.ResourceLabelKey]
+		sc.hostnames[resource] = append(sc.hostnames[resource], ep.DNSName)
+	}
+}
+
+// endpointsFromServices returns the endpoints of the services matching the filters, before merging.
+func (sc *serviceSource) endpointsFromServices(services []*v1.Service) ([]*endpoint.Endpoint, error) {
+	services, err := sc.filterByAnnotations(services)
 	if err != nil {
 		return nil, err
 	}
@@ -197,9 +306,15 @@ func (sc *serviceSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, e
 
 		log.Debugf("Endpoints generated from service: %s/%s: %v", svc.Namespace, svc.Name, svcEndpoints)
 		sc.setResourceLabel(svc, svcEndpoints)
+		setMetadataLabels(svcEndpoints, svc.ObjectMeta)
 		endpoints = append(endpoints, svcEndpoints...)
 	}
 
+	return endpoints, nil
+}
+
+// mergeServiceEndpoints merges the targets of the endpoints of distinct services with the same DNS name.
+func mergeServiceEndpoints(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
 	// this sorting is required to make merging work.
 	// after we merge endpoints that have same DNS, we want to ensure that we end up with the same service being an "owner"
 	// of all those records, as otherwise each time we update, we will end up with a different service that gets data merged in
@@ -237,7 +352,7 @@ func (sc *serviceSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, e
 		sort.Sort(ep.Targets)
 	}
 
-	return endpoints, nil
+	return endpoints
 }
 
 // extractHeadlessEndpoints extracts endpoints from a headless service using the "Endpoints" Kubernetes API resource
@@ -393,25 +508,42 @@ func (sc *serviceSource) endpoints(svc *v1.Service) []*endpoint.Endpoint {
 	// Skip endpoints if we do not want entries from annotations
 	if !sc.ignoreHostnameAnnotation {
 		providerSpecific, setIdentifier := getProviderSpecificAnnotations(svc.Annotations)
+		access := getAccessFromAnnotations(svc.Annotations)
 		var hostnameList []string
 		var internalHostnameList []string
 
 		hostnameList = getHostnamesFromAnnotations(svc.Annotations)
 		for _, hostname := range hostnameList {
-			endpoints = append(endpoints, sc.generateEndpoints(svc, hostname, providerSpecific, setIdentifier, false)...)
+			endpoints = append(endpoints, setAccessLabel(sc.generateEndpoints(svc, hostname, providerSpecific, setIdentifier, false), access)...)
 		}
 
 		internalHostnameList = getInternalHostnamesFromAnnotations(svc.Annotations)
 		for _, hostname := range internalHostnameList {
-			endpoints = append(endpoints, sc.generateEndpoints(svc, hostname, providerSpecific, setIdentifier, true)...)
+			endpoints = append(endpoints, setAccessLabel(sc.generateEndpoints(svc, hostname, providerSpecific, setIdentifier, true), endpoint.AccessPrivate)...)
 		}
 	}
 	return endpoints
 }
 
+// UpdateFilters changes the annotation filter and the label selector of the services.
+func (sc *serviceSource) UpdateFilters(annotationFilter string, labelSelector labels.Selector) error {
+	if _, err := metav1.ParseToLabelSelector(annotationFilter); err != nil {
+		return err
+	}
+	sc.filterMutex.Lock()
+	defer sc.filterMutex.Unlock()
+	sc.annotationFilter = annotationFilter
+	sc.labelSelector = labelSelector
+	return nil
+}
+
 // filterByAnnotations filters a list of services by a given annotation selector.
 func (sc *serviceSource) filterByAnnotations(services []*v1.Service) ([]*v1.Service, error) {
-	labelSelector, err := metav1.ParseToLabelSelector(sc.annotationFilter)
+	sc.filterMutex.RLock()
+	annotationFilter := sc.annotationFilter
+	sc.filterMutex.RUnlock()
+
+	labelSelector, err := metav1.ParseToLabelSelector(annotationFilter)
 	if err != nil {
 		return nil, err
 	}
@@ -459,6 +591,20 @@ func (sc *serviceSource) setResourceLabel(service *v1.Service, endpoints []*endp
 	}
 }
 
+// setAccessLabel marks the endpoints as published publicly or privately, used to split them between providers.
+func setAccessLabel(endpoints []*endpoint.Endpoint, access string) []*endpoint.Endpoint {
+	if access != endpoint.AccessPublic && access != endpoint.AccessPrivate {
+		return endpoints
+	}
+	for _, ep := range endpoints {
+		if ep.Labels == nil {
+			ep.Labels = endpoint.NewLabels()
+		}
+		ep.Labels[endpoint.AccessLabelKey] = access
+	}
+	return endpoints
+}
+
 func (sc *serviceSource) generateEndpoints(svc *v1.Service, hostname string, providerSpecific endpoint.ProviderSpecific, setIdentifier string, useClusterIP bool) (endpoints []*endpoint.Endpoint) {
 	hostname = strings.TrimSuffix(hostname, ".")
 
@@ -680,7 +826,7 @@ func (sc *serviceSource) extractNodePortEndpoints(svc *v1.Service, hostname stri
 			// see https://en.wikipedia.org/wiki/SRV_record
 
 			// build a target with a priority of 0, weight of 50, and pointing the given port on the given host
-			target := fmt.Sprintf("0 50 %d %s", port.NodePort, hostname)
+			target := endpoint.SRVTarget{Priority: 0, Weight: 50, Port: uint16(port.NodePort), Target: hostname}.String()
 
 			// take the service name from the K8s Service object
 			// it is safe to use since it is DNS compatible
@@ -718,3 +864,93 @@ func (sc *serviceSource) AddEventHandler(ctx context.Context, handler func()) {
 	// https://github.com/kubernetes/kubernetes/issues/79610
 	sc.serviceInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
 }
+
+func (sc *serviceSource) AddResourceEventHandler(ctx context.Context, handler func(resource string)) {
+	log.Debug("Adding resource event handler for service")
+
+	sc.serviceInformer.Informer().AddEventHandler(resourceEventHandler{kind: "service", handler: handler})
+	// The endpoints of headless and NodePort services also depend on the Endpoints objects, named
+	// like their service, on the pods selected by the services and on the nodes.
+	sc.endpointsInformer.Informer().AddEventHandler(resourceEventHandler{kind: "service", handler: handler})
+	sc.podInformer.Informer().AddEventHandler(podServicesEventHandler{sc: sc, handler: handler})
+	sc.nodeInformer.Informer().AddEventHandler(nodeEventHandler(handler))
+}
+
+// podServicesEventHandler reports the services selecting the changed pods as changed resources.
+type podServicesEventHandler struct {
+	sc      *serviceSource
+	handler func(resource string)
+}
+
+func (h podServicesEventHandler) OnAdd(obj interface{}, isInInitialList bool) { h.notify(obj) }
+func (h podServicesEventHandler) OnDelete(obj interface{})                    { h.notify(obj) }
+
+// OnUpdate ignores the updates of pods which don't change the endpoints of their services, like the
+// frequent updates of their status by the kubelet.
+func (h podServicesEventHandler) OnUpdate(oldObj, newObj interface{}) {
+	oldPod, oldOK := oldObj.(*v1.Pod)
+	newPod, newOK := newObj.(*v1.Pod)
+	if oldOK && newOK && !podEndpointsChanged(oldPod, newPod) {
+		return
+	}
+	h.notify(oldObj, newObj)
+}
+
+// podEndpointsChanged returns whether the update of a pod may change the endpoints of the services
+// selecting it: its labels, target annotation, host name, node, phase, readiness, termination or addresses.
+func podEndpointsChanged(oldPod, newPod *v1.Pod) bool {
+	return !reflect.DeepEqual(oldPod.Labels, newPod.Labels) ||
+		oldPod.Annotations[targetAnnotationKey] != newPod.Annotations[targetAnnotationKey] ||
+		oldPod.Spec.Hostname != newPod.Spec.Hostname ||
+		oldPod.Spec.NodeName != newPod.Spec.NodeName ||
+		oldPod.Status.Phase != newPod.Status.Phase ||
+		isPodStatusReady(oldPod.Status) != isPodStatusReady(newPod.Status) ||
+		(oldPod.DeletionTimestamp == nil) != (newPod.DeletionTimestamp == nil) ||
+		oldPod.Status.HostIP != newPod.Status.HostIP ||
+		oldPod.Status.PodIP != newPod.Status.PodIP ||
+		!reflect.DeepEqual(oldPod.Status.PodIPs, newPod.Status.PodIPs)
+}
+
+// notify reports the services selecting any of the pods, or all resources if they can't be determined.
+func (h podServicesEventHandler) notify(objs ...interface{}) {
+	resources := map[string]bool{}
+	for _, obj := range objs {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		pod, ok := obj.(*v1.Pod)
+		if !ok {
+			h.handler("")
+			return
+		}
+		services, err := h.sc.serviceInformer.Lister().Services(pod.Namespace).List(labels.Everything())
+		if err != nil {
+			h.handler("")
+			return
+		}
+		for _, svc := range services {
+			if len(svc.Spec.Selector) > 0 && labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(pod.Labels)) {
+				resources[fmt.Sprintf("service/%s/%s", svc.Namespace, svc.Name)] = true
+			}
+		}
+	}
+	for resource := range resources {
+		h.handler(resource)
+	}
+}
+
+// nodeEventHandler reports the changes of the nodes and of their addresses as changes of all
+// resources, the services depending on a node are not tracked.
+type nodeEventHandler func(resource string)
+
+func (h nodeEventHandler) OnAdd(obj interface{}, isInInitialList bool) { h("") }
+func (h nodeEventHandler) OnDelete(obj interface{})                    { h("") }
+
+func (h nodeEventHandler) OnUpdate(oldObj, newObj interface{}) {
+	oldNode, oldOK := oldObj.(*v1.Node)
+	newNode, newOK := newObj.(*v1.Node)
+	if oldOK && newOK && reflect.DeepEqual(oldNode.Status.Addresses, newNode.Status.Addresses) {
+		return
+	}
+	h("")
+}
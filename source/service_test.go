@@ -22,6 +22,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -2015,7 +2016,7 @@ func TestServiceSourceNodePortServices(t *testing.T) {
 			},
 			expected: []*endpoint.Endpoint{
 				{DNSName: "_foo._tcp.foo.example.org", Targets: endpoint.Targets{"0 50 30192 foo.example.org"}, RecordType: endpoint.RecordTypeSRV},
-				{DNSName: "foo.example.org", Targets: endpoint.Targets{"10.0.1.1", "10.0.1.2"}, RecordType: endpoint.RecordTypeA},
+				{DNSName: "foo.example.org", Targets: endpoint.Targets{"10.0.1.1", "10.0.1.2"}, RecordType: endpoint.RecordTypeA, Labels: endpoint.Labels{endpoint.AccessLabelKey: endpoint.AccessPrivate, endpoint.ResourceLabelKey: "service/testing/foo"}},
 				{DNSName: "foo.example.org", Targets: endpoint.Targets{"2001:DB8::1", "2001:DB8::2"}, RecordType: endpoint.RecordTypeAAAA},
 			},
 			nodes: []*v1.Node{{
@@ -2055,7 +2056,7 @@ func TestServiceSourceNodePortServices(t *testing.T) {
 			},
 			expected: []*endpoint.Endpoint{
 				{DNSName: "_foo._tcp.foo.example.org", Targets: endpoint.Targets{"0 50 30192 foo.example.org"}, RecordType: endpoint.RecordTypeSRV},
-				{DNSName: "foo.example.org", Targets: endpoint.Targets{"54.10.11.1", "54.10.11.2"}, RecordType: endpoint.RecordTypeA},
+				{DNSName: "foo.example.org", Targets: endpoint.Targets{"54.10.11.1", "54.10.11.2"}, RecordType: endpoint.RecordTypeA, Labels: endpoint.Labels{endpoint.AccessLabelKey: endpoint.AccessPublic, endpoint.ResourceLabelKey: "service/testing/foo"}},
 				{DNSName: "foo.example.org", Targets: endpoint.Targets{"2001:DB8::1", "2001:DB8::2"}, RecordType: endpoint.RecordTypeAAAA},
 			},
 			nodes: []*v1.Node{{
@@ -3770,3 +3771,170 @@ func BenchmarkServiceEndpoints(b *testing.B) {
 		require.NoError(b, err)
 	}
 }
+
+func TestServiceSourceResourceEventHandler(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := fakeClient.CoreV1().Services("default").Create(ctx, &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+		Spec:       v1.ServiceSpec{Type: v1.ServiceTypeClusterIP, ClusterIP: v1.ClusterIPNone, Selector: map[string]string{"app": "web"}},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	src, err := NewServiceSource(ctx, fakeClient, "", "", "", false, "", false, false, false, []string{}, false, labels.Everything(), false)
+	require.NoError(t, err)
+
+	resources := make(chan string, 10)
+	src.(ResourceEventNotifier).AddResourceEventHandler(ctx, func(resource string) { resources <- resource })
+	expectResource := func(expected string) {
+		t.Helper()
+		select {
+		case resource := <-resources:
+			assert.Equal(t, expected, resource)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected a resource event for %q", expected)
+		}
+	}
+	expectResource("service/default/web")
+
+	for _, tc := range []struct {
+		title    string
+		create   func() error
+		expected string
+	}{
+		{
+			title: "pods are mapped to the services selecting them",
+			create: func() error {
+				_, err := fakeClient.CoreV1().Pods("default").Create(ctx, &v1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-1", Labels: map[string]string{"app": "web"}},
+				}, metav1.CreateOptions{})
+				return err
+			},
+			expected: "service/default/web",
+		},
+		{
+			title: "endpoints are mapped to their service",
+			create: func() error {
+				_, err := fakeClient.CoreV1().Endpoints("default").Create(ctx, &v1.Endpoints{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+				}, metav1.CreateOptions{})
+				return err
+			},
+			expected: "service/default/web",
+		},
+		{
+			title: "nodes change all resources",
+			create: func() error {
+				_, err := fakeClient.CoreV1().Nodes().Create(ctx, &v1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+				}, metav1.CreateOptions{})
+				return err
+			},
+			expected: "",
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			require.NoError(t, tc.create())
+			expectResource(tc.expected)
+		})
+	}
+
+	expectNoResource := func() {
+		t.Helper()
+		select {
+		case resource := <-resources:
+			t.Fatalf("unexpected resource event for %q", resource)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	_, err = fakeClient.CoreV1().Pods("default").Create(ctx, &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "db-1", Labels: map[string]string{"app": "db"}},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	expectNoResource()
+
+	// The updates of pods which don't change the endpoints of their services are ignored.
+	pod, err := fakeClient.CoreV1().Pods("default").Get(ctx, "web-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	pod.Annotations = map[string]string{"example.com/checksum": "1"}
+	pod, err = fakeClient.CoreV1().Pods("default").Update(ctx, pod, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	expectNoResource()
+
+	pod.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}
+	_, err = fakeClient.CoreV1().Pods("default").UpdateStatus(ctx, pod, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	expectResource("service/default/web")
+}
+
+func TestServiceSourceResourceEndpoints(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, svc := range []struct {
+		name     string
+		hostname string
+		ip       string
+	}{
+		{name: "a", hostname: "shared.example.com", ip: "1.1.1.1"},
+		{name: "b", hostname: "shared.example.com", ip: "2.2.2.2"},
+		{name: "c", hostname: "other.example.com", ip: "3.3.3.3"},
+	} {
+		_, err := fakeClient.CoreV1().Services("default").Create(ctx, &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "default",
+				Name:        svc.name,
+				Annotations: map[string]string{hostnameAnnotationKey: svc.hostname},
+			},
+			Spec: v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+			Status: v1.ServiceStatus{
+				LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: svc.ip}}},
+			},
+		}, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	src, err := NewServiceSource(ctx, fakeClient, "", "", "", false, "", false, false, false, []string{}, false, labels.Everything(), false)
+	require.NoError(t, err)
+
+	// resourceEndpoints returns the endpoints of the given DNS name from a full evaluation.
+	resourceEndpoints := func(dnsName string) []*endpoint.Endpoint {
+		t.Helper()
+		endpoints, err := src.Endpoints(ctx)
+		require.NoError(t, err)
+		result := []*endpoint.Endpoint{}
+		for _, ep := range endpoints {
+			if ep.DNSName == dnsName {
+				result = append(result, ep)
+			}
+		}
+		return result
+	}
+
+	// The services sharing a DNS name with the changed service are evaluated with it.
+	expected := resourceEndpoints("shared.example.com")
+	require.Len(t, expected, 1)
+	assert.Len(t, expected[0].Targets, 2)
+	endpoints, err := src.(ResourceEventNotifier).ResourceEndpoints(ctx, map[string]struct{}{"service/default/b": {}})
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, expected)
+
+	// The services which shared a DNS name with a deleted service are evaluated without it.
+	owner := expected[0].Labels[endpoint.ResourceLabelKey]
+	name := strings.TrimPrefix(owner, "service/default/")
+	require.NoError(t, fakeClient.CoreV1().Services("default").Delete(ctx, name, metav1.DeleteOptions{}))
+	require.Eventually(t, func() bool {
+		_, err := src.(*serviceSource).serviceInformer.Lister().Services("default").Get(name)
+		return err != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	endpoints, err = src.(ResourceEventNotifier).ResourceEndpoints(ctx, map[string]struct{}{owner: {}})
+	require.NoError(t, err)
+	expected = resourceEndpoints("shared.example.com")
+	require.Len(t, expected, 1)
+	assert.Len(t, expected[0].Targets, 1)
+	validateEndpoints(t, endpoints, expected)
+}
@@ -73,7 +73,7 @@ func validateEndpoint(t *testing.T, endpoint, expected *endpoint.Endpoint) {
 		t.Errorf("DNSName expected %q, got %q", expected.DNSName, endpoint.DNSName)
 	}
 
-	if !endpoint.Targets.Same(expected.Targets) {
+	if !endpoint.Targets.SameAs(expected.RecordType, expected.Targets) {
 		t.Errorf("Targets expected %q, got %q", expected.Targets, endpoint.Targets)
 	}
 
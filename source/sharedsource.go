@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// sharedSource is a Source evaluating the endpoints of its wrapped source once for the controllers
+// of several providers. The endpoints are reused until the wrapped source reports a change, or until
+// they are older than maxAge.
+type sharedSource struct {
+	source Source
+	maxAge time.Duration
+
+	mutex     sync.Mutex
+	endpoints []*endpoint.Endpoint
+	evaluated time.Time
+	valid     bool
+}
+
+// NewSharedSource creates a new sharedSource wrapping the provided Source.
+func NewSharedSource(source Source, maxAge time.Duration) Source {
+	return &sharedSource{source: source, maxAge: maxAge}
+}
+
+// Endpoints returns copies of the endpoints of its wrapped source, which are only evaluated again
+// if they changed or are older than maxAge. Concurrent calls wait for the same evaluation.
+func (s *sharedSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.valid && time.Since(s.evaluated) < s.maxAge {
+		log.Debug("Reusing the endpoints of the sources evaluated for another provider")
+	} else {
+		endpoints, err := s.source.Endpoints(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.endpoints, s.evaluated, s.valid = endpoints, time.Now(), true
+	}
+
+	// Every caller gets its own copies, which it can modify.
+	result := make([]*endpoint.Endpoint, 0, len(s.endpoints))
+	for _, ep := range s.endpoints {
+		result = append(result, ep.DeepCopy())
+	}
+	return result, nil
+}
+
+// ResourceEndpoints returns the endpoints of the resources of its wrapped source. They are evaluated
+// for every caller, as the callers synchronize distinct changed resources.
+func (s *sharedSource) ResourceEndpoints(ctx context.Context, resources map[string]struct{}) ([]*endpoint.Endpoint, error) {
+	return ResourceEndpoints(ctx, s.source, resources)
+}
+
+// invalidate requires the next call to Endpoints to evaluate the wrapped source.
+func (s *sharedSource) invalidate() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.valid = false
+}
+
+func (s *sharedSource) AddEventHandler(ctx context.Context, handler func()) {
+	s.source.AddEventHandler(ctx, func() {
+		s.invalidate()
+		handler()
+	})
+}
+
+func (s *sharedSource) AddResourceEventHandler(ctx context.Context, handler func(resource string)) {
+	AddResourceEventHandler(ctx, s.source, func(resource string) {
+		s.invalidate()
+		handler(resource)
+	})
+}
+
+func (s *sharedSource) UpdateFilters(annotationFilter string, labelSelector labels.Selector) error {
+	s.invalidate()
+	return UpdateFilters(s.source, annotationFilter, labelSelector)
+}
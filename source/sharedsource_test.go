@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// countingSource is a Source counting the evaluations of its endpoints and calling its event handlers on demand.
+type countingSource struct {
+	endpoints   []*endpoint.Endpoint
+	err         error
+	evaluations int
+	handlers    []func()
+}
+
+func (s *countingSource) Endpoints(context.Context) ([]*endpoint.Endpoint, error) {
+	s.evaluations++
+	return s.endpoints, s.err
+}
+
+func (s *countingSource) AddEventHandler(_ context.Context, handler func()) {
+	s.handlers = append(s.handlers, handler)
+}
+
+func (s *countingSource) changed() {
+	for _, handler := range s.handlers {
+		handler()
+	}
+}
+
+// TestSharedSourceImplementsSource tests that sharedSource is a valid Source.
+func TestSharedSourceImplementsSource(t *testing.T) {
+	var _ Source = &sharedSource{}
+}
+
+func TestSharedSourceEndpoints(t *testing.T) {
+	ctx := context.Background()
+	wrapped := &countingSource{endpoints: []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "1.2.3.4")}}
+	shared := NewSharedSource(wrapped, time.Hour)
+
+	handled := 0
+	shared.AddEventHandler(ctx, func() { handled++ })
+
+	first, err := shared.Endpoints(ctx)
+	require.NoError(t, err)
+	second, err := shared.Endpoints(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, wrapped.evaluations, "the endpoints are evaluated once")
+
+	// the callers get their own copies
+	first[0].Targets = endpoint.Targets{"5.6.7.8"}
+	assert.Equal(t, endpoint.Targets{"1.2.3.4"}, second[0].Targets)
+	assert.Equal(t, endpoint.Targets{"1.2.3.4"}, wrapped.endpoints[0].Targets)
+
+	// a change of the source requires a new evaluation
+	wrapped.changed()
+	assert.Equal(t, 1, handled)
+	_, err = shared.Endpoints(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, wrapped.evaluations)
+}
+
+func TestSharedSourceMaxAge(t *testing.T) {
+	ctx := context.Background()
+	wrapped := &countingSource{}
+	shared := NewSharedSource(wrapped, 0)
+
+	for i := 0; i < 2; i++ {
+		_, err := shared.Endpoints(ctx)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, wrapped.evaluations)
+}
+
+func TestSharedSourceDoesNotReuseErrors(t *testing.T) {
+	ctx := context.Background()
+	wrapped := &countingSource{err: errors.New("source failed")}
+	shared := NewSharedSource(wrapped, time.Hour)
+
+	_, err := shared.Endpoints(ctx)
+	assert.Error(t, err)
+
+	wrapped.err = nil
+	_, err = shared.Endpoints(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, wrapped.evaluations)
+}
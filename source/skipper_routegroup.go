@@ -34,6 +34,7 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"sigs.k8s.io/external-dns/endpoint"
 )
@@ -281,6 +282,7 @@ func (sc *routeGroupSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint
 
 		log.Debugf("Endpoints generated from ingress: %s/%s: %v", rg.Metadata.Namespace, rg.Metadata.Name, eps)
 		sc.setRouteGroupDualstackLabel(rg, eps)
+		setMetadataLabels(eps, metav1.ObjectMeta{Namespace: rg.Metadata.Namespace, Name: rg.Metadata.Name, Annotations: rg.Metadata.Annotations})
 		endpoints = append(endpoints, eps...)
 	}
 
@@ -34,8 +34,10 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
 
 	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
 )
 
 const (
@@ -60,6 +62,12 @@ const (
 	controllerAnnotationValue = "dns-controller"
 	// The annotation used for defining the desired hostname
 	internalHostnameAnnotationKey = "external-dns.alpha.kubernetes.io/internal-hostname"
+	// The annotation used for defining the priority of the resource when several resources claim the same hostname
+	priorityAnnotationKey = "external-dns.alpha.kubernetes.io/priority"
+	// The annotation used for defining the policy applied to the records of the resource
+	policyAnnotationKey = "external-dns.alpha.kubernetes.io/policy"
+	// The annotation used for requesting the CNAME records of the resource to be flattened to A and AAAA records
+	flattenAnnotationKey = "external-dns.alpha.kubernetes.io/flatten"
 )
 
 const (
@@ -87,6 +95,61 @@ type Source interface {
 	AddEventHandler(context.Context, func())
 }
 
+// ResourceEventNotifier is implemented by sources which can report which resources changed.
+type ResourceEventNotifier interface {
+	// AddResourceEventHandler adds an event handler called with the resource of every changed object,
+	// formatted like the endpoint.ResourceLabelKey label of its endpoints, e.g. "ingress/default/web".
+	// An empty resource reports a change of unknown resources.
+	AddResourceEventHandler(ctx context.Context, handler func(resource string))
+	// ResourceEndpoints returns the endpoints of the given resources, without evaluating the other
+	// resources. Sources merging the endpoints of several resources also return all endpoints of the
+	// resources merged with the given ones.
+	ResourceEndpoints(ctx context.Context, resources map[string]struct{}) ([]*endpoint.Endpoint, error)
+}
+
+// AddResourceEventHandler adds handler to source. Changes of sources which can't report
+// which resources changed are reported as changes of unknown resources.
+func AddResourceEventHandler(ctx context.Context, source Source, handler func(resource string)) {
+	if notifier, ok := source.(ResourceEventNotifier); ok {
+		notifier.AddResourceEventHandler(ctx, handler)
+		return
+	}
+	source.AddEventHandler(ctx, func() { handler("") })
+}
+
+// ResourceEndpoints returns the endpoints of the given resources of source. The endpoints of sources
+// which can't evaluate single resources are evaluated in full and filtered by their resource.
+func ResourceEndpoints(ctx context.Context, source Source, resources map[string]struct{}) ([]*endpoint.Endpoint, error) {
+	if notifier, ok := source.(ResourceEventNotifier); ok {
+		return notifier.ResourceEndpoints(ctx, resources)
+	}
+	endpoints, err := source.Endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []*endpoint.Endpoint
+	for _, ep := range endpoints {
+		if _, ok := resources[ep.Labels[endpoint.ResourceLabelKey]]; ok {
+			result = append(result, ep)
+		}
+	}
+	return result, nil
+}
+
+// FilterUpdater is implemented by sources whose annotation filter and label selector can change while they run.
+type FilterUpdater interface {
+	UpdateFilters(annotationFilter string, labelSelector labels.Selector) error
+}
+
+// UpdateFilters changes the annotation filter and the label selector of source, which fails
+// when source can't change them while it runs.
+func UpdateFilters(source Source, annotationFilter string, labelSelector labels.Selector) error {
+	if updater, ok := source.(FilterUpdater); ok {
+		return updater.UpdateFilters(annotationFilter, labelSelector)
+	}
+	return fmt.Errorf("source %T does not support changing its filters", source)
+}
+
 func getTTLFromAnnotations(annotations map[string]string, resource string) endpoint.TTL {
 	ttlNotConfigured := endpoint.TTL(0)
 	ttlAnnotation, exists := annotations[ttlAnnotationKey]
@@ -165,6 +228,41 @@ func getAccessFromAnnotations(annotations map[string]string) string {
 	return annotations[accessAnnotationKey]
 }
 
+// setMetadataLabels sets the labels of the plan on the endpoints of the resource with the metadata meta: the policy
+// and priority annotations, and the creation timestamp.
+func setMetadataLabels(endpoints []*endpoint.Endpoint, meta metav1.ObjectMeta) {
+	priority, exists := meta.Annotations[priorityAnnotationKey]
+	if exists {
+		if _, err := strconv.Atoi(priority); err != nil {
+			log.Warnf("Ignoring the invalid priority %q of %s/%s: %v", priority, meta.Namespace, meta.Name, err)
+			exists = false
+		}
+	}
+	policy := meta.Annotations[policyAnnotationKey]
+	if policy != "" && !plan.IsValidPolicy(policy) {
+		log.Warnf("Ignoring the invalid policy %q of %s/%s", policy, meta.Namespace, meta.Name)
+		policy = ""
+	}
+	flatten, _ := strconv.ParseBool(meta.Annotations[flattenAnnotationKey])
+	for _, ep := range endpoints {
+		if ep.Labels == nil {
+			ep.Labels = endpoint.NewLabels()
+		}
+		if exists {
+			ep.Labels[endpoint.PriorityLabelKey] = priority
+		}
+		if policy != "" {
+			ep.Labels[endpoint.PolicyLabelKey] = policy
+		}
+		if flatten && ep.RecordType == endpoint.RecordTypeCNAME {
+			ep.Labels[endpoint.FlattenLabelKey] = "true"
+		}
+		if !meta.CreationTimestamp.IsZero() {
+			ep.Labels[endpoint.CreatedLabelKey] = meta.CreationTimestamp.UTC().Format(time.RFC3339)
+		}
+	}
+}
+
 func getEndpointsTypeFromAnnotations(annotations map[string]string) string {
 	return annotations[endpointsTypeAnnotationKey]
 }
@@ -348,6 +446,43 @@ func (fn eventHandlerFunc) OnAdd(obj interface{}, isInInitialList bool) { fn() }
 func (fn eventHandlerFunc) OnUpdate(oldObj, newObj interface{})         { fn() }
 func (fn eventHandlerFunc) OnDelete(obj interface{})                    { fn() }
 
+// resourceEventHandler reports the objects of an informer as changed resources of the given kind.
+type resourceEventHandler struct {
+	kind    string
+	handler func(resource string)
+}
+
+func (h resourceEventHandler) OnAdd(obj interface{}, isInInitialList bool) { h.notify(obj) }
+func (h resourceEventHandler) OnUpdate(oldObj, newObj interface{})         { h.notify(newObj) }
+func (h resourceEventHandler) OnDelete(obj interface{})                    { h.notify(obj) }
+
+func (h resourceEventHandler) notify(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		h.handler("")
+		return
+	}
+	h.handler(h.kind + "/" + key)
+}
+
+// resourceKeys returns the namespaces and names of the resources of kind, formatted like the
+// resources reported by resourceEventHandler.
+func resourceKeys(kind string, resources map[string]struct{}) []cache.ObjectName {
+	var keys []cache.ObjectName
+	for resource := range resources {
+		key, ok := strings.CutPrefix(resource, kind+"/")
+		if !ok {
+			continue
+		}
+		namespace, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			continue
+		}
+		keys = append(keys, cache.ObjectName{Namespace: namespace, Name: name})
+	}
+	return keys
+}
+
 type informerFactory interface {
 	WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool
 }
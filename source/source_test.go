@@ -19,8 +19,10 @@ package source
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"sigs.k8s.io/external-dns/endpoint"
 )
@@ -96,3 +98,29 @@ func TestSuitableType(t *testing.T) {
 		}
 	}
 }
+
+func TestSetMetadataLabels(t *testing.T) {
+	endpoints := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeCNAME, "bar.example.com"),
+	}
+	setMetadataLabels(endpoints, metav1.ObjectMeta{
+		Name:              "foo",
+		Annotations:       map[string]string{priorityAnnotationKey: "10", policyAnnotationKey: "create-only", flattenAnnotationKey: "true"},
+		CreationTimestamp: metav1.NewTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+	})
+	assert.Equal(t, "10", endpoints[0].Labels[endpoint.PriorityLabelKey])
+	assert.Equal(t, "2024-01-02T03:04:05Z", endpoints[0].Labels[endpoint.CreatedLabelKey])
+	assert.Equal(t, "create-only", endpoints[0].Labels[endpoint.PolicyLabelKey])
+	assert.NotContains(t, endpoints[0].Labels, endpoint.FlattenLabelKey)
+	assert.Equal(t, "true", endpoints[1].Labels[endpoint.FlattenLabelKey])
+
+	endpoints = []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "1.2.3.4")}
+	setMetadataLabels(endpoints, metav1.ObjectMeta{
+		Name:        "foo",
+		Annotations: map[string]string{priorityAnnotationKey: "high", policyAnnotationKey: "never"},
+	})
+	assert.NotContains(t, endpoints[0].Labels, endpoint.PriorityLabelKey)
+	assert.NotContains(t, endpoints[0].Labels, endpoint.CreatedLabelKey)
+	assert.NotContains(t, endpoints[0].Labels, endpoint.PolicyLabelKey)
+}
@@ -76,6 +76,8 @@ type Config struct {
 	ResolveLoadBalancerHostname    bool
 	TraefikDisableLegacy           bool
 	TraefikDisableNew              bool
+	ZoneFile                       string
+	ZoneFileOrigin                 string
 }
 
 // ClientGenerator provides clients
@@ -313,6 +315,8 @@ func BuildWithConfig(ctx context.Context, source string, p ClientGenerator, cfg
 		return NewFakeSource(cfg.FQDNTemplate)
 	case "connector":
 		return NewConnectorSource(cfg.ConnectorServer)
+	case "zone-file":
+		return NewZoneFileSource(cfg.ZoneFile, cfg.ZoneFileOrigin)
 	case "crd":
 		client, err := p.KubeClient()
 		if err != nil {
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"sigs.k8s.io/external-dns/endpoint"
 )
@@ -38,12 +39,25 @@ func NewTargetFilterSource(source Source, targetFilter endpoint.TargetFilterInte
 // Endpoints collects endpoints from its wrapped source and returns
 // them without targets matching the target filter.
 func (ms *targetFilterSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
-	result := []*endpoint.Endpoint{}
-
 	endpoints, err := ms.source.Endpoints(ctx)
 	if err != nil {
 		return nil, err
 	}
+	return ms.filter(endpoints), nil
+}
+
+// ResourceEndpoints collects the endpoints of the resources from its wrapped source and returns
+// them without targets matching the target filter.
+func (ms *targetFilterSource) ResourceEndpoints(ctx context.Context, resources map[string]struct{}) ([]*endpoint.Endpoint, error) {
+	endpoints, err := ResourceEndpoints(ctx, ms.source, resources)
+	if err != nil {
+		return nil, err
+	}
+	return ms.filter(endpoints), nil
+}
+
+func (ms *targetFilterSource) filter(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	result := []*endpoint.Endpoint{}
 
 	for _, ep := range endpoints {
 		filteredTargets := []string{}
@@ -65,9 +79,17 @@ func (ms *targetFilterSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoi
 		result = append(result, ep)
 	}
 
-	return result, nil
+	return result
 }
 
 func (ms *targetFilterSource) AddEventHandler(ctx context.Context, handler func()) {
 	ms.source.AddEventHandler(ctx, handler)
 }
+
+func (ms *targetFilterSource) AddResourceEventHandler(ctx context.Context, handler func(resource string)) {
+	AddResourceEventHandler(ctx, ms.source, handler)
+}
+
+func (ms *targetFilterSource) UpdateFilters(annotationFilter string, labelSelector labels.Selector) error {
+	return UpdateFilters(ms.source, annotationFilter, labelSelector)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// targetRewriteSource is a Source that rewrites the targets of the endpoints of its wrapped source.
+type targetRewriteSource struct {
+	source Source
+	rules  []endpoint.TargetRewriteRule
+}
+
+// NewTargetRewriteSource creates a new targetRewriteSource wrapping the provided Source.
+// The first rule applying to a target rewrites it.
+func NewTargetRewriteSource(source Source, rules []endpoint.TargetRewriteRule) Source {
+	return &targetRewriteSource{source: source, rules: rules}
+}
+
+// Endpoints collects endpoints from its wrapped source and returns
+// them with their targets rewritten by the rules.
+func (ms *targetRewriteSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	endpoints, err := ms.source.Endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ms.rewrite(endpoints)
+	return endpoints, nil
+}
+
+// ResourceEndpoints collects the endpoints of the resources from its wrapped source and returns
+// them with their targets rewritten by the rules.
+func (ms *targetRewriteSource) ResourceEndpoints(ctx context.Context, resources map[string]struct{}) ([]*endpoint.Endpoint, error) {
+	endpoints, err := ResourceEndpoints(ctx, ms.source, resources)
+	if err != nil {
+		return nil, err
+	}
+	ms.rewrite(endpoints)
+	return endpoints, nil
+}
+
+func (ms *targetRewriteSource) rewrite(endpoints []*endpoint.Endpoint) {
+	if len(ms.rules) == 0 {
+		return
+	}
+
+	for _, ep := range endpoints {
+		rewrittenTargets := make(endpoint.Targets, 0, len(ep.Targets))
+		seen := map[string]bool{}
+
+		for _, t := range ep.Targets {
+			for _, rule := range ms.rules {
+				if rewritten, ok := rule.Rewrite(ep.RecordType, t); ok {
+					log.WithField("endpoint", ep).Debugf("Rewriting target %s to %s following %s", t, rewritten, rule)
+					t = rewritten
+					break
+				}
+			}
+			// Targets rewritten to the same target are merged.
+			if !seen[t] {
+				seen[t] = true
+				rewrittenTargets = append(rewrittenTargets, t)
+			}
+		}
+
+		ep.Targets = rewrittenTargets
+	}
+}
+
+func (ms *targetRewriteSource) AddEventHandler(ctx context.Context, handler func()) {
+	ms.source.AddEventHandler(ctx, handler)
+}
+
+func (ms *targetRewriteSource) AddResourceEventHandler(ctx context.Context, handler func(resource string)) {
+	AddResourceEventHandler(ctx, ms.source, handler)
+}
+
+func (ms *targetRewriteSource) UpdateFilters(annotationFilter string, labelSelector labels.Selector) error {
+	return UpdateFilters(ms.source, annotationFilter, labelSelector)
+}
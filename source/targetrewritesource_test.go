@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// TestTargetRewriteSourceImplementsSource tests that targetRewriteSource is a valid Source.
+func TestTargetRewriteSourceImplementsSource(t *testing.T) {
+	var _ Source = &targetRewriteSource{}
+}
+
+func TestTargetRewriteSourceEndpoints(t *testing.T) {
+	rules, err := endpoint.ParseTargetRewriteRules([]string{
+		"*.lb.internal=cdn.example.com",
+		"10.0.0.0/24=203.0.113.0/24",
+		"10.0.0.0/16=198.51.0.0/16",
+	})
+	require.NoError(t, err)
+
+	source := NewTargetRewriteSource(NewEchoSource([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("shop.example.com", endpoint.RecordTypeCNAME, "shop.lb.internal"),
+		endpoint.NewEndpoint("www.example.com", endpoint.RecordTypeCNAME, "www.example.net"),
+		endpoint.NewEndpoint("api.example.com", endpoint.RecordTypeA, "10.0.0.1", "10.0.1.1", "192.0.2.1"),
+		endpoint.NewEndpoint("lb.example.com", endpoint.RecordTypeTXT, "shop.lb.internal"),
+	}), rules)
+
+	endpoints, err := source.Endpoints(context.Background())
+	require.NoError(t, err)
+	require.Len(t, endpoints, 4)
+	assert.Equal(t, endpoint.Targets{"cdn.example.com"}, endpoints[0].Targets)
+	assert.Equal(t, endpoint.Targets{"www.example.net"}, endpoints[1].Targets)
+	// the first matching rule applies
+	assert.Equal(t, endpoint.Targets{"203.0.113.1", "198.51.1.1", "192.0.2.1"}, endpoints[2].Targets)
+	assert.Equal(t, endpoint.Targets{"shop.lb.internal"}, endpoints[3].Targets)
+}
+
+func TestTargetRewriteSourceMergesTargets(t *testing.T) {
+	rules, err := endpoint.ParseTargetRewriteRules([]string{"*.lb.internal=cdn.example.com"})
+	require.NoError(t, err)
+
+	source := NewTargetRewriteSource(NewEchoSource([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("shop.example.com", endpoint.RecordTypeCNAME, "a.lb.internal", "b.lb.internal"),
+	}), rules)
+
+	endpoints, err := source.Endpoints(context.Background())
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, endpoint.Targets{"cdn.example.com"}, endpoints[0].Targets)
+}
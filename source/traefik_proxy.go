@@ -270,6 +270,7 @@ func (ts *traefikSource) ingressRouteEndpoints() ([]*endpoint.Endpoint, error) {
 
 		log.Debugf("Endpoints generated from IngressRoute: %s: %v", fullname, ingressEndpoints)
 		ts.setDualstackLabelIngressRoute(ingressRoute, ingressEndpoints)
+		setMetadataLabels(ingressEndpoints, ingressRoute.ObjectMeta)
 		endpoints = append(endpoints, ingressEndpoints...)
 	}
 
@@ -323,6 +324,7 @@ func (ts *traefikSource) ingressRouteTCPEndpoints() ([]*endpoint.Endpoint, error
 
 		log.Debugf("Endpoints generated from IngressRouteTCP: %s: %v", fullname, ingressEndpoints)
 		ts.setDualstackLabelIngressRouteTCP(ingressRouteTCP, ingressEndpoints)
+		setMetadataLabels(ingressEndpoints, ingressRouteTCP.ObjectMeta)
 		endpoints = append(endpoints, ingressEndpoints...)
 	}
 
@@ -376,6 +378,7 @@ func (ts *traefikSource) ingressRouteUDPEndpoints() ([]*endpoint.Endpoint, error
 
 		log.Debugf("Endpoints generated from IngressRouteUDP: %s: %v", fullname, ingressEndpoints)
 		ts.setDualstackLabelIngressRouteUDP(ingressRouteUDP, ingressEndpoints)
+		setMetadataLabels(ingressEndpoints, ingressRouteUDP.ObjectMeta)
 		endpoints = append(endpoints, ingressEndpoints...)
 	}
 
@@ -429,6 +432,7 @@ func (ts *traefikSource) oldIngressRouteEndpoints() ([]*endpoint.Endpoint, error
 
 		log.Debugf("Endpoints generated from IngressRoute: %s: %v", fullname, ingressEndpoints)
 		ts.setDualstackLabelIngressRoute(ingressRoute, ingressEndpoints)
+		setMetadataLabels(ingressEndpoints, ingressRoute.ObjectMeta)
 		endpoints = append(endpoints, ingressEndpoints...)
 	}
 
@@ -482,6 +486,7 @@ func (ts *traefikSource) oldIngressRouteTCPEndpoints() ([]*endpoint.Endpoint, er
 
 		log.Debugf("Endpoints generated from IngressRouteTCP: %s: %v", fullname, ingressEndpoints)
 		ts.setDualstackLabelIngressRouteTCP(ingressRouteTCP, ingressEndpoints)
+		setMetadataLabels(ingressEndpoints, ingressRouteTCP.ObjectMeta)
 		endpoints = append(endpoints, ingressEndpoints...)
 	}
 
@@ -535,6 +540,7 @@ func (ts *traefikSource) oldIngressRouteUDPEndpoints() ([]*endpoint.Endpoint, er
 
 		log.Debugf("Endpoints generated from IngressRouteUDP: %s: %v", fullname, ingressEndpoints)
 		ts.setDualstackLabelIngressRouteUDP(ingressRouteUDP, ingressEndpoints)
+		setMetadataLabels(ingressEndpoints, ingressRouteUDP.ObjectMeta)
 		endpoints = append(endpoints, ingressEndpoints...)
 	}
 
@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/zonefile"
+)
+
+// zoneFileSource is an implementation of Source that provides the records of a zone file
+// in the RFC 1035 format as endpoints. The file is read on every synchronization.
+type zoneFileSource struct {
+	path   string
+	origin string
+}
+
+// NewZoneFileSource creates a new zoneFileSource reading the zone file at path, whose relative
+// names are relative to origin unless the file sets its own.
+func NewZoneFileSource(path, origin string) (Source, error) {
+	if path == "" {
+		return nil, errors.New("the zone-file source requires --zone-file")
+	}
+	return &zoneFileSource{
+		path:   path,
+		origin: origin,
+	}, nil
+}
+
+// Endpoints returns the endpoints of the records of the zone file.
+func (zs *zoneFileSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	f, err := os.Open(zs.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	endpoints, err := zonefile.Read(f, zs.origin)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", zs.path, err)
+	}
+	return endpoints, nil
+}
+
+func (zs *zoneFileSource) AddEventHandler(ctx context.Context, handler func()) {
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestZoneFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "example.com.zone")
+	require.NoError(t, os.WriteFile(path, []byte("www 60 IN A 1.2.3.4\napi 120 IN CNAME www\n"), 0o600))
+
+	_, err := NewZoneFileSource("", "example.com")
+	assert.Error(t, err)
+
+	src, err := NewZoneFileSource(path, "example.com")
+	require.NoError(t, err)
+	endpoints, err := src.Endpoints(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("www.example.com", endpoint.RecordTypeA, 60, "1.2.3.4"),
+		endpoint.NewEndpointWithTTL("api.example.com", endpoint.RecordTypeCNAME, 120, "www.example.com"),
+	})
+
+	require.NoError(t, os.WriteFile(path, []byte("www IN A invalid\n"), 0o600))
+	_, err = src.Endpoints(context.Background())
+	assert.ErrorContains(t, err, "invalid zone file")
+}